@@ -0,0 +1,662 @@
+// app/controllers/api_controller.go - Versioned JSON REST API (/api/v1)
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// APIController exposes blogs and users as JSON resources
+type APIController struct {
+	*Controller
+	BlogModel models.BlogRepository
+	UserModel models.UserRepository
+	RoleModel *models.RoleModel
+	Notifier  *reviewNotifier
+}
+
+// NewAPIController creates a new APIController
+func NewAPIController(app *bootstrap.App) *APIController {
+	return &APIController{
+		Controller: NewController(app),
+		BlogModel:  app.Blogs,
+		UserModel:  app.Users,
+		RoleModel:  app.Roles,
+		Notifier:   newReviewNotifier(app),
+	}
+}
+
+// blogRequest is the JSON body accepted when creating or updating a blog
+type blogRequest struct {
+	Title   *string `json:"title"`
+	Slug    *string `json:"slug"`
+	Content *string `json:"content"`
+	Format  *string `json:"format"`
+	Excerpt *string `json:"excerpt"`
+	Status  *string `json:"status"`
+	// PublishAt is an RFC 3339 time, required when Status is "scheduled"
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// userRequest is the JSON body accepted when creating or updating a user
+type userRequest struct {
+	Name     *string `json:"name"`
+	Email    *string `json:"email"`
+	Role     *string `json:"role"`
+	Password *string `json:"password"`
+}
+
+// ListBlogs returns published blogs with pagination metadata
+func (c *APIController) ListBlogs(w http.ResponseWriter, r *http.Request) {
+	page, perPage := apiPagination(r)
+
+	blogs, err := c.BlogModel.GetAll(r.Context(), perPage, (page-1)*perPage)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to load blogs")
+		return
+	}
+
+	total, err := c.BlogModel.CountPublished(r.Context())
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to count blogs")
+		return
+	}
+
+	if blogs == nil {
+		blogs = []*models.Blog{}
+	}
+	respondPaginated(w, blogs, newPaginationMeta(page, perPage, total))
+}
+
+// ShowBlog returns a single blog; unpublished posts are only visible to their owner or users with blogs.edit_any
+func (c *APIController) ShowBlog(w http.ResponseWriter, r *http.Request) {
+	id, ok := apiIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	blog, err := c.BlogModel.GetByID(r.Context(), id)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusNotFound, "not_found", "Blog not found")
+		return
+	}
+
+	if !blog.IsPublished() {
+		user, _ := middleware.GetCurrentUser(r)
+		if !policies.CanViewBlog(user, blog) {
+			respondError(w, http.StatusNotFound, "not_found", "Blog not found")
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"data": blog})
+}
+
+// CreateBlog creates a blog owned by the authenticated user
+func (c *APIController) CreateBlog(w http.ResponseWriter, r *http.Request) {
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil || user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+		return
+	}
+
+	if !policies.CanCreateBlog(user) {
+		respondError(w, http.StatusForbidden, "forbidden", "Missing the '"+models.PermBlogsCreate+"' permission")
+		return
+	}
+
+	if !user.IsVerified() {
+		respondError(w, http.StatusForbidden, "email_unverified", "Verify your email address before creating blogs")
+		return
+	}
+
+	var req blogRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	title := strings.TrimSpace(stringValue(req.Title))
+	content := strings.TrimSpace(stringValue(req.Content))
+	excerpt := strings.TrimSpace(stringValue(req.Excerpt))
+	status := strings.TrimSpace(stringValue(req.Status))
+	format := strings.TrimSpace(stringValue(req.Format))
+
+	if excerpt == "" {
+		excerpt = generateExcerpt(content)
+	}
+	if status == "" {
+		status = defaultBlogStatus(user)
+	}
+
+	fields := map[string]string{}
+	if title == "" {
+		fields["title"] = "Title is required"
+	}
+	if content == "" {
+		fields["content"] = "Content is required"
+	}
+	if !models.IsValidBlogStatus(status) {
+		fields["status"] = "Status must be draft, pending_review, scheduled or published"
+	} else if !policies.CanSetBlogStatus(user, models.BlogDraft, status) {
+		fields["status"] = statusDenied(status)
+	} else if msg := validateSchedule(status, req.PublishAt); msg != "" {
+		fields["publish_at"] = msg
+	}
+	if format != "" && !services.IsValidFormat(format) {
+		fields["format"] = "Format must be markdown or plain"
+	}
+	if len(fields) > 0 {
+		respondValidationError(w, fields)
+		return
+	}
+
+	blog, err := c.BlogModel.CreateFrom(r.Context(), models.BlogInput{
+		Title:     title,
+		Slug:      strings.TrimSpace(stringValue(req.Slug)),
+		Content:   content,
+		Format:    format,
+		Excerpt:   excerpt,
+		Status:    status,
+		UserID:    user.ID,
+		PublishAt: req.PublishAt,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "slug already exists") {
+			respondValidationError(w, map[string]string{"slug": "Slug is already in use"})
+			return
+		}
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to create blog")
+		return
+	}
+
+	c.Notifier.statusChanged(r, blog, models.BlogDraft, user, "")
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{"data": blog})
+}
+
+// UpdateBlog applies a partial update to a blog owned by the authenticated user
+func (c *APIController) UpdateBlog(w http.ResponseWriter, r *http.Request) {
+	id, ok := apiIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil || user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+		return
+	}
+
+	blog, err := c.BlogModel.GetByID(r.Context(), id)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusNotFound, "not_found", "Blog not found")
+		return
+	}
+	if !canEditBlog(r, user, blog) {
+		respondError(w, http.StatusForbidden, "forbidden", "You don't have permission to edit this blog")
+		return
+	}
+
+	var req blogRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Only overwrite the fields that were sent
+	title, content, excerpt, status, format := blog.Title, blog.Content, blog.Excerpt, blog.Status, blog.Format
+	publishAt := blog.PublishAt
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+	}
+	if req.Content != nil {
+		content = strings.TrimSpace(*req.Content)
+	}
+	if req.Excerpt != nil {
+		excerpt = strings.TrimSpace(*req.Excerpt)
+		if excerpt == "" {
+			excerpt = generateExcerpt(content)
+		}
+	}
+	if req.Status != nil {
+		status = strings.TrimSpace(*req.Status)
+	}
+	if req.Format != nil {
+		format = strings.TrimSpace(*req.Format)
+	}
+	if req.PublishAt != nil {
+		publishAt = req.PublishAt
+	}
+	status = policies.EditedBlogStatus(user, blog.Status, status)
+
+	fields := map[string]string{}
+	if title == "" {
+		fields["title"] = "Title is required"
+	}
+	if content == "" {
+		fields["content"] = "Content is required"
+	}
+	if !models.IsValidBlogStatus(status) {
+		fields["status"] = "Status must be draft, pending_review, scheduled or published"
+	} else if !policies.CanSetBlogStatus(user, blog.Status, status) {
+		fields["status"] = statusDenied(status)
+	} else if req.Status != nil || req.PublishAt != nil {
+		// An unchanged schedule is left alone, even if it is already due
+		if msg := validateSchedule(status, publishAt); msg != "" {
+			fields["publish_at"] = msg
+		}
+	}
+	if !services.IsValidFormat(format) {
+		fields["format"] = "Format must be markdown or plain"
+	}
+	if len(fields) > 0 {
+		respondValidationError(w, fields)
+		return
+	}
+
+	updated, err := c.BlogModel.UpdateFrom(r.Context(), id, models.BlogInput{
+		Title:     title,
+		Slug:      strings.TrimSpace(stringValue(req.Slug)),
+		Content:   content,
+		Format:    format,
+		Excerpt:   excerpt,
+		Status:    status,
+		PublishAt: publishAt,
+		EditorID:  user.ID,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "slug already exists") {
+			respondValidationError(w, map[string]string{"slug": "Slug is already in use"})
+			return
+		}
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to update blog")
+		return
+	}
+
+	c.Notifier.statusChanged(r, updated, blog.Status, user, "")
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"data": updated})
+}
+
+// DeleteBlog deletes a blog owned by the authenticated user (or any blog with blogs.delete_any)
+func (c *APIController) DeleteBlog(w http.ResponseWriter, r *http.Request) {
+	id, ok := apiIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil || user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+		return
+	}
+
+	ownerID, err := c.BlogModel.OwnerID(r.Context(), id)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusNotFound, "not_found", "Blog not found")
+		return
+	}
+	if !canDeleteBlog(r, user, ownerID) {
+		respondError(w, http.StatusForbidden, "forbidden", "You don't have permission to delete this blog")
+		return
+	}
+
+	before := auditBlog(r.Context(), c.BlogModel, id)
+
+	if err := c.BlogModel.Delete(r.Context(), id); err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to delete blog")
+		return
+	}
+
+	c.recordAudit(r, user, models.AuditBlogDeleted, "blog", id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me returns the authenticated user
+func (c *APIController) Me(w http.ResponseWriter, r *http.Request) {
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil || user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"data": user})
+}
+
+// ListUsers returns all users with pagination metadata (requires users.manage)
+func (c *APIController) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.requireAdmin(w, r); !ok {
+		return
+	}
+
+	page, perPage := apiPagination(r)
+
+	users, err := c.UserModel.GetAllPaginated(r.Context(), perPage, (page-1)*perPage)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to load users")
+		return
+	}
+
+	total, err := c.UserModel.Count(r.Context())
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to count users")
+		return
+	}
+
+	if users == nil {
+		users = []*models.User{}
+	}
+	respondPaginated(w, users, newPaginationMeta(page, perPage, total))
+}
+
+// ShowUser returns a single user (requires users.manage)
+func (c *APIController) ShowUser(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.requireAdmin(w, r); !ok {
+		return
+	}
+
+	id, ok := apiIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := c.UserModel.GetByID(r.Context(), id)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusNotFound, "not_found", "User not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"data": user})
+}
+
+// CreateUser creates a new user account (requires users.manage)
+func (c *APIController) CreateUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, ok := c.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	var req userRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	name := strings.TrimSpace(stringValue(req.Name))
+	email := strings.TrimSpace(stringValue(req.Email))
+	role := strings.TrimSpace(stringValue(req.Role))
+	password := stringValue(req.Password)
+
+	if role == "" {
+		role = models.DefaultRole
+	}
+
+	fields := map[string]string{}
+	if name == "" {
+		fields["name"] = "Name is required"
+	}
+	if !strings.Contains(email, "@") {
+		fields["email"] = "A valid email is required"
+	}
+	if len(password) < 6 {
+		fields["password"] = "Password must be at least 6 characters long"
+	}
+	if !c.isValidRole(role) {
+		fields["role"] = "Role does not exist"
+	}
+	if len(fields) > 0 {
+		respondValidationError(w, fields)
+		return
+	}
+
+	exists, err := c.UserModel.EmailExists(r.Context(), email)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to check email availability")
+		return
+	}
+	if exists {
+		respondError(w, http.StatusConflict, "conflict", "Email already registered")
+		return
+	}
+
+	// Create the user and assign their role together, so a failed role
+	// assignment doesn't leave a user behind with the default role
+	var user *models.User
+	err = c.App.Transaction(r.Context(), func(ctx context.Context) error {
+		created, err := c.App.Users.Create(ctx, name, email, password)
+		if err != nil {
+			return err
+		}
+
+		// UserModel.Create always assigns the default role
+		if role != created.Role {
+			if err := c.App.Users.Update(ctx, created.ID, created.Name, created.Email, role, nil); err != nil {
+				return err
+			}
+			created.Role = role
+		}
+
+		user = created
+		return nil
+	})
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to create user")
+		return
+	}
+
+	c.recordAudit(r, currentUser, models.AuditUserCreated, "user", user.ID, nil, auditUser(user))
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{"data": user})
+}
+
+// UpdateUser applies a partial update to a user (requires users.manage)
+func (c *APIController) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, ok := c.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := apiIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	// Prevent editing super admin (ID 1)
+	if id == 1 {
+		respondError(w, http.StatusForbidden, "forbidden", "Super admin account cannot be edited")
+		return
+	}
+
+	user, err := c.UserModel.GetByID(r.Context(), id)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusNotFound, "not_found", "User not found")
+		return
+	}
+
+	var req userRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	name, email, role := user.Name, user.Email, user.Role
+	if req.Name != nil {
+		name = strings.TrimSpace(*req.Name)
+	}
+	if req.Email != nil {
+		email = strings.TrimSpace(*req.Email)
+	}
+	if req.Role != nil {
+		role = strings.TrimSpace(*req.Role)
+	}
+
+	fields := map[string]string{}
+	if name == "" {
+		fields["name"] = "Name is required"
+	}
+	if !strings.Contains(email, "@") {
+		fields["email"] = "A valid email is required"
+	}
+	if !c.isValidRole(role) {
+		fields["role"] = "Role does not exist"
+	}
+	if req.Password != nil && *req.Password != "" && len(*req.Password) < 6 {
+		fields["password"] = "Password must be at least 6 characters long"
+	}
+	if len(fields) > 0 {
+		respondValidationError(w, fields)
+		return
+	}
+
+	var password *string
+	if req.Password != nil && *req.Password != "" {
+		password = req.Password
+	}
+
+	// A password set by an admin evicts whoever was signed in with the old one
+	err = c.updateUser(r, currentUser, user, name, email, role, password)
+	if err != nil {
+		if strings.Contains(err.Error(), "email already exists") {
+			respondError(w, http.StatusConflict, "conflict", "Email address is already in use by another user")
+			return
+		}
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to update user")
+		return
+	}
+
+	updated, err := c.UserModel.GetByID(r.Context(), id)
+	if err != nil {
+		respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to load user")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"data": updated})
+}
+
+// DeleteUser deletes a user and their blogs (requires users.manage)
+func (c *APIController) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, ok := c.requireAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := apiIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if id == currentUser.ID {
+		respondError(w, http.StatusBadRequest, "bad_request", "You cannot delete your own account")
+		return
+	}
+
+	// Prevent deletion of super admin (ID 1)
+	if id == 1 {
+		respondError(w, http.StatusForbidden, "forbidden", "Super admin account cannot be deleted")
+		return
+	}
+
+	err := c.deleteUser(r, currentUser, id)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			respondError(w, http.StatusNotFound, "not_found", "User not found")
+		case "cannot delete the main administrator account":
+			respondError(w, http.StatusForbidden, "forbidden", "Cannot delete the main administrator account")
+		default:
+			respondQueryError(w, r, err, http.StatusInternalServerError, "internal_error", "Failed to delete user")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NotFound is the JSON 404 handler for unknown API routes
+func (c *APIController) NotFound(w http.ResponseWriter, r *http.Request) {
+	respondError(w, http.StatusNotFound, "not_found", "Resource not found")
+}
+
+// requireAdmin resolves the current user and rejects anyone without users.manage
+func (c *APIController) requireAdmin(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil || user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+		return nil, false
+	}
+
+	if !user.Can(models.PermUsersManage) {
+		respondError(w, http.StatusForbidden, "forbidden", "Missing the '"+models.PermUsersManage+"' permission")
+		return nil, false
+	}
+
+	if !middleware.HasScope(r, models.ScopeAdmin) {
+		respondError(w, http.StatusForbidden, "insufficient_scope", "API token lacks the 'admin' scope")
+		return nil, false
+	}
+
+	return user, true
+}
+
+// apiIDParam parses the {id} route variable, writing a 400 on failure
+func apiIDParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "bad_request", "Invalid ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// apiPagination reads ?page= and ?per_page= with sane bounds
+func apiPagination(r *http.Request) (int, int) {
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	perPage := 12
+	if pp, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && pp > 0 {
+		perPage = pp
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	return page, perPage
+}
+
+// decodeJSON decodes the request body, writing a 400 on malformed input
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_json", "Request body must be valid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// stringValue dereferences an optional string
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// isValidRole reports whether role names one of the configured roles
+func (c *APIController) isValidRole(role string) bool {
+	exists, err := c.RoleModel.Exists(role)
+	return err == nil && exists
+}
@@ -0,0 +1,235 @@
+// app/controllers/audit_controller.go - Handles the admin audit log and its CSV export
+package controllers
+
+import (
+	"encoding/csv"
+	"go-web-app/app/models"
+	"go-web-app/bootstrap"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditController handles viewing and exporting audit events
+type AuditController struct {
+	*Controller
+	AuditModel *models.AuditModel
+}
+
+// NewAuditController creates a new AuditController
+func NewAuditController(app *bootstrap.App) *AuditController {
+	return &AuditController{
+		Controller: NewController(app),
+		AuditModel: app.Audit,
+	}
+}
+
+// auditFilters holds the audit log filter form values as submitted
+type auditFilters struct {
+	Action     string
+	Actor      string
+	TargetType string
+	TargetID   string
+	From       string
+	To         string
+}
+
+// Index lists audit events, newest first, with optional filters (requires audit.view)
+func (c *AuditController) Index(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermAuditView)
+	if !ok {
+		return
+	}
+
+	filters := readAuditFilters(r.URL.Query())
+
+	// Get page parameter from URL (default to 1)
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	limit := 25
+	query, errorMsg := buildAuditQuery(filters)
+	query.Limit = limit
+	query.Offset = (page - 1) * limit
+
+	var events []*models.AuditEvent
+	total := 0
+	if errorMsg == "" {
+		var err error
+		events, err = c.AuditModel.Search(query)
+		if err != nil {
+			http.Error(w, "Failed to load audit log", http.StatusInternalServerError)
+			return
+		}
+
+		total, err = c.AuditModel.Count(query)
+		if err != nil {
+			total = 0 // Default to 0 if count fails
+		}
+	}
+	totalPages := (total + limit - 1) / limit // Ceiling division
+
+	actors, err := c.AuditModel.GetActors()
+	if err != nil {
+		actors = []*models.AuditActor{} // Default to empty slice on error
+	}
+
+	// Carry the filters across pages and into the export
+	pageQuery := filters.values()
+
+	data := map[string]interface{}{
+		"Title":       "Audit Log",
+		"User":        user,
+		"Events":      events,
+		"Filters":     filters,
+		"Actions":     models.AuditActions,
+		"TargetTypes": models.AuditTargetTypes,
+		"Actors":      actors,
+		"Error":       errorMsg,
+		"Total":       total,
+		"Page":        page,
+		"TotalPages":  totalPages,
+		"HasNext":     page < totalPages,
+		"HasPrev":     page > 1,
+		"NextPage":    page + 1,
+		"PrevPage":    page - 1,
+		"BaseURL":     "/dashboard/audit",               // For pagination component
+		"PageQuery":   template.URL(pageQuery.Encode()), // Encoded by url.Values, safe to pass through
+	}
+
+	c.renderTemplate(w, r, "dashboard/audit", data)
+}
+
+// Export downloads every audit event matching the filters as CSV (requires audit.view)
+func (c *AuditController) Export(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authorize(w, r, models.PermAuditView); !ok {
+		return
+	}
+
+	query, errorMsg := buildAuditQuery(readAuditFilters(r.URL.Query()))
+	if errorMsg != "" {
+		http.Error(w, errorMsg, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-log-`+time.Now().Format("20060102-150405")+`.csv"`)
+
+	// Rows are streamed, so a failure part way through can only be logged
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "time", "actor_id", "actor", "action", "target_type", "target_id", "ip_address", "before", "after"})
+	err := c.AuditModel.Each(query, func(event *models.AuditEvent) error {
+		return writer.Write([]string{
+			strconv.FormatInt(event.ID, 10),
+			event.CreatedAt.Format(time.RFC3339),
+			optionalID(event.ActorID),
+			csvCell(event.ActorName),
+			event.Action,
+			event.TargetType,
+			optionalID(event.TargetID),
+			event.IPAddress,
+			csvCell(event.Before),
+			csvCell(event.After),
+		})
+	})
+	writer.Flush()
+
+	if err == nil {
+		err = writer.Error()
+	}
+	if err != nil {
+		log.Printf("Audit export error: %v", err)
+	}
+}
+
+// readAuditFilters reads the filter form from query parameters
+func readAuditFilters(values url.Values) auditFilters {
+	return auditFilters{
+		Action:     strings.TrimSpace(values.Get("action")),
+		Actor:      strings.TrimSpace(values.Get("actor")),
+		TargetType: strings.TrimSpace(values.Get("target_type")),
+		TargetID:   strings.TrimSpace(values.Get("target_id")),
+		From:       strings.TrimSpace(values.Get("from")),
+		To:         strings.TrimSpace(values.Get("to")),
+	}
+}
+
+// values encodes the filters that are set, to carry them across pages and into the export
+func (f auditFilters) values() url.Values {
+	values := url.Values{}
+	for key, value := range map[string]string{"action": f.Action, "actor": f.Actor, "target_type": f.TargetType,
+		"target_id": f.TargetID, "from": f.From, "to": f.To} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	return values
+}
+
+// buildAuditQuery validates the submitted filters and converts them into an audit query
+func buildAuditQuery(filters auditFilters) (models.AuditQuery, string) {
+	query := models.AuditQuery{Action: filters.Action, TargetType: filters.TargetType}
+
+	if filters.Actor != "" {
+		id, err := strconv.Atoi(filters.Actor)
+		if err != nil || id <= 0 {
+			return query, "Invalid actor"
+		}
+		query.ActorID = id
+	}
+
+	if filters.TargetID != "" {
+		id, err := strconv.Atoi(filters.TargetID)
+		if err != nil || id <= 0 {
+			return query, "The target ID must be a positive number"
+		}
+		query.TargetID = id
+	}
+
+	if filters.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", filters.From, time.Local)
+		if err != nil {
+			return query, "Dates must use the YYYY-MM-DD format"
+		}
+		query.From = &from
+	}
+
+	if filters.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", filters.To, time.Local)
+		if err != nil {
+			return query, "Dates must use the YYYY-MM-DD format"
+		}
+		// The end date is inclusive
+		to = to.AddDate(0, 0, 1)
+		query.To = &to
+	}
+
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return query, "The start date must be before the end date"
+	}
+
+	return query, ""
+}
+
+// optionalID formats a nullable ID for CSV, leaving NULL blank
+func optionalID(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.Itoa(*id)
+}
+
+// csvCell stops spreadsheet apps from running user-supplied text as a formula
+// by prefixing cells that start with a formula character with a quote
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -1,22 +1,33 @@
 package controllers
 
 import (
-	"go-web-app/app/middleware"
 	"go-web-app/app/models"
-	"go-web-app/config"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // AuthController handles authentication related requests
 type AuthController struct {
-	UserModel *models.UserModel
+	*Controller
+	UserModel      models.UserRepository
+	TwoFactorModel *models.TwoFactorModel
+	AttemptModel   *models.LoginAttemptModel
+	Mailer         services.Mailer
 }
 
 // NewAuthController creates a new AuthController
-func NewAuthController() *AuthController {
+func NewAuthController(app *bootstrap.App) *AuthController {
 	return &AuthController{
-		UserModel: models.NewUserModel(config.Database),
+		Controller:     NewController(app),
+		UserModel:      app.Users,
+		TwoFactorModel: app.TwoFactor,
+		AttemptModel:   app.LoginAttempts,
+		Mailer:         app.Mailer,
 	}
 }
 
@@ -26,7 +37,22 @@ func (c *AuthController) ShowLogin(w http.ResponseWriter, r *http.Request) {
 		"Title": "Login",
 	}
 
-	renderTemplate(w, "auth/login", data)
+	if r.URL.Query().Get("reset") != "" {
+		data["Success"] = "Your password has been reset. Sign in with your new password."
+	}
+
+	if r.URL.Query().Get("sessions") == "ended" {
+		data["Success"] = "You have been logged out on every device."
+	}
+
+	switch r.URL.Query().Get("verification") {
+	case "verified":
+		data["Success"] = "Your email address is verified. Sign in to start writing."
+	case "expired":
+		data["Error"] = "That verification link has expired. Sign in to request a new one."
+	}
+
+	c.renderTemplate(w, r, "auth/login", data)
 }
 
 // ShowRegister displays the registration form
@@ -35,7 +61,7 @@ func (c *AuthController) ShowRegister(w http.ResponseWriter, r *http.Request) {
 		"Title": "Register",
 	}
 
-	renderTemplate(w, "auth/register", data)
+	c.renderTemplate(w, r, "auth/register", data)
 }
 
 // Login handles user login
@@ -54,15 +80,108 @@ func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Slow down repeated guesses against this account or from this address
+	if wait, locked := c.loginWait(email, c.App.Middleware.ClientIP(r)); wait > 0 {
+		c.showLoginThrottled(w, r, wait, locked)
+		return
+	}
+
 	// Authenticate user
-	user, err := c.UserModel.Authenticate(email, password)
+	user, err := c.UserModel.Authenticate(r.Context(), email, password)
 	if err != nil {
+		c.recordAttempt(r, email, false)
 		c.showLoginWithError(w, r, "Invalid email or password")
 		return
 	}
 
+	// Accounts with two-factor authentication finish signing in on the next step
+	if user.HasTwoFactor() {
+		if err := c.App.Middleware.SetPendingTwoFactor(w, r, user); err != nil {
+			c.showLoginWithError(w, r, "Failed to create session")
+			return
+		}
+
+		http.Redirect(w, r, "/login/two-factor", http.StatusSeeOther)
+		return
+	}
+
+	c.recordAttempt(r, user.Email, true)
+
+	// Set session
+	err = c.App.Middleware.SetUserSession(w, r, user)
+	if err != nil {
+		c.showLoginWithError(w, r, "Failed to create session")
+		return
+	}
+
+	// Redirect to dashboard
+	http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+}
+
+// ShowTwoFactor displays the second login step for accounts with two-factor authentication
+func (c *AuthController) ShowTwoFactor(w http.ResponseWriter, r *http.Request) {
+	if _, ok := c.App.Middleware.GetPendingTwoFactor(r); !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Title": "Two-Factor Authentication",
+	}
+
+	c.renderTemplate(w, r, "auth/two-factor", data)
+}
+
+// VerifyTwoFactor checks an authenticator or recovery code and completes the login
+func (c *AuthController) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/login/two-factor", http.StatusSeeOther)
+		return
+	}
+
+	userID, ok := c.App.Middleware.GetPendingTwoFactor(r)
+	if !ok {
+		c.showLoginWithError(w, r, "Your sign-in attempt expired. Please sign in again.")
+		return
+	}
+
+	user, err := c.UserModel.GetByID(r.Context(), userID)
+	if err != nil || !user.HasTwoFactor() {
+		c.App.Middleware.ClearPendingTwoFactor(w, r)
+		c.showLoginWithError(w, r, "Your sign-in attempt expired. Please sign in again.")
+		return
+	}
+
+	code := strings.TrimSpace(r.FormValue("code"))
+	if code == "" {
+		c.showTwoFactorWithError(w, r, "Enter the code from your authenticator app or a recovery code")
+		return
+	}
+
+	valid, err := c.checkSecondFactor(user, code)
+	if err != nil {
+		log.Printf("Two-factor error: %v", err)
+		c.showTwoFactorWithError(w, r, "Failed to verify code")
+		return
+	}
+
+	if !valid {
+		c.recordAttempt(r, user.Email, false)
+
+		remaining, err := c.App.Middleware.FailPendingTwoFactor(w, r)
+		if err != nil || remaining == 0 {
+			c.showLoginWithError(w, r, "Too many invalid codes. Please sign in again.")
+			return
+		}
+
+		c.showTwoFactorWithError(w, r, "Invalid code")
+		return
+	}
+
+	c.recordAttempt(r, user.Email, true)
+
 	// Set session
-	err = middleware.SetUserSession(w, r, user.ID)
+	err = c.App.Middleware.SetUserSession(w, r, user)
 	if err != nil {
 		c.showLoginWithError(w, r, "Failed to create session")
 		return
@@ -72,6 +191,59 @@ func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
 }
 
+// checkSecondFactor accepts a current authenticator code that has not been
+// used before, or one of the user's unused recovery codes
+func (c *AuthController) checkSecondFactor(user *models.User, code string) (bool, error) {
+	if isTOTPCode(code) {
+		step, ok := services.ValidateTOTP(user.TwoFactorSecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return c.TwoFactorModel.UseStep(user.ID, step)
+	}
+
+	return c.TwoFactorModel.UseRecoveryCode(user.ID, code)
+}
+
+// loginWait returns how long the client must wait before trying to sign in to
+// email again, and whether that is because of a lockout. The stricter of the
+// per-email and per-IP throttles wins.
+func (c *AuthController) loginWait(email, ip string) (time.Duration, bool) {
+	var wait time.Duration
+	var locked bool
+
+	checks := []struct {
+		throttle services.Throttle
+		count    func(string, time.Duration) (int, time.Duration, error)
+		key      string
+	}{
+		{services.LoginEmailThrottle, c.AttemptModel.FailuresByEmail, email},
+		{services.LoginIPThrottle, c.AttemptModel.FailuresByIP, ip},
+	}
+
+	for _, check := range checks {
+		failures, sinceLast, err := check.count(check.key, check.throttle.LockoutFor)
+		if err != nil {
+			// Don't lock everyone out because the attempts table is unavailable
+			log.Printf("Login throttle error: %v", err)
+			continue
+		}
+
+		if d, l := check.throttle.Wait(failures, sinceLast); d > wait {
+			wait, locked = d, l
+		}
+	}
+
+	return wait, locked
+}
+
+// recordAttempt stores a login attempt for the throttle and the admin activity log
+func (c *AuthController) recordAttempt(r *http.Request, email string, successful bool) {
+	if err := c.AttemptModel.Record(email, c.App.Middleware.ClientIP(r), r.UserAgent(), successful); err != nil {
+		log.Printf("Login attempt error: %v", err)
+	}
+}
+
 // Register handles user registration
 func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -101,7 +273,7 @@ func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if email already exists
-	exists, err := c.UserModel.EmailExists(email)
+	exists, err := c.UserModel.EmailExists(r.Context(), email)
 	if err != nil {
 		c.showRegisterWithError(w, r, "Failed to check email availability")
 		return
@@ -113,26 +285,33 @@ func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create user
-	user, err := c.UserModel.Create(name, email, password)
+	user, err := c.UserModel.Create(r.Context(), name, email, password)
 	if err != nil {
 		c.showRegisterWithError(w, r, "Failed to create account")
 		return
 	}
 
+	// Ask the new user to confirm their address; they can resend from the dashboard
+	verification := "sent"
+	if err := c.sendVerificationEmail(c.Mailer, user); err != nil {
+		log.Printf("Verification email error: %v", err)
+		verification = "failed"
+	}
+
 	// Set session
-	err = middleware.SetUserSession(w, r, user.ID)
+	err = c.App.Middleware.SetUserSession(w, r, user)
 	if err != nil {
 		c.showRegisterWithError(w, r, "Account created but failed to login")
 		return
 	}
 
 	// Redirect to dashboard
-	http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+	http.Redirect(w, r, "/dashboard?verification="+verification, http.StatusSeeOther)
 }
 
 // Logout handles user logout
 func (c *AuthController) Logout(w http.ResponseWriter, r *http.Request) {
-	err := middleware.ClearUserSession(w, r)
+	err := c.App.Middleware.ClearUserSession(w, r)
 	if err != nil {
 		http.Error(w, "Failed to logout", http.StatusInternalServerError)
 		return
@@ -149,7 +328,51 @@ func (c *AuthController) showLoginWithError(w http.ResponseWriter, r *http.Reque
 		"Email": r.FormValue("email"), // Preserve email input
 	}
 
-	renderTemplate(w, "auth/login", data)
+	c.renderTemplate(w, r, "auth/login", data)
+}
+
+// showLoginThrottled displays the login form with a 429 status telling the
+// client how long to wait before trying again
+func (c *AuthController) showLoginThrottled(w http.ResponseWriter, r *http.Request, wait time.Duration, locked bool) {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+
+	message := "Too many failed sign-in attempts. Try again in " + formatWait(wait) + "."
+	if locked {
+		message = "This account is temporarily locked after too many failed sign-in attempts. Try again in " + formatWait(wait) + " or ask an administrator to unlock it."
+	}
+
+	data := map[string]interface{}{
+		"Title": "Login",
+		"Error": message,
+		"Email": r.FormValue("email"), // Preserve email input
+	}
+
+	c.renderTemplateStatus(w, r, http.StatusTooManyRequests, "auth/login", data)
+}
+
+// formatWait renders a wait as whole seconds or, past a minute, whole minutes (rounded up)
+func formatWait(wait time.Duration) string {
+	if wait <= time.Minute {
+		seconds := int((wait + time.Second - 1) / time.Second)
+		if seconds == 1 {
+			return "1 second"
+		}
+		return strconv.Itoa(seconds) + " seconds"
+	}
+
+	minutes := int((wait + time.Minute - 1) / time.Minute)
+	return strconv.Itoa(minutes) + " minutes"
+}
+
+// showTwoFactorWithError displays the second login step with an error message
+func (c *AuthController) showTwoFactorWithError(w http.ResponseWriter, r *http.Request, errorMsg string) {
+	data := map[string]interface{}{
+		"Title": "Two-Factor Authentication",
+		"Error": errorMsg,
+	}
+
+	c.renderTemplate(w, r, "auth/two-factor", data)
 }
 
 // showRegisterWithError displays register form with error message
@@ -161,5 +384,5 @@ func (c *AuthController) showRegisterWithError(w http.ResponseWriter, r *http.Re
 		"Email": r.FormValue("email"),
 	}
 
-	renderTemplate(w, "auth/register", data)
+	c.renderTemplate(w, r, "auth/register", data)
 }
@@ -4,26 +4,37 @@ package controllers
 import (
 	"go-web-app/app/middleware"
 	"go-web-app/app/models"
-	"go-web-app/config"
+	"go-web-app/app/policies"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 // BlogController handles blog CRUD operations
 type BlogController struct {
-	BlogModel *models.BlogModel
-	UserModel *models.UserModel
+	*Controller
+	BlogModel     models.BlogRepository
+	UserModel     models.UserRepository
+	CategoryModel *models.CategoryModel
+	TagModel      *models.TagModel
+	Notifier      *reviewNotifier
 }
 
 // NewBlogController creates a new BlogController
-func NewBlogController() *BlogController {
+func NewBlogController(app *bootstrap.App) *BlogController {
 	return &BlogController{
-		BlogModel: models.NewBlogModel(config.Database),
-		UserModel: models.NewUserModel(config.Database),
+		Controller:    NewController(app),
+		BlogModel:     app.Blogs,
+		UserModel:     app.Users,
+		CategoryModel: app.Categories,
+		TagModel:      app.Tags,
+		Notifier:      newReviewNotifier(app),
 	}
 }
 
@@ -50,14 +61,14 @@ func (c *BlogController) Index(w http.ResponseWriter, r *http.Request) {
 	offset := (page - 1) * limit
 
 	// Get user's blogs for current page
-	blogs, err := c.BlogModel.GetByUserIDPaginated(user.ID, limit, offset)
+	blogs, err := c.BlogModel.GetByUserIDPaginated(r.Context(), user.ID, limit, offset)
 	if err != nil {
-		http.Error(w, "Failed to load blogs", http.StatusInternalServerError)
+		queryError(w, r, err, "Failed to load blogs", http.StatusInternalServerError)
 		return
 	}
 
 	// Get total user blog count for pagination
-	totalBlogs, err := c.BlogModel.CountUserBlogs(user.ID)
+	totalBlogs, err := c.BlogModel.CountUserBlogs(r.Context(), user.ID)
 	if err != nil {
 		totalBlogs = 0 // Default to 0 if count fails
 	}
@@ -71,8 +82,8 @@ func (c *BlogController) Index(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Blog Controller: Found %d blogs for user %s (page %d)", len(blogs), user.Name, page)
 
 	// Calculate user's blog statistics
-	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "published")
-	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "draft")
+	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "published")
+	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "draft")
 
 	// Prepare stats
 	stats := map[string]interface{}{
@@ -86,7 +97,6 @@ func (c *BlogController) Index(w http.ResponseWriter, r *http.Request) {
 		"Title":      "My Blogs",
 		"Blogs":      blogs,
 		"User":       user,
-		"IsAdmin":    user.IsAdmin(),
 		"Stats":      stats,
 		"Page":       page,
 		"TotalPages": totalPages,
@@ -97,21 +107,13 @@ func (c *BlogController) Index(w http.ResponseWriter, r *http.Request) {
 		"BaseURL":    "/dashboard/blogs", // For pagination component
 	}
 
-	renderTemplate(w, "dashboard/blogs/index", data)
+	c.renderTemplate(w, r, "dashboard/blogs/index", data)
 }
 
-// AdminIndex displays all blogs for admin users only
+// AdminIndex displays every author's blogs (requires blogs.edit_any)
 func (c *BlogController) AdminIndex(w http.ResponseWriter, r *http.Request) {
-	// Get current user
-	user, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
-		return
-	}
-
-	// Check if user is admin
-	if !user.IsAdmin() {
-		http.Error(w, "Access denied. Admin privileges required.", http.StatusForbidden)
+	user, ok := authorize(w, r, models.PermBlogsEditAny)
+	if !ok {
 		return
 	}
 
@@ -129,14 +131,14 @@ func (c *BlogController) AdminIndex(w http.ResponseWriter, r *http.Request) {
 	offset := (page - 1) * limit
 
 	// Get blogs for current page
-	blogs, err := c.BlogModel.GetAllBlogs(limit, offset)
+	blogs, err := c.BlogModel.GetAllBlogs(r.Context(), limit, offset)
 	if err != nil {
-		http.Error(w, "Failed to load blogs", http.StatusInternalServerError)
+		queryError(w, r, err, "Failed to load blogs", http.StatusInternalServerError)
 		return
 	}
 
 	// Get total blog count for pagination
-	totalBlogs, err := c.BlogModel.Count()
+	totalBlogs, err := c.BlogModel.Count(r.Context())
 	if err != nil {
 		totalBlogs = 0 // Default to 0 if count fails
 	}
@@ -150,9 +152,9 @@ func (c *BlogController) AdminIndex(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Admin Blog Controller: Found %d total blogs for admin %s (page %d)", len(blogs), user.Name, page)
 
 	// Calculate global blog statistics for admin
-	publishedCount, _ := c.BlogModel.CountByStatus("published")
-	draftCount, _ := c.BlogModel.CountByStatus("draft")
-	totalAuthors, _ := c.UserModel.CountByRole("author")
+	publishedCount, _ := c.BlogModel.CountByStatus(r.Context(), "published")
+	draftCount, _ := c.BlogModel.CountByStatus(r.Context(), "draft")
+	totalAuthors, _ := c.UserModel.CountByRole(r.Context(), "author")
 
 	// Prepare stats
 	stats := map[string]interface{}{
@@ -167,7 +169,6 @@ func (c *BlogController) AdminIndex(w http.ResponseWriter, r *http.Request) {
 		"Title":      "All Blogs (Admin Management)",
 		"Blogs":      blogs,
 		"User":       user,
-		"IsAdmin":    true,
 		"AdminView":  true, // Flag to indicate this is admin view
 		"Stats":      stats,
 		"Page":       page,
@@ -179,15 +180,19 @@ func (c *BlogController) AdminIndex(w http.ResponseWriter, r *http.Request) {
 		"BaseURL":    "/dashboard/admin/blogs", // For pagination component
 	}
 
-	renderTemplate(w, "dashboard/blogs/admin", data)
+	c.renderTemplate(w, r, "dashboard/blogs/admin", data)
 }
 
 // Create shows the create blog form
 func (c *BlogController) Create(w http.ResponseWriter, r *http.Request) {
-	// Get current user
-	user, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+	user, ok := authorize(w, r, models.PermBlogsCreate)
+	if !ok {
+		return
+	}
+
+	// Unverified accounts can't create blogs
+	if !user.IsVerified() {
+		http.Redirect(w, r, "/dashboard?verification=required", http.StatusSeeOther)
 		return
 	}
 
@@ -196,8 +201,10 @@ func (c *BlogController) Create(w http.ResponseWriter, r *http.Request) {
 		"Title": "Create New Blog",
 		"User":  user,
 	}
+	c.addTaxonomyData(data, taxonomySelection{})
+	addScheduleData(data, "", "")
 
-	renderTemplate(w, "dashboard/blogs/create", data)
+	c.renderTemplate(w, r, "dashboard/blogs/create", data)
 }
 
 // Store creates a new blog post
@@ -207,45 +214,90 @@ func (c *BlogController) Store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get current user
-	user, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+	user, ok := authorize(w, r, models.PermBlogsCreate)
+	if !ok {
+		return
+	}
+
+	// Unverified accounts can't create blogs
+	if !user.IsVerified() {
+		http.Redirect(w, r, "/dashboard?verification=required", http.StatusSeeOther)
 		return
 	}
 
 	// Get form data
 	title := strings.TrimSpace(r.FormValue("title"))
+	slug := strings.TrimSpace(r.FormValue("slug"))
 	content := strings.TrimSpace(r.FormValue("content"))
 	excerpt := strings.TrimSpace(r.FormValue("excerpt"))
 	status := strings.TrimSpace(r.FormValue("status"))
+	format := strings.TrimSpace(r.FormValue("format"))
 
 	// Set defaults
 	if excerpt == "" {
 		// Auto-generate excerpt from content (first 200 characters)
-		if len(content) > 200 {
-			excerpt = content[:200] + "..."
-		} else {
-			excerpt = content
-		}
+		excerpt = generateExcerpt(content)
 	}
 	if status == "" {
-		status = "published"
+		status = defaultBlogStatus(user)
 	}
 
 	// Validate input
 	if title == "" || content == "" {
-		c.showCreateWithError(w, r, "Title and content are required", title, content)
+		c.showCreateWithError(w, r, "Title and content are required", title, slug, content)
+		return
+	}
+
+	if !policies.CanSetBlogStatus(user, models.BlogDraft, status) {
+		c.showCreateWithError(w, r, statusDenied(status), title, slug, content)
 		return
 	}
 
-	// Create blog
-	_, err = c.BlogModel.Create(title, content, excerpt, status, user.ID)
+	if format != "" && !services.IsValidFormat(format) {
+		c.showCreateWithError(w, r, "Invalid content format", title, slug, content)
+		return
+	}
+
+	publishAt, errorMsg := readSchedule(r, status)
+	if errorMsg != "" {
+		c.showCreateWithError(w, r, errorMsg, title, slug, content)
+		return
+	}
+
+	taxonomy, err := c.readTaxonomy(r)
+	if err != nil {
+		c.showCreateWithError(w, r, "Please choose a valid category", title, slug, content)
+		return
+	}
+
+	// Create blog (an empty slug is generated from the title)
+	blog, err := c.BlogModel.CreateFrom(r.Context(), models.BlogInput{
+		Title:      title,
+		Slug:       slug,
+		Content:    content,
+		Format:     format,
+		Excerpt:    excerpt,
+		Status:     status,
+		PublishAt:  publishAt,
+		UserID:     user.ID,
+		CategoryID: &taxonomy.CategoryID,
+	})
 	if err != nil {
-		c.showCreateWithError(w, r, "Failed to create blog", title, content)
+		if strings.Contains(err.Error(), "slug already exists") {
+			c.showCreateWithError(w, r, "That URL slug is already in use by another post", title, slug, content)
+			return
+		}
+		c.showCreateWithError(w, r, "Failed to create blog", title, slug, content)
 		return
 	}
 
+	// The post already exists, so a tagging failure should not send the author back to the form
+	if err := c.syncTags(blog.ID, taxonomy); err != nil {
+		log.Printf("Blog Controller: failed to save tags for blog %d: %v", blog.ID, err)
+	}
+
+	c.Notifier.statusChanged(r, blog, models.BlogDraft, user, "")
+
 	// Redirect to blogs list
 	http.Redirect(w, r, "/dashboard/blogs", http.StatusSeeOther)
 }
@@ -273,33 +325,41 @@ func (c *BlogController) Edit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user can edit this blog
-	canEdit, err := c.BlogModel.CanUserEdit(id, user.ID)
+	// Get blog
+	blog, err := c.BlogModel.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Blog not found", http.StatusNotFound)
+		queryError(w, r, err, "Blog not found", http.StatusNotFound)
 		return
 	}
 
-	if !canEdit {
+	// Check if user can edit this blog
+	if !canEditBlog(r, user, blog) {
 		http.Error(w, "You don't have permission to edit this blog", http.StatusForbidden)
 		return
 	}
 
-	// Get blog
-	blog, err := c.BlogModel.GetByID(id)
-	if err != nil {
-		http.Error(w, "Blog not found", http.StatusNotFound)
-		return
-	}
-
 	// Prepare data for template
 	data := map[string]interface{}{
 		"Title": "Edit Blog",
 		"Blog":  blog,
 		"User":  user,
 	}
+	c.addReviewData(data, user, blog)
 
-	renderTemplate(w, "dashboard/blogs/edit", data)
+	// Pre-select the post's current category and tags
+	taxonomy := taxonomySelection{}
+	if blog.CategoryID != nil {
+		taxonomy.CategoryID = *blog.CategoryID
+	}
+	if tags, err := c.TagModel.GetByBlogID(blog.ID); err == nil {
+		for _, tag := range tags {
+			taxonomy.TagIDs = append(taxonomy.TagIDs, tag.ID)
+		}
+	}
+	c.addTaxonomyData(data, taxonomy)
+	addScheduleData(data, blog.Status, formatPublishAt(blog.PublishAt))
+
+	c.renderTemplate(w, r, "dashboard/blogs/edit", data)
 }
 
 // Update updates an existing blog post
@@ -331,46 +391,88 @@ func (c *BlogController) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if user can edit this blog
-	canEdit, err := c.BlogModel.CanUserEdit(id, user.ID)
+	current, err := c.BlogModel.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Blog not found", http.StatusNotFound)
+		queryError(w, r, err, "Blog not found", http.StatusNotFound)
 		return
 	}
 
-	if !canEdit {
+	if !canEditBlog(r, user, current) {
 		http.Error(w, "You don't have permission to edit this blog", http.StatusForbidden)
 		return
 	}
 
 	// Get form data
 	title := strings.TrimSpace(r.FormValue("title"))
+	slug := strings.TrimSpace(r.FormValue("slug"))
 	content := strings.TrimSpace(r.FormValue("content"))
 	excerpt := strings.TrimSpace(r.FormValue("excerpt"))
 	status := strings.TrimSpace(r.FormValue("status"))
+	format := strings.TrimSpace(r.FormValue("format"))
 
 	// Set defaults
 	if excerpt == "" {
 		// Auto-generate excerpt from content (first 200 characters)
-		if len(content) > 200 {
-			excerpt = content[:200] + "..."
-		} else {
-			excerpt = content
-		}
+		excerpt = generateExcerpt(content)
 	}
 	if status == "" {
-		status = "published"
+		status = defaultBlogStatus(user)
 	}
+	status = policies.EditedBlogStatus(user, current.Status, status)
 
 	// Validate input
 	if title == "" || content == "" {
-		c.showEditWithError(w, r, id, "Title and content are required", title, content)
+		c.showEditWithError(w, r, id, "Title and content are required", title, slug, content)
 		return
 	}
 
-	// Update blog
-	_, err = c.BlogModel.Update(id, title, content, excerpt, status)
+	if !policies.CanSetBlogStatus(user, current.Status, status) {
+		c.showEditWithError(w, r, id, statusDenied(status), title, slug, content)
+		return
+	}
+
+	if format != "" && !services.IsValidFormat(format) {
+		c.showEditWithError(w, r, id, "Invalid content format", title, slug, content)
+		return
+	}
+
+	publishAt, errorMsg := readSchedule(r, status)
+	if errorMsg != "" {
+		c.showEditWithError(w, r, id, errorMsg, title, slug, content)
+		return
+	}
+
+	taxonomy, err := c.readTaxonomy(r)
 	if err != nil {
-		c.showEditWithError(w, r, id, "Failed to update blog", title, content)
+		c.showEditWithError(w, r, id, "Please choose a valid category", title, slug, content)
+		return
+	}
+
+	// Update blog (the slug follows title changes unless overridden)
+	blog, err := c.BlogModel.UpdateFrom(r.Context(), id, models.BlogInput{
+		Title:      title,
+		Slug:       slug,
+		Content:    content,
+		Format:     format,
+		Excerpt:    excerpt,
+		Status:     status,
+		PublishAt:  publishAt,
+		CategoryID: &taxonomy.CategoryID,
+		EditorID:   user.ID,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "slug already exists") {
+			c.showEditWithError(w, r, id, "That URL slug is already in use by another post", title, slug, content)
+			return
+		}
+		c.showEditWithError(w, r, id, "Failed to update blog", title, slug, content)
+		return
+	}
+
+	c.Notifier.statusChanged(r, blog, current.Status, user, "")
+
+	if err := c.syncTags(id, taxonomy); err != nil {
+		c.showEditWithError(w, r, id, "Blog saved, but its tags could not be updated", title, slug, content)
 		return
 	}
 
@@ -406,30 +508,34 @@ func (c *BlogController) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user can delete this blog (owner or admin)
-	canDelete, err := c.BlogModel.CanUserDelete(id, user.ID, user.Role)
+	// Check if user can delete this blog
+	ownerID, err := c.BlogModel.OwnerID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Blog not found", http.StatusNotFound)
+		queryError(w, r, err, "Blog not found", http.StatusNotFound)
 		return
 	}
 
-	if !canDelete {
+	if !canDeleteBlog(r, user, ownerID) {
 		http.Error(w, "You don't have permission to delete this blog", http.StatusForbidden)
 		return
 	}
 
+	before := auditBlog(r.Context(), c.BlogModel, id)
+
 	// Delete blog
-	err = c.BlogModel.Delete(id)
+	err = c.BlogModel.Delete(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Failed to delete blog", http.StatusInternalServerError)
+		queryError(w, r, err, "Failed to delete blog", http.StatusInternalServerError)
 		return
 	}
 
+	c.recordAudit(r, user, models.AuditBlogDeleted, "blog", id, before, nil)
+
 	// Redirect to blogs list
 	http.Redirect(w, r, "/dashboard/blogs", http.StatusSeeOther)
 }
 
-// AdminDelete deletes any blog post (admin only)
+// AdminDelete deletes any blog post (requires blogs.delete_any)
 func (c *BlogController) AdminDelete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Redirect(w, r, "/dashboard/admin/blogs", http.StatusSeeOther)
@@ -450,32 +556,47 @@ func (c *BlogController) AdminDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get current user
-	user, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+	user, ok := authorize(w, r, models.PermBlogsDeleteAny)
+	if !ok {
 		return
 	}
 
-	// Check if user is admin
-	if !user.IsAdmin() {
-		http.Error(w, "Access denied. Admin privileges required.", http.StatusForbidden)
-		return
-	}
+	before := auditBlog(r.Context(), c.BlogModel, id)
 
 	// Delete blog (admin can delete any blog)
-	err = c.BlogModel.Delete(id)
+	err = c.BlogModel.Delete(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Failed to delete blog", http.StatusInternalServerError)
+		queryError(w, r, err, "Failed to delete blog", http.StatusInternalServerError)
 		return
 	}
 
+	c.recordAudit(r, user, models.AuditBlogDeleted, "blog", id, before, nil)
+
 	// Redirect to admin blogs list
 	http.Redirect(w, r, "/dashboard/admin/blogs", http.StatusSeeOther)
 }
 
+// Preview renders posted content to sanitized HTML for the live editor preview.
+// Drafts are rendered uncached, leaving the render cache to stored posts.
+func (c *BlogController) Preview(w http.ResponseWriter, r *http.Request) {
+	content := r.FormValue("content")
+	format := strings.TrimSpace(r.FormValue("format"))
+	if format == "" {
+		format = services.FormatMarkdown
+	}
+
+	if !services.IsValidFormat(format) {
+		respondError(w, http.StatusUnprocessableEntity, "validation_failed", "Invalid content format")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"html": services.RenderPreview(format, content),
+	})
+}
+
 // showCreateWithError displays create form with error
-func (c *BlogController) showCreateWithError(w http.ResponseWriter, r *http.Request, errorMsg, title, content string) {
+func (c *BlogController) showCreateWithError(w http.ResponseWriter, r *http.Request, errorMsg, title, slug, content string) {
 	user, _ := middleware.GetCurrentUser(r)
 	data := map[string]interface{}{
 		"Title":      "Create New Blog",
@@ -483,23 +604,202 @@ func (c *BlogController) showCreateWithError(w http.ResponseWriter, r *http.Requ
 		"Error":      errorMsg,
 		"OldTitle":   title,
 		"OldContent": content,
-	}
-	renderTemplate(w, "dashboard/blogs/create", data)
+		"FormData": map[string]interface{}{
+			"Title":   title,
+			"Slug":    slug,
+			"Content": content,
+			"Excerpt": r.FormValue("excerpt"),
+			"Status":  r.FormValue("status"),
+			"Format":  r.FormValue("format"),
+		},
+	}
+	taxonomy, _ := c.readTaxonomy(r)
+	c.addTaxonomyData(data, taxonomy)
+	addScheduleData(data, r.FormValue("status"), r.FormValue("publish_at"))
+	c.renderTemplate(w, r, "dashboard/blogs/create", data)
 }
 
 // showEditWithError displays edit form with error
-func (c *BlogController) showEditWithError(w http.ResponseWriter, r *http.Request, id int, errorMsg, title, content string) {
+func (c *BlogController) showEditWithError(w http.ResponseWriter, r *http.Request, id int, errorMsg, title, slug, content string) {
 	user, _ := middleware.GetCurrentUser(r)
 	blog := &models.Blog{
 		ID:      id,
 		Title:   title,
 		Content: content,
+		Format:  r.FormValue("format"),
+	}
+	if current, err := c.BlogModel.GetByID(r.Context(), id); err == nil {
+		blog.Slug = current.Slug
+		blog.Excerpt = current.Excerpt
+		blog.Status = current.Status
+		blog.UserID = current.UserID
+		blog.CreatedAt = current.CreatedAt
 	}
 	data := map[string]interface{}{
-		"Title": "Edit Blog",
-		"User":  user,
-		"Blog":  blog,
-		"Error": errorMsg,
+		"Title":   "Edit Blog",
+		"User":    user,
+		"Blog":    blog,
+		"OldSlug": slug,
+		"Error":   errorMsg,
+	}
+	c.addReviewData(data, user, blog)
+	taxonomy, _ := c.readTaxonomy(r)
+	c.addTaxonomyData(data, taxonomy)
+	addScheduleData(data, r.FormValue("status"), r.FormValue("publish_at"))
+	c.renderTemplate(w, r, "dashboard/blogs/edit", data)
+}
+
+// taxonomySelection holds the category and tags picked on the blog form
+type taxonomySelection struct {
+	CategoryID int // 0 means uncategorized
+	TagIDs     []int
+	NewTags    string // Comma-separated names of tags to create
+}
+
+// readTaxonomy reads the category and tag pickers from the submitted form.
+// An error is returned when the chosen category does not exist.
+func (c *BlogController) readTaxonomy(r *http.Request) (taxonomySelection, error) {
+	taxonomy := taxonomySelection{
+		NewTags: strings.TrimSpace(r.FormValue("new_tags")),
+	}
+
+	for _, value := range r.Form["tag_ids"] {
+		if id, err := strconv.Atoi(value); err == nil && id > 0 {
+			taxonomy.TagIDs = append(taxonomy.TagIDs, id)
+		}
+	}
+
+	if value := strings.TrimSpace(r.FormValue("category_id")); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return taxonomy, err
+		}
+		if _, err := c.CategoryModel.GetByID(id); err != nil {
+			return taxonomy, err
+		}
+		taxonomy.CategoryID = id
+	}
+
+	return taxonomy, nil
+}
+
+// syncTags attaches the selected tags to a blog, creating any new ones
+func (c *BlogController) syncTags(blogID int, taxonomy taxonomySelection) error {
+	tagIDs := taxonomy.TagIDs
+
+	if names := models.ParseTagNames(taxonomy.NewTags); len(names) > 0 {
+		newIDs, err := c.TagModel.FindOrCreate(names)
+		if err != nil {
+			return err
+		}
+		tagIDs = append(tagIDs, newIDs...)
+	}
+
+	return c.TagModel.SyncBlogTags(blogID, tagIDs)
+}
+
+// addTaxonomyData adds the category and tag picker options to template data
+func (c *BlogController) addTaxonomyData(data map[string]interface{}, taxonomy taxonomySelection) {
+	categories, err := c.CategoryModel.GetTree()
+	if err != nil {
+		categories = []*models.Category{} // Default to empty slice on error
 	}
-	renderTemplate(w, "dashboard/blogs/edit", data)
+
+	tags, err := c.TagModel.GetAll()
+	if err != nil {
+		tags = []*models.Tag{} // Default to empty slice on error
+	}
+
+	selectedTags := make(map[int]bool, len(taxonomy.TagIDs))
+	for _, id := range taxonomy.TagIDs {
+		selectedTags[id] = true
+	}
+
+	data["Categories"] = categories
+	data["Tags"] = tags
+	data["SelectedCategory"] = taxonomy.CategoryID
+	data["SelectedTags"] = selectedTags
+	data["NewTags"] = taxonomy.NewTags
+}
+
+// publishAtLayout is the value format of the datetime-local publish time input
+const publishAtLayout = "2006-01-02T15:04"
+
+// readSchedule reads the publish time submitted with the blog form for status.
+// It returns an error message when the status is unknown or a scheduled post
+// lacks a valid future publish time.
+func readSchedule(r *http.Request, status string) (*time.Time, string) {
+	if !models.IsValidBlogStatus(status) {
+		return nil, "Please choose a valid publication status"
+	}
+	if status != models.BlogScheduled {
+		return nil, ""
+	}
+
+	value := strings.TrimSpace(r.FormValue("publish_at"))
+	if value == "" {
+		return nil, validateSchedule(status, nil)
+	}
+
+	publishAt, err := time.ParseInLocation(publishAtLayout, value, time.Local)
+	if err != nil {
+		return nil, "Please enter a valid publish time"
+	}
+
+	return &publishAt, validateSchedule(status, &publishAt)
+}
+
+// validateSchedule returns an error message unless a scheduled post has a publish time in the future
+func validateSchedule(status string, publishAt *time.Time) string {
+	if status != models.BlogScheduled {
+		return ""
+	}
+	if publishAt == nil {
+		return "Please choose when the post should be published"
+	}
+	if !publishAt.After(time.Now()) {
+		return "The publish time must be in the future"
+	}
+	return ""
+}
+
+// formatPublishAt formats a publish time for the datetime-local input
+func formatPublishAt(publishAt *time.Time) string {
+	if publishAt == nil {
+		return ""
+	}
+	return publishAt.In(time.Local).Format(publishAtLayout)
+}
+
+// defaultBlogStatus is the status used when a form or request leaves it out:
+// published for users who may publish, otherwise draft
+func defaultBlogStatus(user *models.User) string {
+	if policies.CanPublishBlog(user) {
+		return models.BlogPublished
+	}
+	return models.BlogDraft
+}
+
+// statusDenied explains why CanSetBlogStatus refused to give a post status
+func statusDenied(status string) string {
+	if status == models.BlogChangesRequested {
+		return "Posts are sent back to their author from the review queue"
+	}
+	return "You don't have permission to publish posts; submit it for review instead"
+}
+
+// addReviewData adds a post's review history, and whether user may review it, to template data
+func (c *BlogController) addReviewData(data map[string]interface{}, user *models.User, blog *models.Blog) {
+	reviews, err := c.Notifier.ReviewModel.GetByBlogID(blog.ID)
+	if err != nil {
+		log.Printf("Blog Controller: failed to load reviews for blog %d: %v", blog.ID, err)
+	}
+	data["Reviews"] = reviews
+	data["CanReview"] = policies.CanReviewBlog(user, blog)
+}
+
+// addScheduleData adds the publication status and schedule fields to template data
+func addScheduleData(data map[string]interface{}, status, publishAt string) {
+	data["SelectedStatus"] = status
+	data["PublishAt"] = publishAt
 }
@@ -0,0 +1,332 @@
+// app/controllers/comment_controller.go - Handles blog comments and their moderation
+package controllers
+
+import (
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/bootstrap"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+// CommentController handles posting and moderating comments
+type CommentController struct {
+	*Controller
+	CommentModel *models.CommentModel
+	BlogModel    models.BlogRepository
+}
+
+// NewCommentController creates a new CommentController
+func NewCommentController(app *bootstrap.App) *CommentController {
+	return &CommentController{
+		Controller:   NewController(app),
+		CommentModel: app.Comments,
+		BlogModel:    app.Blogs,
+	}
+}
+
+// canModerate reports whether user may moderate comments on blog
+func canModerate(user *models.User, blog *models.Blog) bool {
+	return blog != nil && policies.CanModerateComments(user, blog.UserID)
+}
+
+// commentView is a comment prepared for display to a particular viewer
+type commentView struct {
+	*models.Comment
+	Replies     []*commentView
+	CanModerate bool // Viewer may approve, hide or delete the comment
+	CanReply    bool // Viewer may reply to the comment
+}
+
+// buildCommentViews threads a post's comments for display. Visitors see approved
+// comments plus their own pending ones; the post's author and admins see everything.
+func buildCommentViews(comments []*models.Comment, viewer *models.User, blog *models.Blog) []*commentView {
+	moderator := canModerate(viewer, blog)
+
+	roots := models.BuildCommentTree(comments, func(comment *models.Comment) bool {
+		return comment.IsApproved() || moderator || (viewer != nil && comment.UserID == viewer.ID && comment.IsPending())
+	})
+
+	var convert func(list []*models.Comment) []*commentView
+	convert = func(list []*models.Comment) []*commentView {
+		views := make([]*commentView, 0, len(list))
+		for _, comment := range list {
+			views = append(views, &commentView{
+				Comment:     comment,
+				Replies:     convert(comment.Replies),
+				CanModerate: moderator,
+				CanReply:    viewer != nil && comment.IsApproved(),
+			})
+		}
+		return views
+	}
+
+	return convert(roots)
+}
+
+// Store adds a comment or reply to a published blog post. Comments by the
+// post's author or a comment moderator are approved immediately; others await moderation.
+func (c *CommentController) Store(w http.ResponseWriter, r *http.Request) {
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	blogID, err := strconv.Atoi(r.FormValue("blog_id"))
+	if err != nil {
+		http.Error(w, "Invalid blog ID", http.StatusBadRequest)
+		return
+	}
+
+	blog, err := c.BlogModel.GetByID(r.Context(), blogID)
+	if err != nil || !blog.IsPublished() {
+		queryError(w, r, err, "Blog not found", http.StatusNotFound)
+		return
+	}
+
+	// Validate input
+	body := strings.TrimSpace(r.FormValue("body"))
+	if body == "" {
+		http.Redirect(w, r, blog.URL()+"?comment=empty#comments", http.StatusSeeOther)
+		return
+	}
+	if utf8.RuneCountInString(body) > models.MaxCommentLength {
+		http.Redirect(w, r, blog.URL()+"?comment=too-long#comments", http.StatusSeeOther)
+		return
+	}
+
+	moderator := canModerate(user, blog)
+
+	var parentID *int
+	if value := r.FormValue("parent_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Invalid parent comment", http.StatusBadRequest)
+			return
+		}
+
+		// Replies are only allowed to comments the user can see
+		parent, err := c.CommentModel.GetByID(id)
+		if err != nil || parent.BlogID != blog.ID || (!parent.IsApproved() && !moderator && parent.UserID != user.ID) {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+			return
+		}
+		parentID = &id
+	}
+
+	status := models.CommentPending
+	if moderator {
+		status = models.CommentApproved
+	}
+
+	comment, err := c.CommentModel.Create(blog.ID, user.ID, parentID, body, status)
+	if err != nil {
+		http.Error(w, "Failed to post comment", http.StatusInternalServerError)
+		return
+	}
+
+	if comment.IsPending() {
+		http.Redirect(w, r, blog.URL()+"?comment=pending#comment-"+strconv.Itoa(comment.ID), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, blog.URL()+"#comment-"+strconv.Itoa(comment.ID), http.StatusSeeOther)
+}
+
+// Approve makes a comment publicly visible (post author or admin)
+func (c *CommentController) Approve(w http.ResponseWriter, r *http.Request) {
+	c.moderate(w, r, models.CommentApproved)
+}
+
+// Hide removes a comment from public view without deleting it (post author or admin)
+func (c *CommentController) Hide(w http.ResponseWriter, r *http.Request) {
+	c.moderate(w, r, models.CommentHidden)
+}
+
+// Delete permanently deletes a comment and its replies (post author or admin)
+func (c *CommentController) Delete(w http.ResponseWriter, r *http.Request) {
+	comment, ok := c.moderatedComment(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.CommentModel.Delete(comment.ID); err != nil {
+		http.Error(w, "Failed to delete comment", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, c.redirectTarget(r, comment, false), http.StatusSeeOther)
+}
+
+// Queue lists comments awaiting moderation across all posts (requires comments.moderate)
+func (c *CommentController) Queue(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermCommentsModerate)
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if !models.IsValidCommentStatus(status) {
+		status = models.CommentPending
+	}
+
+	// Get page parameter from URL (default to 1)
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	// Calculate offset for pagination
+	limit := 20
+	offset := (page - 1) * limit
+
+	comments, err := c.CommentModel.GetByStatus(status, limit, offset)
+	if err != nil {
+		http.Error(w, "Failed to load comments", http.StatusInternalServerError)
+		return
+	}
+
+	// Count comments per status for the tabs and pagination
+	counts := map[string]int{}
+	for _, s := range []string{models.CommentPending, models.CommentApproved, models.CommentHidden} {
+		counts[s], _ = c.CommentModel.CountByStatus(s)
+	}
+
+	// Calculate pagination info
+	totalPages := (counts[status] + limit - 1) / limit // Ceiling division
+
+	data := map[string]interface{}{
+		"Title":      "Comment Moderation",
+		"User":       user,
+		"Comments":   comments,
+		"Status":     status,
+		"Counts":     counts,
+		"Updated":    r.URL.Query().Get("updated"),
+		"Page":       page,
+		"TotalPages": totalPages,
+		"HasNext":    page < totalPages,
+		"HasPrev":    page > 1,
+		"NextPage":   page + 1,
+		"PrevPage":   page - 1,
+		"BaseURL":    "/dashboard/comments",            // For pagination component
+		"PageQuery":  template.URL("status=" + status), // status is one of the fixed comment statuses
+	}
+
+	c.renderTemplate(w, r, "dashboard/comments", data)
+}
+
+// Bulk approves or rejects (hides) the selected comments from the moderation queue (requires comments.moderate)
+func (c *CommentController) Bulk(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authorize(w, r, models.PermCommentsModerate); !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form submission", http.StatusBadRequest)
+		return
+	}
+
+	var status string
+	switch r.FormValue("action") {
+	case "approve":
+		status = models.CommentApproved
+	case "reject":
+		status = models.CommentHidden
+	default:
+		http.Error(w, "Invalid bulk action", http.StatusBadRequest)
+		return
+	}
+
+	var ids []int
+	for _, value := range r.Form["ids"] {
+		if id, err := strconv.Atoi(value); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	updated, err := c.CommentModel.SetStatusBulk(ids, status)
+	if err != nil {
+		http.Error(w, "Failed to update comments", http.StatusInternalServerError)
+		return
+	}
+
+	returnStatus := r.FormValue("status")
+	if !models.IsValidCommentStatus(returnStatus) {
+		returnStatus = models.CommentPending
+	}
+
+	http.Redirect(w, r, "/dashboard/comments?status="+returnStatus+"&updated="+strconv.Itoa(updated), http.StatusSeeOther)
+}
+
+// moderate sets the status of the comment named in the URL
+func (c *CommentController) moderate(w http.ResponseWriter, r *http.Request, status string) {
+	comment, ok := c.moderatedComment(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.CommentModel.SetStatus(comment.ID, status); err != nil {
+		http.Error(w, "Failed to update comment", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, c.redirectTarget(r, comment, true), http.StatusSeeOther)
+}
+
+// moderatedComment loads the comment named in the URL, writing an error
+// response unless the current user may moderate it
+func (c *CommentController) moderatedComment(w http.ResponseWriter, r *http.Request) (*models.Comment, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	comment, err := c.CommentModel.GetByID(id)
+	if err != nil {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	blog, err := c.BlogModel.GetByID(r.Context(), comment.BlogID)
+	if err != nil {
+		queryError(w, r, err, "Blog not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	if !canModerate(user, blog) {
+		http.Error(w, "You don't have permission to moderate this comment", http.StatusForbidden)
+		return nil, false
+	}
+
+	return comment, true
+}
+
+// redirectTarget returns where to send the moderator afterwards: the local
+// path in the "redirect" form field, or else the comment on its post
+func (c *CommentController) redirectTarget(r *http.Request, comment *models.Comment, anchor bool) string {
+	target := r.FormValue("redirect")
+	if strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "//") && !strings.HasPrefix(target, "/\\") {
+		return target
+	}
+
+	if anchor {
+		return comment.BlogURL()
+	}
+	return (&models.Blog{ID: comment.BlogID, Slug: comment.BlogSlug}).URL() + "#comments"
+}
@@ -2,73 +2,308 @@
 package controllers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/bootstrap"
 	"html/template"
+	"log"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
+// Controller is embedded in every controller. It carries the application
+// container along with the helpers that depend on it.
+type Controller struct {
+	App *bootstrap.App
+}
+
+// NewController creates a Controller for app
+func NewController(app *bootstrap.App) *Controller {
+	return &Controller{App: app}
+}
+
 // renderTemplate renders a template with the given data
-func renderTemplate(w http.ResponseWriter, tmpl string, data interface{}) {
-	// Determine layout and template execution based on template path
-	var layoutPath string
-	var executeTemplate string
-
-	if strings.HasPrefix(tmpl, "dashboard/") {
-		// Dashboard templates use dashboard layout
-		layoutPath = "templates/dashboard/layout.html"
-		executeTemplate = "dashboard_layout"
-	} else {
-		// Other templates use base layout
-		layoutPath = "templates/layouts/base.html"
-		executeTemplate = "base"
-	}
-
-	templatePath := "templates/" + tmpl + ".html"
-
-	// Component template paths
-	componentPaths := []string{
-		"templates/components/header.html",
-		"templates/components/dashboard-header.html",
-		"templates/components/footer.html",
-		"templates/components/pagination.html",
-	}
-
-	// Create template with helper functions
-	t := template.New(executeTemplate).Funcs(template.FuncMap{
-		"split": strings.Split,
-		"slice": func(s string, start, end int) string {
-			if start >= len(s) {
-				return ""
-			}
-			if end > len(s) {
-				end = len(s)
-			}
-			return s[start:end]
-		},
-		"add": func(a, b int) int {
-			return a + b
+func (c *Controller) renderTemplate(w http.ResponseWriter, r *http.Request, tmpl string, data interface{}) {
+	c.renderTemplateStatus(w, r, http.StatusOK, tmpl, data)
+}
+
+// renderTemplateStatus renders a template with the given data and status code.
+// Templates are parsed at startup by the views registry, which also knows each
+// page's layout and partials (see views.Pages). Every template can use
+// {{csrfField}} in its forms and {{csrfToken}} for scripts; the first call
+// creates the session's token. Layouts include {{template "impersonation-banner" .}},
+// which uses {{impersonator}}.
+func (c *Controller) renderTemplateStatus(w http.ResponseWriter, r *http.Request, status int, tmpl string, data interface{}) {
+	if c.App.Views == nil {
+		http.Error(w, "Template error: templates are not loaded", http.StatusInternalServerError)
+		return
+	}
+
+	// The token is only created when the page uses it, so pages without forms
+	// don't start a session. It may set the session cookie, which still goes
+	// out before the body because the page is rendered into a buffer first.
+	csrfToken := ""
+	token := func() string {
+		if csrfToken == "" {
+			csrfToken = c.App.Middleware.CSRFToken(w, r)
+		}
+		return csrfToken
+	}
+
+	// Execute into a buffer so a failing template doesn't leave a half-written page
+	var buf bytes.Buffer
+	err := c.App.Views.Render(&buf, tmpl, data, template.FuncMap{
+		"csrfToken": token,
+		"csrfField": func() template.HTML {
+			return template.HTML(`<input type="hidden" name="` + middleware.CSRFFieldName + `" value="` + template.HTMLEscapeString(token()) + `" />`)
 		},
-		"asset": func(path string) string {
-			return "/public/" + path
+		"impersonator": func() *models.User {
+			return c.App.Middleware.GetImpersonator(r)
 		},
 	})
-
-	// Prepare all template files
-	allTemplateFiles := []string{layoutPath, templatePath}
-	allTemplateFiles = append(allTemplateFiles, componentPaths...)
-
-	// Parse template files
-	t, err := t.ParseFiles(allTemplateFiles...)
 	if err != nil {
 		http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Execute the appropriate template
-	err = t.ExecuteTemplate(w, executeTemplate, data)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// StatusPageExpired is the status code for requests with a missing or stale CSRF token
+const StatusPageExpired = 419
+
+// CSRFFailure answers a request rejected by the CSRF middleware: API clients get a
+// JSON error, browsers a page explaining that the form expired
+func (c *Controller) CSRFFailure(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/api/") || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		respondError(w, StatusPageExpired, "csrf_token_mismatch", "CSRF token missing or expired; reload the page and try again")
+		return
+	}
+
+	// Only offer to go back to a page on this site
+	back := ""
+	if referer, err := url.Parse(r.Referer()); err == nil && referer.Host == r.Host && referer.Path != "" {
+		back = referer.RequestURI()
+	}
+
+	c.renderTemplateStatus(w, r, StatusPageExpired, "errors/csrf", map[string]interface{}{
+		"Title": "Page Expired",
+		"Back":  back,
+	})
+}
+
+// authorize returns the logged in user, writing a 403 unless their role grants
+// permission and their API token, if any, may use it
+func authorize(w http.ResponseWriter, r *http.Request, permission string) (*models.User, bool) {
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil || user == nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	if !policies.Can(user, permission) {
+		http.Error(w, "Access denied. You need the '"+permission+"' permission.", http.StatusForbidden)
+		return nil, false
+	}
+
+	if !middleware.HasPermissionScope(r, permission) {
+		http.Error(w, "API token lacks the '"+models.ScopeAdmin+"' scope", http.StatusForbidden)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// canEditBlog is policies.CanEditBlog for this request: editing someone else's
+// post through blogs.edit_any also needs an admin-scoped token, if any
+func canEditBlog(r *http.Request, user *models.User, blog *models.Blog) bool {
+	if !policies.CanEditBlog(user, blog) {
+		return false
+	}
+	return user.ID == blog.UserID || policies.CanReviewBlog(user, blog) || middleware.HasPermissionScope(r, models.PermBlogsEditAny)
+}
+
+// canDeleteBlog is policies.CanDeleteBlog for this request: deleting someone
+// else's post also needs an admin-scoped token, if any
+func canDeleteBlog(r *http.Request, user *models.User, ownerID int) bool {
+	if !policies.CanDeleteBlog(user, ownerID) {
+		return false
+	}
+	return user.ID == ownerID || middleware.HasPermissionScope(r, models.PermBlogsDeleteAny)
+}
+
+// endSessions signs a user out everywhere. Bumping the session version covers
+// cookie sessions too; deleting the rows empties the device list.
+func (c *Controller) endSessions(ctx context.Context, userID int) error {
+	if err := c.App.Users.BumpSessionVersion(ctx, userID); err != nil {
+		return err
+	}
+
+	_, err := c.App.Sessions.DeleteAllForUser(userID, "")
+	return err
+}
+
+// queryError answers a request whose blog or user query failed: 499 when the
+// client went away, 503 when the query timed out, otherwise message and status
+func queryError(w http.ResponseWriter, r *http.Request, err error, message string, status int) {
+	if middleware.WriteInterrupted(w, r, err) {
+		return
+	}
+	http.Error(w, message, status)
+}
+
+// recordAudit stores an audit event for an action actor took on a target
+// record. before and after are stored as JSON; pass nil when not applicable.
+// Failures are logged rather than failing the request, as the action is done,
+// and the event is stored even if the client has already gone away.
+func (c *Controller) recordAudit(r *http.Request, actor *models.User, action, targetType string, targetID int, before, after interface{}) {
+	event := c.auditEvent(r, actor, action, targetType, targetID, before, after)
+	if err := c.App.Audit.Record(context.WithoutCancel(r.Context()), event); err != nil {
+		log.Printf("Audit error: %v", err)
+	}
+}
+
+// auditEvent builds the audit event recordAudit stores, for actions that must
+// record it in the same transaction as the change itself
+func (c *Controller) auditEvent(r *http.Request, actor *models.User, action, targetType string, targetID int, before, after interface{}) *models.AuditEvent {
+	event := &models.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		IPAddress:  c.App.Middleware.ClientIP(r),
+	}
+	if actor != nil {
+		event.ActorID = &actor.ID
+		event.ActorName = actor.Name
+	}
+	if targetID != 0 {
+		event.TargetID = &targetID
+	}
+	event.Before = auditJSON(before)
+	event.After = auditJSON(after)
+	return event
+}
+
+// deleteUser deletes a user with their posts and audits it in one transaction,
+// so the audit log holds every deletion and nothing is deleted unless it does
+func (c *Controller) deleteUser(r *http.Request, actor *models.User, id int) error {
+	return c.App.Transaction(r.Context(), func(ctx context.Context) error {
+		// Snapshot the user for the audit log; their blogs are deleted with them
+		var before map[string]interface{}
+		if user, err := c.App.Users.GetByID(ctx, id); err == nil {
+			before = auditUser(user)
+			before["blogs"], _ = c.App.Blogs.CountUserBlogs(ctx, id)
+		}
+
+		if err := c.App.Users.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return c.App.Audit.Record(ctx, c.auditEvent(r, actor, models.AuditUserDeleted, "user", id, before, nil))
+	})
+}
+
+// auditUser is the snapshot of a user stored in audit events
+func auditUser(user *models.User) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  user.Name,
+		"email": user.Email,
+		"role":  user.Role,
+	}
+}
+
+// auditBlog loads the snapshot of a blog post stored in audit events, or
+// returns nil if it cannot be loaded
+func auditBlog(ctx context.Context, blogs models.BlogRepository, id int) map[string]interface{} {
+	blog, err := blogs.GetByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"title":     blog.Title,
+		"slug":      blog.Slug,
+		"status":    blog.Status,
+		"author_id": blog.UserID,
+		"author":    blog.UserName,
+	}
+}
+
+// updateUser saves an admin's edit to a user and audits it in one
+// transaction. Setting a password also bumps the session version in it, so the
+// password never changes without signing out whoever used the old one. A nil
+// password leaves it unchanged.
+func (c *Controller) updateUser(r *http.Request, actor, before *models.User, name, email, role string, password *string) error {
+	err := c.App.Transaction(r.Context(), func(ctx context.Context) error {
+		if err := c.App.Users.Update(ctx, before.ID, name, email, role, password); err != nil {
+			return err
+		}
+
+		if password != nil {
+			if err := c.App.Users.BumpSessionVersion(ctx, before.ID); err != nil {
+				return err
+			}
+		}
+
+		if event := c.userUpdateEvent(r, actor, before, name, email, role, password != nil); event != nil {
+			return c.App.Audit.Record(ctx, event)
+		}
+		return nil
+	})
 	if err != nil {
-		http.Error(w, "Execute error: "+err.Error(), http.StatusInternalServerError)
+		return err
+	}
+
+	// The session rows can't join the transaction; the version bump has
+	// already signed them out, so this only empties the device list
+	if password != nil {
+		if _, err := c.App.Sessions.DeleteAllForUser(before.ID, ""); err != nil {
+			log.Printf("Session cleanup error: %v", err)
+		}
 	}
+	return nil
+}
+
+// userUpdateEvent builds the audit event for an edit to a user's account.
+// Edits that change the role are recorded as user.role_changed so they are
+// easy to find; edits that change nothing return nil.
+func (c *Controller) userUpdateEvent(r *http.Request, actor, before *models.User, name, email, role string, passwordChanged bool) *models.AuditEvent {
+	if name == before.Name && email == before.Email && role == before.Role && !passwordChanged {
+		return nil
+	}
+
+	action := models.AuditUserUpdated
+	if role != before.Role {
+		action = models.AuditUserRoleChanged
+	}
+
+	// The password itself is never recorded, only that it was changed
+	snapshot := auditUser(&models.User{Name: name, Email: email, Role: role})
+	if passwordChanged {
+		snapshot["password"] = "changed"
+	}
+	return c.auditEvent(r, actor, action, "user", before.ID, auditUser(before), snapshot)
+}
+
+// auditJSON encodes a value for an audit event, or "" for nil
+func auditJSON(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Audit error: failed to encode %T: %v", value, err)
+		return ""
+	}
+	return string(data)
 }
 
 // StaticFileHandler serves static files from the public directory
@@ -89,3 +324,106 @@ func formatTemplate(name string, data map[string]interface{}) map[string]interfa
 
 	return data
 }
+
+// generateExcerpt builds a default excerpt from the first 200 characters of content
+func generateExcerpt(content string) string {
+	if len(content) > 200 {
+		return content[:200] + "..."
+	}
+	return content
+}
+
+// PaginationMeta describes a page of results in JSON responses
+type PaginationMeta struct {
+	Page       int  `json:"page"`
+	PerPage    int  `json:"per_page"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+// newPaginationMeta calculates pagination metadata for a result set
+func newPaginationMeta(page, perPage, total int) PaginationMeta {
+	totalPages := (total + perPage - 1) / perPage // Ceiling division
+	return PaginationMeta{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// APIError is the error envelope returned by JSON endpoints
+type APIError struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// respondJSON writes payload as JSON with the given status code
+func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("Failed to encode JSON response: %v", err)
+	}
+}
+
+// respondError writes a JSON error envelope
+func respondError(w http.ResponseWriter, status int, code, message string) {
+	respondJSON(w, status, map[string]interface{}{
+		"error": APIError{Code: code, Message: message},
+	})
+}
+
+// respondQueryError is queryError for JSON endpoints
+func respondQueryError(w http.ResponseWriter, r *http.Request, err error, status int, code, message string) {
+	if middleware.WriteInterrupted(w, r, err) {
+		return
+	}
+	respondError(w, status, code, message)
+}
+
+// respondValidationError writes a 422 error envelope with per-field messages
+func respondValidationError(w http.ResponseWriter, fields map[string]string) {
+	respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"error": APIError{Code: "validation_failed", Message: "The given data was invalid", Fields: fields},
+	})
+}
+
+// respondPaginated writes a list of items along with pagination metadata
+func respondPaginated(w http.ResponseWriter, data interface{}, meta PaginationMeta) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data": data,
+		"meta": meta,
+	})
+}
+
+// siteURL returns the absolute base URL of the site for links in pages and
+// feeds, without a trailing slash. APP_URL is preferred; otherwise it is
+// derived from the request, believing X-Forwarded-Proto only when
+// TRUST_PROXY_HEADERS is on. Links in emails use mailURL instead.
+func (c *Controller) siteURL(r *http.Request) string {
+	if c.App.Config.AppURL != "" {
+		return strings.TrimRight(c.App.Config.AppURL, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil || (c.App.Config.TrustProxyHeaders && r.Header.Get("X-Forwarded-Proto") == "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// mailURL returns the base URL for links in emails, without a trailing slash.
+// It only ever comes from APP_URL: a link built from the request's Host header
+// would send reset tokens to whatever host the client named.
+func (c *Controller) mailURL() (string, error) {
+	if c.App.Config.AppURL == "" {
+		return "", errors.New("APP_URL is not set, refusing to email a link built from request headers")
+	}
+	return strings.TrimRight(c.App.Config.AppURL, "/"), nil
+}
@@ -2,9 +2,13 @@
 package controllers
 
 import (
+	"context"
 	"go-web-app/app/middleware"
 	"go-web-app/app/models"
-	"go-web-app/config"
+	"go-web-app/app/policies"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -14,15 +18,23 @@ import (
 
 // DashboardController handles dashboard pages
 type DashboardController struct {
-	UserModel *models.UserModel
-	BlogModel *models.BlogModel
+	*Controller
+	UserModel    models.UserRepository
+	BlogModel    models.BlogRepository
+	AttemptModel *models.LoginAttemptModel
+	SessionModel *models.SessionModel
+	RoleModel    *models.RoleModel
 }
 
 // NewDashboardController creates a new DashboardController
-func NewDashboardController() *DashboardController {
+func NewDashboardController(app *bootstrap.App) *DashboardController {
 	return &DashboardController{
-		UserModel: models.NewUserModel(config.Database),
-		BlogModel: models.NewBlogModel(config.Database),
+		Controller:   NewController(app),
+		UserModel:    app.Users,
+		BlogModel:    app.Blogs,
+		AttemptModel: app.LoginAttempts,
+		SessionModel: app.Sessions,
+		RoleModel:    app.Roles,
 	}
 }
 
@@ -36,15 +48,15 @@ func (c *DashboardController) Index(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get user's recent blogs
-	userBlogs, err := c.BlogModel.GetByUserID(user.ID)
+	userBlogs, err := c.BlogModel.GetByUserID(r.Context(), user.ID)
 	if err != nil {
 		userBlogs = []*models.Blog{} // Default to empty slice on error
 	}
 
 	// Get user's blog statistics
 	totalUserBlogs := len(userBlogs)
-	publishedUserBlogs, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "published")
-	draftUserBlogs, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "draft")
+	publishedUserBlogs, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "published")
+	draftUserBlogs, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "draft")
 
 	// Stats structure for template
 	stats := map[string]interface{}{
@@ -54,14 +66,14 @@ func (c *DashboardController) Index(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// For admin users, add global statistics
-	if user.IsAdmin() {
-		totalUsers, _ := c.UserModel.Count()
-		totalBlogs, _ := c.BlogModel.Count()
-		totalPublished, _ := c.BlogModel.CountByStatus("published")
-		totalDrafts, _ := c.BlogModel.CountByStatus("draft")
-		totalAdmins, _ := c.UserModel.CountByRole("admin")
-		totalAuthors, _ := c.UserModel.CountByRole("author")
-		totalRegularUsers, _ := c.UserModel.CountByRole("user")
+	if user.Can(models.PermUsersManage) {
+		totalUsers, _ := c.UserModel.Count(r.Context())
+		totalBlogs, _ := c.BlogModel.Count(r.Context())
+		totalPublished, _ := c.BlogModel.CountByStatus(r.Context(), "published")
+		totalDrafts, _ := c.BlogModel.CountByStatus(r.Context(), "draft")
+		totalAdmins, _ := c.UserModel.CountByRole(r.Context(), "admin")
+		totalAuthors, _ := c.UserModel.CountByRole(r.Context(), "author")
+		totalRegularUsers, _ := c.UserModel.CountByRole(r.Context(), "user")
 
 		stats["TotalUsers"] = totalUsers
 		stats["GlobalTotalBlogs"] = totalBlogs
@@ -74,13 +86,14 @@ func (c *DashboardController) Index(w http.ResponseWriter, r *http.Request) {
 
 	// Prepare data for template
 	data := map[string]interface{}{
-		"Title":       "Dashboard",
-		"User":        user,
-		"Stats":       stats,
-		"RecentBlogs": userBlogs[:min(len(userBlogs), 5)], // Show last 5 blogs
+		"Title":        "Dashboard",
+		"User":         user,
+		"Stats":        stats,
+		"RecentBlogs":  userBlogs[:min(len(userBlogs), 5)], // Show last 5 blogs
+		"Verification": r.URL.Query().Get("verification"),
 	}
 
-	renderTemplate(w, "dashboard/index", data)
+	c.renderTemplate(w, r, "dashboard/index", data)
 }
 
 // Profile displays the user profile page
@@ -93,13 +106,13 @@ func (c *DashboardController) Profile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get user's blog count and stats
-	userBlogs, err := c.BlogModel.GetByUserID(user.ID)
+	userBlogs, err := c.BlogModel.GetByUserID(r.Context(), user.ID)
 	if err != nil {
 		userBlogs = []*models.Blog{}
 	}
 
-	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "published")
-	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "draft")
+	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "published")
+	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "draft")
 
 	userStats := map[string]interface{}{
 		"TotalBlogs":     len(userBlogs),
@@ -115,7 +128,7 @@ func (c *DashboardController) Profile(w http.ResponseWriter, r *http.Request) {
 		"UserStats": userStats,
 	}
 
-	renderTemplate(w, "dashboard/profile", data)
+	c.renderProfile(w, r, data)
 }
 
 // ChangePassword handles password change for the current user
@@ -129,9 +142,9 @@ func (c *DashboardController) ChangePassword(w http.ResponseWriter, r *http.Requ
 
 	// Helper function to show profile with error
 	showProfileWithError := func(errorMsg string) {
-		userBlogs, _ := c.BlogModel.GetByUserID(user.ID)
-		publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "published")
-		draftCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "draft")
+		userBlogs, _ := c.BlogModel.GetByUserID(r.Context(), user.ID)
+		publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "published")
+		draftCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "draft")
 
 		userStats := map[string]interface{}{
 			"TotalBlogs":     len(userBlogs),
@@ -146,7 +159,7 @@ func (c *DashboardController) ChangePassword(w http.ResponseWriter, r *http.Requ
 			"UserStats": userStats,
 			"Error":     errorMsg,
 		}
-		renderTemplate(w, "dashboard/profile", data)
+		c.renderProfile(w, r, data)
 	}
 
 	// Get form data
@@ -170,8 +183,14 @@ func (c *DashboardController) ChangePassword(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Change password
-	err = c.UserModel.UpdateProfile(user.ID, user.Name, user.Email, currentPassword, &newPassword)
+	// Change password and bump the session version together, so a changed
+	// password always signs out every other device in case the old one leaked
+	err = c.App.Transaction(r.Context(), func(ctx context.Context) error {
+		if err := c.App.Users.UpdateProfile(ctx, user.ID, user.Name, user.Email, currentPassword, &newPassword); err != nil {
+			return err
+		}
+		return c.App.Users.BumpSessionVersion(ctx, user.ID)
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), "current password is incorrect") {
 			showProfileWithError("Current password is incorrect")
@@ -181,10 +200,17 @@ func (c *DashboardController) ChangePassword(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if updatedUser, err := c.UserModel.GetByID(r.Context(), user.ID); err == nil {
+		c.App.Middleware.SetUserSession(w, r, updatedUser)
+	}
+	if _, err := c.SessionModel.DeleteAllForUser(user.ID, c.App.Middleware.CurrentSessionHash(r)); err != nil {
+		log.Printf("Session cleanup error: %v", err)
+	}
+
 	// Show success message
-	userBlogs, _ := c.BlogModel.GetByUserID(user.ID)
-	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "published")
-	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "draft")
+	userBlogs, _ := c.BlogModel.GetByUserID(r.Context(), user.ID)
+	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "published")
+	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "draft")
 
 	userStats := map[string]interface{}{
 		"TotalBlogs":     len(userBlogs),
@@ -197,9 +223,9 @@ func (c *DashboardController) ChangePassword(w http.ResponseWriter, r *http.Requ
 		"User":      user,
 		"BlogCount": len(userBlogs),
 		"UserStats": userStats,
-		"Success":   "Password changed successfully",
+		"Success":   "Password changed successfully. Other devices have been signed out.",
 	}
-	renderTemplate(w, "dashboard/profile", data)
+	c.renderProfile(w, r, data)
 }
 
 // UpdateProfile updates the current user's profile
@@ -214,9 +240,9 @@ func (c *DashboardController) UpdateProfile(w http.ResponseWriter, r *http.Reque
 
 	// Helper function to show profile form with error
 	showProfileWithError := func(errorMsg string) {
-		userBlogs, _ := c.BlogModel.GetByUserID(user.ID)
-		publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "published")
-		draftCount, _ := c.BlogModel.CountUserBlogsByStatus(user.ID, "draft")
+		userBlogs, _ := c.BlogModel.GetByUserID(r.Context(), user.ID)
+		publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "published")
+		draftCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), user.ID, "draft")
 
 		userStats := map[string]interface{}{
 			"TotalBlogs":     len(userBlogs),
@@ -231,7 +257,7 @@ func (c *DashboardController) UpdateProfile(w http.ResponseWriter, r *http.Reque
 			"UserStats": userStats,
 			"Error":     errorMsg,
 		}
-		renderTemplate(w, "dashboard/profile", data)
+		c.renderProfile(w, r, data)
 	}
 
 	// Get form data
@@ -251,7 +277,7 @@ func (c *DashboardController) UpdateProfile(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Update profile (name and email only, without password change)
-	err = c.UserModel.UpdateProfile(user.ID, name, email, "", nil)
+	err = c.UserModel.UpdateProfile(r.Context(), user.ID, name, email, "", nil)
 	if err != nil {
 		if strings.Contains(err.Error(), "email already exists") {
 			showProfileWithError("Email address is already in use by another user")
@@ -262,15 +288,15 @@ func (c *DashboardController) UpdateProfile(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Get updated user data
-	updatedUser, err := c.UserModel.GetByID(user.ID)
+	updatedUser, err := c.UserModel.GetByID(r.Context(), user.ID)
 	if err != nil {
 		updatedUser = user // fallback to current user if error
 	}
 
 	// Get user stats
-	userBlogs, _ := c.BlogModel.GetByUserID(updatedUser.ID)
-	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(updatedUser.ID, "published")
-	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(updatedUser.ID, "draft")
+	userBlogs, _ := c.BlogModel.GetByUserID(r.Context(), updatedUser.ID)
+	publishedCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), updatedUser.ID, "published")
+	draftCount, _ := c.BlogModel.CountUserBlogsByStatus(r.Context(), updatedUser.ID, "draft")
 
 	userStats := map[string]interface{}{
 		"TotalBlogs":     len(userBlogs),
@@ -286,21 +312,22 @@ func (c *DashboardController) UpdateProfile(w http.ResponseWriter, r *http.Reque
 		"UserStats": userStats,
 		"Success":   "Profile updated successfully",
 	}
-	renderTemplate(w, "dashboard/profile", data)
+	c.renderProfile(w, r, data)
 }
 
-// Users displays all users (admin only)
-func (c *DashboardController) Users(w http.ResponseWriter, r *http.Request) {
-	// Get current user
-	currentUser, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
-		return
+// renderProfile renders the profile page, adding the user's signed-in devices
+func (c *DashboardController) renderProfile(w http.ResponseWriter, r *http.Request, data map[string]interface{}) {
+	if user, ok := data["User"].(*models.User); ok {
+		data["Sessions"] = c.userSessionViews(r, c.SessionModel, user.ID)
 	}
 
-	// Check if user is admin
-	if !currentUser.IsAdmin() {
-		http.Error(w, "Access denied. Admin privileges required.", http.StatusForbidden)
+	c.renderTemplate(w, r, "dashboard/profile", data)
+}
+
+// Users displays all users (requires users.manage)
+func (c *DashboardController) Users(w http.ResponseWriter, r *http.Request) {
+	currentUser, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
 		return
 	}
 
@@ -318,14 +345,14 @@ func (c *DashboardController) Users(w http.ResponseWriter, r *http.Request) {
 	offset := (page - 1) * limit
 
 	// Get users for current page
-	users, err := c.UserModel.GetAllPaginated(limit, offset)
+	users, err := c.UserModel.GetAllPaginated(r.Context(), limit, offset)
 	if err != nil {
-		http.Error(w, "Failed to load users", http.StatusInternalServerError)
+		queryError(w, r, err, "Failed to load users", http.StatusInternalServerError)
 		return
 	}
 
 	// Get total user count for pagination
-	totalUsers, err := c.UserModel.Count()
+	totalUsers, err := c.UserModel.Count(r.Context())
 	if err != nil {
 		totalUsers = 0 // Default to 0 if count fails
 	}
@@ -335,10 +362,24 @@ func (c *DashboardController) Users(w http.ResponseWriter, r *http.Request) {
 	hasNext := page < totalPages
 	hasPrev := page > 1
 
+	// List lookups skip permissions, so attach them from the roles to decide
+	// who can be impersonated (administrators cannot)
+	grants := map[string]map[string]bool{}
+	if roles, err := c.RoleModel.GetAll(); err == nil {
+		for _, role := range roles {
+			grants[role.Name] = role.Permissions
+		}
+	}
+	impersonatable := map[int]bool{}
+	for _, u := range users {
+		u.Permissions = grants[u.Role]
+		impersonatable[u.ID] = policies.CanImpersonate(currentUser, u)
+	}
+
 	// Calculate user role statistics
-	adminCount, _ := c.UserModel.CountByRole("admin")
-	authorCount, _ := c.UserModel.CountByRole("author")
-	userCount, _ := c.UserModel.CountByRole("user")
+	adminCount, _ := c.UserModel.CountByRole(r.Context(), "admin")
+	authorCount, _ := c.UserModel.CountByRole(r.Context(), "author")
+	userCount, _ := c.UserModel.CountByRole(r.Context(), "user")
 
 	// Prepare user stats
 	userStats := map[string]interface{}{
@@ -349,41 +390,33 @@ func (c *DashboardController) Users(w http.ResponseWriter, r *http.Request) {
 
 	// Prepare data for template
 	data := map[string]interface{}{
-		"Title":       "All Users",
-		"Users":       users,
-		"User":        currentUser,
-		"CurrentUser": currentUser,
-		"IsAdmin":     currentUser.IsAdmin(),
-		"UserStats":   userStats,
-		"Page":        page,
-		"TotalPages":  totalPages,
-		"HasNext":     hasNext,
-		"HasPrev":     hasPrev,
-		"NextPage":    page + 1,
-		"PrevPage":    page - 1,
-		"BaseURL":     "/dashboard/users", // For pagination component
-	}
-
-	renderTemplate(w, "dashboard/users", data)
+		"Title":          "All Users",
+		"Users":          users,
+		"User":           currentUser,
+		"CurrentUser":    currentUser,
+		"UserStats":      userStats,
+		"Impersonatable": impersonatable,
+		"Page":           page,
+		"TotalPages":     totalPages,
+		"HasNext":        hasNext,
+		"HasPrev":        hasPrev,
+		"NextPage":       page + 1,
+		"PrevPage":       page - 1,
+		"BaseURL":        "/dashboard/users", // For pagination component
+	}
+
+	c.renderTemplate(w, r, "dashboard/users", data)
 }
 
-// DeleteUser deletes a user (admin only)
+// DeleteUser deletes a user (requires users.manage)
 func (c *DashboardController) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Redirect(w, r, "/dashboard/users", http.StatusSeeOther)
 		return
 	}
 
-	// Get current user
-	currentUser, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
-		return
-	}
-
-	// Check if current user is admin
-	if !currentUser.IsAdmin() {
-		http.Error(w, "Access denied. Admin privileges required.", http.StatusForbidden)
+	currentUser, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
 		return
 	}
 
@@ -414,14 +447,16 @@ func (c *DashboardController) DeleteUser(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Delete user
-	err = c.UserModel.Delete(userID)
+	err = c.deleteUser(r, currentUser, userID)
 	if err != nil {
-		// Check if this is the main admin protection error
-		if err.Error() == "cannot delete the main administrator account" {
+		switch err.Error() {
+		case "user not found":
+			http.Error(w, "User not found", http.StatusNotFound)
+		case "cannot delete the main administrator account":
 			http.Error(w, "Cannot delete the main administrator account", http.StatusForbidden)
-			return
+		default:
+			queryError(w, r, err, "Failed to delete user", http.StatusInternalServerError)
 		}
-		http.Error(w, "Failed to delete user", http.StatusInternalServerError)
 		return
 	}
 
@@ -429,18 +464,10 @@ func (c *DashboardController) DeleteUser(w http.ResponseWriter, r *http.Request)
 	http.Redirect(w, r, "/dashboard/users", http.StatusSeeOther)
 }
 
-// EditUser shows the user edit form (admin only)
+// EditUser shows the user edit form (requires users.manage)
 func (c *DashboardController) EditUser(w http.ResponseWriter, r *http.Request) {
-	// Get current user
-	currentUser, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
-		return
-	}
-
-	// Check if current user is admin
-	if !currentUser.IsAdmin() {
-		http.Error(w, "Access denied. Admin privileges required.", http.StatusForbidden)
+	currentUser, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
 		return
 	}
 
@@ -465,34 +492,50 @@ func (c *DashboardController) EditUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get user to edit
-	editUser, err := c.UserModel.GetByID(userID)
+	editUser, err := c.UserModel.GetByID(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		queryError(w, r, err, "User not found", http.StatusNotFound)
 		return
 	}
 
+	roles, _ := c.RoleModel.GetAll()
+
 	// Prepare data for template
 	data := map[string]interface{}{
 		"Title":    "Edit User",
 		"User":     currentUser,
 		"EditUser": editUser,
+		"Roles":    roles,
 	}
 
-	renderTemplate(w, "dashboard/users/edit", data)
-}
+	if r.URL.Query().Get("unlocked") != "" {
+		data["Success"] = "Failed sign-in attempts cleared. The user can sign in again."
+	}
+	if r.URL.Query().Get("sessions") == "ended" {
+		data["Success"] = "All of this user's sessions have been terminated."
+	}
+	data["Sessions"] = c.userSessionViews(r, c.SessionModel, editUser.ID)
 
-// UpdateUser updates user information (admin only)
-func (c *DashboardController) UpdateUser(w http.ResponseWriter, r *http.Request) {
-	// Get current user
-	currentUser, err := middleware.GetCurrentUser(r)
-	if err != nil {
-		http.Error(w, "Failed to get user", http.StatusInternalServerError)
-		return
+	// Sign-in status: failed attempts and any backoff or lockout they caused
+	throttle := services.LoginEmailThrottle
+	if failures, sinceLast, err := c.AttemptModel.FailuresByEmail(editUser.Email, throttle.LockoutFor); err == nil {
+		wait, locked := throttle.Wait(failures, sinceLast)
+		data["FailedAttempts"] = failures
+		data["Locked"] = locked
+		if wait > 0 {
+			data["RetryIn"] = formatWait(wait)
+		}
 	}
+	attempts, _ := c.AttemptModel.GetRecent(editUser.Email, "", 10, 0)
+	data["Attempts"] = attempts
+
+	c.renderTemplate(w, r, "dashboard/users/edit", data)
+}
 
-	// Check if current user is admin
-	if !currentUser.IsAdmin() {
-		http.Error(w, "Access denied. Admin privileges required.", http.StatusForbidden)
+// UpdateUser updates user information (requires users.manage)
+func (c *DashboardController) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
 		return
 	}
 
@@ -517,21 +560,23 @@ func (c *DashboardController) UpdateUser(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get user to edit for error display
-	editUser, err := c.UserModel.GetByID(userID)
+	editUser, err := c.UserModel.GetByID(r.Context(), userID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+		queryError(w, r, err, "User not found", http.StatusNotFound)
 		return
 	}
 
 	// Helper function to show edit form with error
 	showEditWithError := func(errorMsg string) {
+		roles, _ := c.RoleModel.GetAll()
 		data := map[string]interface{}{
 			"Title":    "Edit User",
 			"User":     currentUser,
 			"EditUser": editUser,
+			"Roles":    roles,
 			"Error":    errorMsg,
 		}
-		renderTemplate(w, "dashboard/users/edit", data)
+		c.renderTemplate(w, r, "dashboard/users/edit", data)
 	}
 
 	// Get form data
@@ -547,7 +592,7 @@ func (c *DashboardController) UpdateUser(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Validate role
-	if role != "user" && role != "author" && role != "admin" {
+	if exists, err := c.RoleModel.Exists(role); err != nil || !exists {
 		showEditWithError("Invalid role selected")
 		return
 	}
@@ -564,7 +609,8 @@ func (c *DashboardController) UpdateUser(w http.ResponseWriter, r *http.Request)
 		passwordPtr = &password
 	}
 
-	err = c.UserModel.Update(userID, name, email, role, passwordPtr)
+	// A password set by an admin evicts whoever was signed in with the old one
+	err = c.updateUser(r, currentUser, editUser, name, email, role, passwordPtr)
 	if err != nil {
 		if strings.Contains(err.Error(), "email already exists") {
 			showEditWithError("Email address is already in use by another user")
@@ -574,6 +620,13 @@ func (c *DashboardController) UpdateUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Keep an admin who reset their own password signed in on this device, as ChangePassword does
+	if passwordPtr != nil && userID == currentUser.ID {
+		if updatedUser, err := c.UserModel.GetByID(r.Context(), userID); err == nil {
+			c.App.Middleware.SetUserSession(w, r, updatedUser)
+		}
+	}
+
 	// Redirect to users list after successful update
 	http.Redirect(w, r, "/dashboard/users", http.StatusSeeOther)
 }
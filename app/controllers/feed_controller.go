@@ -0,0 +1,160 @@
+// app/controllers/feed_controller.go - Handles RSS, Atom and JSON Feed syndication
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// feedSize is the number of most recent posts included in a feed
+const feedSize = 20
+
+// FeedController serves the site, author and tag feeds
+type FeedController struct {
+	*Controller
+	BlogModel models.BlogRepository
+	UserModel models.UserRepository
+	TagModel  *models.TagModel
+}
+
+// NewFeedController creates a new FeedController
+func NewFeedController(app *bootstrap.App) *FeedController {
+	return &FeedController{
+		Controller: NewController(app),
+		BlogModel:  app.Blogs,
+		UserModel:  app.Users,
+		TagModel:   app.Tags,
+	}
+}
+
+// RSS serves the feed as RSS 2.0 (/feed.xml)
+func (c *FeedController) RSS(w http.ResponseWriter, r *http.Request) {
+	c.serve(w, r, "application/rss+xml; charset=utf-8", (*services.Feed).RSS)
+}
+
+// Atom serves the feed as Atom 1.0 (/atom.xml)
+func (c *FeedController) Atom(w http.ResponseWriter, r *http.Request) {
+	c.serve(w, r, "application/atom+xml; charset=utf-8", (*services.Feed).Atom)
+}
+
+// JSON serves the feed as JSON Feed 1.1 (/feed.json)
+func (c *FeedController) JSON(w http.ResponseWriter, r *http.Request) {
+	c.serve(w, r, "application/feed+json; charset=utf-8", (*services.Feed).JSON)
+}
+
+// serve builds the feed named by the URL (site-wide, /author/{id}/... or
+// /tag/{slug}/...), encodes it and answers conditional GETs with 304
+func (c *FeedController) serve(w http.ResponseWriter, r *http.Request, contentType string, encode func(*services.Feed) ([]byte, error)) {
+	feed, ok := c.buildFeed(w, r)
+	if !ok {
+		return
+	}
+
+	body, err := encode(feed)
+	if err != nil {
+		http.Error(w, "Failed to generate feed", http.StatusInternalServerError)
+		return
+	}
+
+	// The ETag changes with any change to the feed, including deleted posts,
+	// which Last-Modified alone cannot reflect
+	sum := sha256.Sum256(body)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	// ServeContent handles If-None-Match / If-Modified-Since and HEAD requests
+	http.ServeContent(w, r, "", feed.Updated, bytes.NewReader(body))
+}
+
+// buildFeed loads the posts for the feed named by the URL, writing an error response on failure
+func (c *FeedController) buildFeed(w http.ResponseWriter, r *http.Request) (*services.Feed, bool) {
+	base := c.siteURL(r)
+	vars := mux.Vars(r)
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	path = path[strings.LastIndex(path, "/")+1:]
+
+	feed := &services.Feed{
+		Title:       "Go Blog",
+		Description: "The latest posts from Go Blog",
+		HomeURL:     base + "/",
+	}
+
+	var blogs []*models.Blog
+	var err error
+
+	switch {
+	case vars["id"] != "":
+		id, convErr := strconv.Atoi(vars["id"])
+		if convErr != nil {
+			http.Error(w, "Invalid author ID", http.StatusBadRequest)
+			return nil, false
+		}
+		author, getErr := c.UserModel.GetByID(r.Context(), id)
+		if getErr != nil {
+			http.Error(w, "Author not found", http.StatusNotFound)
+			return nil, false
+		}
+		feed.Title = "Go Blog: posts by " + author.Name
+		feed.Description = "The latest posts by " + author.Name
+		feed.FeedURL = base + "/author/" + strconv.Itoa(author.ID) + "/" + path
+		blogs, err = c.BlogModel.GetPublishedByUser(r.Context(), author.ID, feedSize, 0)
+
+	case vars["slug"] != "":
+		tag, getErr := c.TagModel.GetBySlug(vars["slug"])
+		if getErr != nil {
+			http.Error(w, "Tag not found", http.StatusNotFound)
+			return nil, false
+		}
+		feed.Title = "Go Blog: posts tagged " + tag.Name
+		feed.Description = "The latest posts tagged " + tag.Name
+		feed.HomeURL = base + tag.URL()
+		feed.FeedURL = base + tag.URL() + "/" + path
+		blogs, err = c.BlogModel.GetPublishedByTag(r.Context(), tag.ID, feedSize, 0)
+
+	default:
+		feed.FeedURL = base + "/" + path
+		blogs, err = c.BlogModel.GetAll(r.Context(), feedSize, 0)
+	}
+
+	if err != nil {
+		queryError(w, r, err, "Failed to load blogs", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	for _, blog := range blogs {
+		published := blog.CreatedAt
+		if blog.PublishedAt != nil {
+			published = *blog.PublishedAt
+		}
+		if blog.UpdatedAt.After(feed.Updated) {
+			feed.Updated = blog.UpdatedAt
+		}
+
+		// The ID is built from the post ID, which unlike the slug survives a
+		// rename, so readers don't show a renamed post again; it also resolves,
+		// as legacy numeric URLs redirect to the current slug
+		url := base + blog.URL()
+		feed.Items = append(feed.Items, services.FeedItem{
+			ID:          base + "/blog/" + strconv.Itoa(blog.ID),
+			URL:         url,
+			Title:       blog.Title,
+			Summary:     blog.Excerpt,
+			ContentHTML: string(services.RenderContent(blog.Format, blog.Content)),
+			Author:      blog.UserName,
+			Published:   published,
+			Updated:     blog.UpdatedAt,
+		})
+	}
+
+	return feed, true
+}
@@ -2,9 +2,9 @@
 package controllers
 
 import (
-	"go-web-app/app/middleware"
 	"go-web-app/app/models"
-	"go-web-app/config"
+	"go-web-app/app/policies"
+	"go-web-app/bootstrap"
 	"net/http"
 	"strconv"
 
@@ -13,15 +13,23 @@ import (
 
 // HomeController handles public pages
 type HomeController struct {
-	BlogModel *models.BlogModel
-	UserModel *models.UserModel
+	*Controller
+	BlogModel     models.BlogRepository
+	UserModel     models.UserRepository
+	CategoryModel *models.CategoryModel
+	TagModel      *models.TagModel
+	CommentModel  *models.CommentModel
 }
 
 // NewHomeController creates a new HomeController
-func NewHomeController() *HomeController {
+func NewHomeController(app *bootstrap.App) *HomeController {
 	return &HomeController{
-		BlogModel: models.NewBlogModel(config.Database),
-		UserModel: models.NewUserModel(config.Database),
+		Controller:    NewController(app),
+		BlogModel:     app.Blogs,
+		UserModel:     app.Users,
+		CategoryModel: app.Categories,
+		TagModel:      app.Tags,
+		CommentModel:  app.Comments,
 	}
 }
 
@@ -41,14 +49,14 @@ func (c *HomeController) Index(w http.ResponseWriter, r *http.Request) {
 	offset := (page - 1) * limit
 
 	// Get blogs for current page
-	blogs, err := c.BlogModel.GetAll(limit, offset)
+	blogs, err := c.BlogModel.GetAll(r.Context(), limit, offset)
 	if err != nil {
-		http.Error(w, "Failed to load blogs", http.StatusInternalServerError)
+		queryError(w, r, err, "Failed to load blogs", http.StatusInternalServerError)
 		return
 	}
 
 	// Get total blog count for pagination
-	totalBlogs, err := c.BlogModel.Count()
+	totalBlogs, err := c.BlogModel.CountPublished(r.Context())
 	if err != nil {
 		totalBlogs = 0 // Default to 0 if count fails
 	}
@@ -59,7 +67,7 @@ func (c *HomeController) Index(w http.ResponseWriter, r *http.Request) {
 	hasPrev := page > 1
 
 	// Check if user is logged in (using session for homepage)
-	user, _ := middleware.GetCurrentUserFromSession(r) // Don't show error for homepage
+	user, _ := c.App.Middleware.GetCurrentUserFromSession(r) // Don't show error for homepage
 
 	// Prepare data for template
 	data := map[string]interface{}{
@@ -75,41 +83,224 @@ func (c *HomeController) Index(w http.ResponseWriter, r *http.Request) {
 		"BaseURL":    "/", // For pagination component
 	}
 
-	renderTemplate(w, "home", data)
+	c.renderTemplate(w, r, "home", data)
 }
 
-// ShowBlog displays a single blog post
+// ShowBlog displays a single blog post by slug.
+// Legacy numeric URLs and previous slugs are permanently redirected to the current slug.
+// Posts that aren't published yet are only found by those who may edit them.
 func (c *HomeController) ShowBlog(w http.ResponseWriter, r *http.Request) {
-	// Get blog ID from URL
+	// Get blog slug from URL
 	vars := mux.Vars(r)
-	idStr, ok := vars["id"]
-	if !ok {
-		http.Error(w, "Blog ID is required", http.StatusBadRequest)
+	slug, ok := vars["slug"]
+	if !ok || slug == "" {
+		http.Error(w, "Blog slug is required", http.StatusBadRequest)
 		return
 	}
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid blog ID", http.StatusBadRequest)
+	// Legacy /blog/{id} URLs
+	if id, err := strconv.Atoi(slug); err == nil {
+		blog, err := c.BlogModel.GetByID(r.Context(), id)
+		if err != nil || !c.canView(r, blog) {
+			queryError(w, r, err, "Blog not found", http.StatusNotFound)
+			return
+		}
+		if blog.Slug != "" {
+			http.Redirect(w, r, blog.URL(), http.StatusMovedPermanently)
+			return
+		}
+		c.renderBlog(w, r, blog)
 		return
 	}
 
-	// Get blog by ID
-	blog, err := c.BlogModel.GetByID(id)
+	// Get blog by slug
+	blog, err := c.BlogModel.GetBySlug(r.Context(), slug)
 	if err != nil {
+		// The slug may have been replaced after a title change
+		blogID, redirectErr := c.BlogModel.FindRedirect(r.Context(), slug)
+		if redirectErr != nil {
+			queryError(w, r, redirectErr, "Blog not found", http.StatusNotFound)
+			return
+		}
+
+		blog, err = c.BlogModel.GetByID(r.Context(), blogID)
+		if err != nil || !c.canView(r, blog) {
+			queryError(w, r, err, "Blog not found", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, blog.URL(), http.StatusMovedPermanently)
+		return
+	}
+
+	if !c.canView(r, blog) {
 		http.Error(w, "Blog not found", http.StatusNotFound)
 		return
 	}
 
+	c.renderBlog(w, r, blog)
+}
+
+// canView reports whether the visitor may see blog: anyone once it is
+// published, before that (draft, scheduled, in review) only its editors
+func (c *HomeController) canView(r *http.Request, blog *models.Blog) bool {
+	if blog.IsPublished() {
+		return true
+	}
+	user, _ := c.App.Middleware.GetCurrentUserFromSession(r)
+	return policies.CanViewBlog(user, blog)
+}
+
+// renderBlog renders the public page for a single blog post
+func (c *HomeController) renderBlog(w http.ResponseWriter, r *http.Request, blog *models.Blog) {
 	// Get current user (if logged in)
-	user, _ := middleware.GetCurrentUserFromSession(r)
+	user, _ := c.App.Middleware.GetCurrentUserFromSession(r)
+
+	// Load tags for display; a failure only hides them
+	if tags, err := c.TagModel.GetByBlogID(blog.ID); err == nil {
+		blog.Tags = tags
+	}
+
+	// Load comments; a failure only hides them
+	comments, err := c.CommentModel.GetByBlogID(blog.ID)
+	if err != nil {
+		comments = []*models.Comment{}
+	}
+
+	approved := 0
+	for _, comment := range comments {
+		if comment.IsApproved() {
+			approved++
+		}
+	}
 
 	// Prepare data for template
 	data := map[string]interface{}{
-		"Title": blog.Title,
-		"Blog":  blog,
-		"User":  user, // Add user data to template
+		"Title":            blog.Title,
+		"Blog":             blog,
+		"User":             user, // Add user data to template
+		"Comments":         buildCommentViews(comments, user, blog),
+		"CommentCount":     approved,
+		"CanComment":       user != nil && blog.IsPublished(),
+		"CommentNotice":    r.URL.Query().Get("comment"),
+		"MaxCommentLength": models.MaxCommentLength,
+		"FeedPath":         "/author/" + strconv.Itoa(blog.UserID), // Autodiscovery for the author's feed
+		"FeedTitle":        "Go Blog: posts by " + blog.UserName,
+	}
+
+	c.renderTemplate(w, r, "blog/show", data)
+}
+
+// ShowCategory lists published posts in a category and all of its subcategories
+func (c *HomeController) ShowCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := c.CategoryModel.GetBySlug(mux.Vars(r)["slug"])
+	if err != nil {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
 	}
 
-	renderTemplate(w, "blog/show", data)
+	categoryIDs, err := c.CategoryModel.DescendantIDs(category.ID)
+	if err != nil {
+		http.Error(w, "Failed to load category", http.StatusInternalServerError)
+		return
+	}
+
+	page, limit, offset := archivePage(r)
+
+	// Get blogs for current page
+	blogs, err := c.BlogModel.GetPublishedByCategories(r.Context(), categoryIDs, limit, offset)
+	if err != nil {
+		queryError(w, r, err, "Failed to load blogs", http.StatusInternalServerError)
+		return
+	}
+
+	// Get total blog count for pagination
+	totalBlogs, err := c.BlogModel.CountPublishedByCategories(r.Context(), categoryIDs)
+	if err != nil {
+		totalBlogs = 0 // Default to 0 if count fails
+	}
+
+	subcategories, _ := c.CategoryModel.GetChildren(category.ID)
+
+	var parent *models.Category
+	if category.ParentID != nil {
+		parent, _ = c.CategoryModel.GetByID(*category.ParentID)
+	}
+
+	c.renderArchive(w, r, page, limit, totalBlogs, map[string]interface{}{
+		"Title":          category.Name,
+		"Heading":        category.Name,
+		"Description":    category.Description,
+		"Category":       category,
+		"ParentCategory": parent,
+		"Subcategories":  subcategories,
+		"Blogs":          blogs,
+		"BaseURL":        category.URL(), // For pagination component
+	})
+}
+
+// ShowTag lists published posts carrying a tag
+func (c *HomeController) ShowTag(w http.ResponseWriter, r *http.Request) {
+	tag, err := c.TagModel.GetBySlug(mux.Vars(r)["slug"])
+	if err != nil {
+		http.Error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+
+	page, limit, offset := archivePage(r)
+
+	// Get blogs for current page
+	blogs, err := c.BlogModel.GetPublishedByTag(r.Context(), tag.ID, limit, offset)
+	if err != nil {
+		queryError(w, r, err, "Failed to load blogs", http.StatusInternalServerError)
+		return
+	}
+
+	// Get total blog count for pagination
+	totalBlogs, err := c.BlogModel.CountPublishedByTag(r.Context(), tag.ID)
+	if err != nil {
+		totalBlogs = 0 // Default to 0 if count fails
+	}
+
+	c.renderArchive(w, r, page, limit, totalBlogs, map[string]interface{}{
+		"Title":     "Posts tagged " + tag.Name,
+		"Heading":   tag.Name,
+		"Tag":       tag,
+		"Blogs":     blogs,
+		"BaseURL":   tag.URL(), // For pagination component
+		"FeedPath":  tag.URL(), // Autodiscovery for the tag's feed
+		"FeedTitle": "Go Blog: posts tagged " + tag.Name,
+	})
+}
+
+// archivePage reads the page parameter and returns the page, page size and offset
+func archivePage(r *http.Request) (page, limit, offset int) {
+	// Get page parameter from URL (default to 1)
+	page = 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	limit = 12
+	offset = (page - 1) * limit
+	return page, limit, offset
+}
+
+// renderArchive adds pagination info to a category or tag listing and renders it
+func (c *HomeController) renderArchive(w http.ResponseWriter, r *http.Request, page, limit, total int, data map[string]interface{}) {
+	// Calculate pagination info
+	totalPages := (total + limit - 1) / limit // Ceiling division
+
+	// Check if user is logged in (for navigation)
+	user, _ := c.App.Middleware.GetCurrentUserFromSession(r)
+
+	data["User"] = user
+	data["Page"] = page
+	data["TotalPages"] = totalPages
+	data["HasNext"] = page < totalPages
+	data["HasPrev"] = page > 1
+	data["NextPage"] = page + 1
+	data["PrevPage"] = page - 1
+
+	c.renderTemplate(w, r, "blog/archive", data)
 }
@@ -0,0 +1,133 @@
+// app/controllers/impersonation_controller.go - Lets admins sign in as a user to see what they see
+package controllers
+
+import (
+	"context"
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/bootstrap"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ImpersonationController handles starting and stopping impersonation
+type ImpersonationController struct {
+	*Controller
+	UserModel models.UserRepository
+}
+
+// NewImpersonationController creates a new ImpersonationController
+func NewImpersonationController(app *bootstrap.App) *ImpersonationController {
+	return &ImpersonationController{
+		Controller: NewController(app),
+		UserModel:  app.Users,
+	}
+}
+
+// Start signs the admin in as the user in the URL (requires users.manage).
+// Administrators cannot be impersonated.
+func (c *ImpersonationController) Start(w http.ResponseWriter, r *http.Request) {
+	admin, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
+		return
+	}
+
+	if middleware.GetAPIToken(r) != nil {
+		http.Error(w, "Impersonation requires a browser session", http.StatusForbidden)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	target, err := c.UserModel.GetByID(r.Context(), id)
+	if err != nil {
+		queryError(w, r, err, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if !policies.CanImpersonate(admin, target) {
+		http.Error(w, "Administrators cannot be impersonated", http.StatusForbidden)
+		return
+	}
+
+	if err := c.App.Middleware.StartImpersonation(w, r, admin, target); err != nil {
+		log.Printf("Impersonation error: %v", err)
+		http.Error(w, "Failed to impersonate user", http.StatusInternalServerError)
+		return
+	}
+
+	c.record(r, admin, target, models.ImpersonationStarted)
+	log.Printf("Impersonation: %s started impersonating %s", admin.Email, target.Email)
+
+	http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+}
+
+// Stop ends impersonation and signs the admin back in as themselves. It works
+// even if the impersonated user has since been signed out everywhere.
+func (c *ImpersonationController) Stop(w http.ResponseWriter, r *http.Request) {
+	admin := c.App.Middleware.GetImpersonator(r)
+	if admin == nil {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	// The user is looked up before switching back, while the session is still theirs
+	target, _ := c.App.Middleware.GetCurrentUserFromSession(r)
+
+	if _, err := c.App.Middleware.StopImpersonation(w, r); err != nil {
+		log.Printf("Impersonation error: %v", err)
+		http.Error(w, "Failed to stop impersonating", http.StatusInternalServerError)
+		return
+	}
+
+	targetID := 0
+	if target != nil {
+		targetID = target.ID
+	}
+	c.record(r, admin, target, models.ImpersonationStopped)
+	log.Printf("Impersonation: %s stopped impersonating user %d", admin.Email, targetID)
+
+	http.Redirect(w, r, "/dashboard/users", http.StatusSeeOther)
+}
+
+// record stores the start or stop of an impersonation, in the audit log and
+// the impersonation history together. Failures are logged rather than failing
+// the request, as the session has already switched.
+func (c *ImpersonationController) record(r *http.Request, admin, target *models.User, action string) {
+	event := &models.ImpersonationEvent{
+		AdminID:   &admin.ID,
+		AdminName: admin.Name,
+		Action:    action,
+		IPAddress: c.App.Middleware.ClientIP(r),
+	}
+	auditAction := models.AuditImpersonationStarted
+	if action == models.ImpersonationStopped {
+		auditAction = models.AuditImpersonationStopped
+	}
+	targetID := 0
+	var after interface{}
+	if target != nil {
+		event.UserID = &target.ID
+		event.UserName = target.Name
+		targetID = target.ID
+		after = map[string]interface{}{"name": target.Name, "email": target.Email}
+	}
+
+	err := c.App.Transaction(context.WithoutCancel(r.Context()), func(ctx context.Context) error {
+		if err := c.App.Impersonations.Record(ctx, event); err != nil {
+			return err
+		}
+		return c.App.Audit.Record(ctx, c.auditEvent(r, admin, auditAction, "user", targetID, nil, after))
+	})
+	if err != nil {
+		log.Printf("Impersonation error: %v", err)
+	}
+}
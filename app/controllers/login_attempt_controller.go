@@ -0,0 +1,119 @@
+// app/controllers/login_attempt_controller.go - Handles the admin login activity log
+package controllers
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/bootstrap"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// LoginAttemptController handles viewing login attempts and unlocking accounts
+type LoginAttemptController struct {
+	*Controller
+	UserModel    models.UserRepository
+	AttemptModel *models.LoginAttemptModel
+}
+
+// NewLoginAttemptController creates a new LoginAttemptController
+func NewLoginAttemptController(app *bootstrap.App) *LoginAttemptController {
+	return &LoginAttemptController{
+		Controller:   NewController(app),
+		UserModel:    app.Users,
+		AttemptModel: app.LoginAttempts,
+	}
+}
+
+// Index lists recent login attempts, optionally filtered by email or IP (requires users.manage)
+func (c *LoginAttemptController) Index(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
+		return
+	}
+
+	email := strings.TrimSpace(r.URL.Query().Get("email"))
+	ip := strings.TrimSpace(r.URL.Query().Get("ip"))
+
+	// Get page parameter from URL (default to 1)
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	limit := 25
+	offset := (page - 1) * limit
+
+	attempts, err := c.AttemptModel.GetRecent(email, ip, limit, offset)
+	if err != nil {
+		http.Error(w, "Failed to load login attempts", http.StatusInternalServerError)
+		return
+	}
+
+	total, err := c.AttemptModel.Count(email, ip)
+	if err != nil {
+		total = 0 // Default to 0 if count fails
+	}
+	totalPages := (total + limit - 1) / limit // Ceiling division
+
+	pageQuery := url.Values{}
+	if email != "" {
+		pageQuery.Set("email", email)
+	}
+	if ip != "" {
+		pageQuery.Set("ip", ip)
+	}
+
+	data := map[string]interface{}{
+		"Title":      "Login Activity",
+		"User":       user,
+		"Attempts":   attempts,
+		"Filters":    map[string]string{"Email": email, "IP": ip},
+		"Total":      total,
+		"Page":       page,
+		"TotalPages": totalPages,
+		"HasNext":    page < totalPages,
+		"HasPrev":    page > 1,
+		"NextPage":   page + 1,
+		"PrevPage":   page - 1,
+		"BaseURL":    "/dashboard/login-attempts",      // For pagination component
+		"PageQuery":  template.URL(pageQuery.Encode()), // Encoded by url.Values, safe to pass through
+	}
+
+	c.renderTemplate(w, r, "dashboard/login-attempts", data)
+}
+
+// Unlock clears a user's failed login attempts, lifting any backoff or lockout (requires users.manage)
+func (c *LoginAttemptController) Unlock(w http.ResponseWriter, r *http.Request) {
+	admin, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	lockedUser, err := c.UserModel.GetByID(r.Context(), id)
+	if err != nil {
+		queryError(w, r, err, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if err := c.AttemptModel.Clear(lockedUser.Email); err != nil {
+		log.Printf("Unlock error: %v", err)
+		http.Error(w, "Failed to unlock user", http.StatusInternalServerError)
+		return
+	}
+
+	c.recordAudit(r, admin, models.AuditUserUnlocked, "user", id, nil, nil)
+
+	http.Redirect(w, r, "/dashboard/users/"+strconv.Itoa(id)+"/edit?unlocked=1", http.StatusSeeOther)
+}
@@ -0,0 +1,162 @@
+// app/controllers/password_reset_controller.go - Handles forgotten passwords
+package controllers
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// PasswordResetController emails reset links and lets guests choose a new password
+type PasswordResetController struct {
+	*Controller
+	UserModel    models.UserRepository
+	ResetModel   *models.PasswordResetModel
+	SessionModel *models.SessionModel
+	Mailer       services.Mailer
+}
+
+// NewPasswordResetController creates a new PasswordResetController
+func NewPasswordResetController(app *bootstrap.App) *PasswordResetController {
+	return &PasswordResetController{
+		Controller:   NewController(app),
+		UserModel:    app.Users,
+		ResetModel:   app.PasswordResets,
+		SessionModel: app.Sessions,
+		Mailer:       app.Mailer,
+	}
+}
+
+// ShowForgot displays the form asking for the account's email address
+func (c *PasswordResetController) ShowForgot(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"Title": "Forgot Password",
+	}
+
+	c.renderTemplate(w, r, "auth/forgot-password", data)
+}
+
+// SendLink emails a reset link to the account with the given address. The
+// response is the same whether or not the account exists, so the form cannot
+// be used to discover registered addresses.
+func (c *PasswordResetController) SendLink(w http.ResponseWriter, r *http.Request) {
+	email := strings.TrimSpace(r.FormValue("email"))
+	if email == "" {
+		c.renderTemplate(w, r, "auth/forgot-password", map[string]interface{}{
+			"Title": "Forgot Password",
+			"Error": "Email is required",
+		})
+		return
+	}
+
+	if user, err := c.UserModel.GetByEmail(r.Context(), email); err == nil {
+		if err := c.sendResetEmail(user); err != nil {
+			log.Printf("Password reset error: %v", err)
+		}
+	}
+
+	c.renderTemplate(w, r, "auth/forgot-password", map[string]interface{}{
+		"Title":   "Forgot Password",
+		"Success": "If an account exists for " + email + ", we've emailed a link to reset its password. The link expires in 1 hour.",
+	})
+}
+
+// sendResetEmail issues a reset token for user and emails them the link
+func (c *PasswordResetController) sendResetEmail(user *models.User) error {
+	base, err := c.mailURL()
+	if err != nil {
+		return err
+	}
+
+	token, err := c.ResetModel.Create(user.ID)
+	if err != nil {
+		return err
+	}
+
+	link := base + "/reset-password/" + token
+
+	return c.Mailer.Send(services.Message{
+		To:      user.Email,
+		Subject: "Reset your Go Blog password",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Someone (hopefully you) asked to reset the password for your Go Blog account.\n" +
+			"Choose a new password here:\n\n" +
+			link + "\n\n" +
+			"The link expires in 1 hour and can only be used once. Resetting your password\n" +
+			"signs you out on every device.\n\n" +
+			"If you didn't ask for this, you can ignore this email; your password won't change.\n",
+	})
+}
+
+// ShowReset displays the new password form for a reset link
+func (c *PasswordResetController) ShowReset(w http.ResponseWriter, r *http.Request) {
+	// Keep the token out of Referer headers sent to other sites
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	token := mux.Vars(r)["token"]
+	data := map[string]interface{}{
+		"Title": "Reset Password",
+		"Token": token,
+	}
+
+	if _, err := c.ResetModel.FindValid(token); err != nil {
+		data["Invalid"] = true
+	}
+
+	c.renderTemplate(w, r, "auth/reset-password", data)
+}
+
+// Reset sets the new password and signs the user out of every existing session
+func (c *PasswordResetController) Reset(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	token := r.FormValue("token")
+	password := r.FormValue("password")
+	passwordConfirmation := r.FormValue("password_confirmation")
+
+	showError := func(errorMsg string) {
+		c.renderTemplate(w, r, "auth/reset-password", map[string]interface{}{
+			"Title": "Reset Password",
+			"Token": token,
+			"Error": errorMsg,
+		})
+	}
+
+	// Validate input
+	if password == "" {
+		showError("Password is required")
+		return
+	}
+
+	if password != passwordConfirmation {
+		showError("Passwords do not match")
+		return
+	}
+
+	if len(password) < 6 {
+		showError("Password must be at least 6 characters long")
+		return
+	}
+
+	userID, err := c.ResetModel.Reset(r.Context(), token, password)
+	if err != nil {
+		log.Printf("Password reset error: %v", err)
+		c.renderTemplate(w, r, "auth/reset-password", map[string]interface{}{
+			"Title":   "Reset Password",
+			"Invalid": true,
+		})
+		return
+	}
+
+	// The session version bump already signed them out; drop the stored sessions too
+	if _, err := c.SessionModel.DeleteAllForUser(userID, ""); err != nil {
+		log.Printf("Password reset error: %v", err)
+	}
+
+	http.Redirect(w, r, "/login?reset=1", http.StatusSeeOther)
+}
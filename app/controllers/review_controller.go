@@ -0,0 +1,239 @@
+// app/controllers/review_controller.go - Handles the editorial review queue
+package controllers
+
+import (
+	"fmt"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// ReviewController handles the queue of posts waiting for an editor
+type ReviewController struct {
+	*Controller
+	BlogModel models.BlogRepository
+	Notifier  *reviewNotifier
+}
+
+// NewReviewController creates a new ReviewController
+func NewReviewController(app *bootstrap.App) *ReviewController {
+	return &ReviewController{
+		Controller: NewController(app),
+		BlogModel:  app.Blogs,
+		Notifier:   newReviewNotifier(app),
+	}
+}
+
+// Index lists posts waiting for review, oldest submission first (requires blogs.review)
+func (c *ReviewController) Index(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermBlogsReview)
+	if !ok {
+		return
+	}
+
+	// Get page parameter from URL (default to 1)
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	limit := 12
+	offset := (page - 1) * limit
+
+	blogs, err := c.BlogModel.GetByStatus(r.Context(), models.BlogPendingReview, limit, offset)
+	if err != nil {
+		queryError(w, r, err, "Failed to load the review queue", http.StatusInternalServerError)
+		return
+	}
+
+	total, err := c.BlogModel.CountByStatus(r.Context(), models.BlogPendingReview)
+	if err != nil {
+		total = 0 // Default to 0 if count fails
+	}
+
+	totalPages := (total + limit - 1) / limit // Ceiling division
+
+	data := map[string]interface{}{
+		"Title":      "Review Queue",
+		"User":       user,
+		"Blogs":      blogs,
+		"Total":      total,
+		"Page":       page,
+		"TotalPages": totalPages,
+		"HasNext":    page < totalPages,
+		"HasPrev":    page > 1,
+		"NextPage":   page + 1,
+		"PrevPage":   page - 1,
+		"BaseURL":    "/dashboard/reviews", // For pagination component
+	}
+
+	switch r.URL.Query().Get("done") {
+	case models.ReviewApproved:
+		data["Success"] = "Post approved and published. The author has been notified."
+	case models.ReviewChangesRequested:
+		data["Success"] = "Post sent back to its author with your notes."
+	}
+	if r.URL.Query().Get("stale") != "" {
+		data["Error"] = "That post is no longer waiting for review."
+	}
+
+	c.renderTemplate(w, r, "dashboard/reviews", data)
+}
+
+// Approve publishes a post waiting for review (requires blogs.review and blogs.publish)
+func (c *ReviewController) Approve(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermBlogsReview)
+	if !ok {
+		return
+	}
+
+	if !policies.CanPublishBlog(user) {
+		http.Error(w, "Access denied. You need the '"+models.PermBlogsPublish+"' permission.", http.StatusForbidden)
+		return
+	}
+
+	c.decide(w, r, user, models.BlogPublished, "")
+}
+
+// Reject sends a post back to its author with the reviewer's notes (requires blogs.review)
+func (c *ReviewController) Reject(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermBlogsReview)
+	if !ok {
+		return
+	}
+
+	notes := strings.TrimSpace(r.FormValue("notes"))
+	if notes == "" {
+		http.Error(w, "Please tell the author what to change", http.StatusUnprocessableEntity)
+		return
+	}
+
+	c.decide(w, r, user, models.BlogChangesRequested, notes)
+}
+
+// decide moves the post in the URL out of review to status and tells its author
+func (c *ReviewController) decide(w http.ResponseWriter, r *http.Request, user *models.User, status, notes string) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid blog ID", http.StatusBadRequest)
+		return
+	}
+
+	blog, err := c.BlogModel.GetByID(r.Context(), id)
+	if err != nil {
+		queryError(w, r, err, "Blog not found", http.StatusNotFound)
+		return
+	}
+
+	if blog.Status != models.BlogPendingReview {
+		http.Redirect(w, r, "/dashboard/reviews?stale=1", http.StatusSeeOther)
+		return
+	}
+
+	if !policies.CanReviewBlog(user, blog) {
+		http.Error(w, "You can't review your own post", http.StatusForbidden)
+		return
+	}
+
+	blog, err = c.BlogModel.TransitionStatus(r.Context(), id, models.BlogPendingReview, status, user.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "status has changed") {
+			http.Redirect(w, r, "/dashboard/reviews?stale=1", http.StatusSeeOther)
+			return
+		}
+		queryError(w, r, err, "Failed to update blog", http.StatusInternalServerError)
+		return
+	}
+
+	c.Notifier.statusChanged(r, blog, models.BlogPendingReview, user, notes)
+
+	http.Redirect(w, r, "/dashboard/reviews?done="+models.ReviewAction(models.BlogPendingReview, status), http.StatusSeeOther)
+}
+
+// reviewNotifier records each review step in a post's history and emails its author
+type reviewNotifier struct {
+	*Controller
+	ReviewModel *models.BlogReviewModel
+	Mailer      services.Mailer
+}
+
+// newReviewNotifier creates a reviewNotifier using the configured mailer
+func newReviewNotifier(app *bootstrap.App) *reviewNotifier {
+	return &reviewNotifier{
+		Controller:  NewController(app),
+		ReviewModel: app.BlogReviews,
+		Mailer:      app.Mailer,
+	}
+}
+
+// statusChanged is called after actor saved blog, which previously had status
+// from. Changes that are part of the review workflow are recorded and emailed
+// to the author. The post is already saved, so failures are only logged.
+func (n *reviewNotifier) statusChanged(r *http.Request, blog *models.Blog, from string, actor *models.User, notes string) {
+	action := models.ReviewAction(from, blog.Status)
+	if action == "" {
+		return
+	}
+
+	if err := n.ReviewModel.Record(blog.ID, actor.ID, action, notes); err != nil {
+		log.Printf("Review error: %v", err)
+	}
+
+	if blog.User == nil || blog.User.Email == "" {
+		return
+	}
+
+	site, err := n.mailURL()
+	if err != nil {
+		log.Printf("Review error: failed to notify author of blog %d: %v", blog.ID, err)
+		return
+	}
+	if err := n.Mailer.Send(reviewMessage(site, blog, action, actor, notes)); err != nil {
+		log.Printf("Review error: failed to notify author of blog %d: %v", blog.ID, err)
+	}
+}
+
+// reviewMessage builds the email telling blog's author about a review step
+func reviewMessage(site string, blog *models.Blog, action string, actor *models.User, notes string) services.Message {
+	editLink := site + "/dashboard/blogs/" + strconv.Itoa(blog.ID) + "/edit"
+	by := ""
+	if actor.ID != blog.UserID {
+		by = " by " + actor.Name
+	}
+
+	var subject, body string
+	switch action {
+	case models.ReviewSubmitted:
+		subject = fmt.Sprintf("%q was submitted for review", blog.Title)
+		body = fmt.Sprintf("Your post %q was submitted for review%s.\n"+
+			"An editor will publish it or send it back with notes; we'll email you either way.\n", blog.Title, by)
+	case models.ReviewWithdrawn:
+		subject = fmt.Sprintf("%q was withdrawn from review", blog.Title)
+		body = fmt.Sprintf("Your post %q was moved back to drafts%s and is no longer waiting for review.\n\n"+
+			"Submit it again when it's ready:\n\n%s\n", blog.Title, by, editLink)
+	case models.ReviewApproved:
+		subject = fmt.Sprintf("%q was approved", blog.Title)
+		body = fmt.Sprintf("Your post %q was approved%s and is now live:\n\n%s\n", blog.Title, by, site+blog.URL())
+		if blog.Status == models.BlogScheduled && blog.PublishAt != nil {
+			body = fmt.Sprintf("Your post %q was approved%s and will be published on %s.\n",
+				blog.Title, by, blog.PublishAt.Format("Jan 2, 2006 at 3:04 PM"))
+		}
+	case models.ReviewChangesRequested:
+		subject = fmt.Sprintf("Changes requested on %q", blog.Title)
+		body = fmt.Sprintf("Your post %q was reviewed%s and needs some changes before it can be published:\n\n%s\n\n"+
+			"Edit the post and submit it for review again:\n\n%s\n", blog.Title, by, notes, editLink)
+	}
+
+	return services.Message{
+		To:      blog.User.Email,
+		Subject: subject,
+		Body:    "Hi " + blog.User.Name + ",\n\n" + body,
+	}
+}
@@ -0,0 +1,205 @@
+// app/controllers/revision_controller.go - Handles blog revision history
+package controllers
+
+import (
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// RevisionController handles listing, comparing and restoring blog revisions
+type RevisionController struct {
+	*Controller
+	BlogModel     models.BlogRepository
+	RevisionModel *models.RevisionModel
+	Notifier      *reviewNotifier
+}
+
+// NewRevisionController creates a new RevisionController
+func NewRevisionController(app *bootstrap.App) *RevisionController {
+	return &RevisionController{
+		Controller:    NewController(app),
+		BlogModel:     app.Blogs,
+		RevisionModel: app.Revisions,
+		Notifier:      newReviewNotifier(app),
+	}
+}
+
+// Index lists every saved version of a blog post, newest first
+func (c *RevisionController) Index(w http.ResponseWriter, r *http.Request) {
+	user, blog, ok := c.editableBlog(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := c.RevisionModel.GetByBlogID(blog.ID)
+	if err != nil {
+		http.Error(w, "Failed to load revisions", http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Title":     "Revision History",
+		"User":      user,
+		"Blog":      blog,
+		"Revisions": revisions,
+		"Restored":  r.URL.Query().Get("restored"),
+	}
+
+	c.renderTemplate(w, r, "dashboard/blogs/revisions", data)
+}
+
+// Diff shows a line-level diff between two revisions of a blog post. "to"
+// defaults to the latest revision and "from" to the revision before "to".
+func (c *RevisionController) Diff(w http.ResponseWriter, r *http.Request) {
+	user, blog, ok := c.editableBlog(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := c.RevisionModel.GetByBlogID(blog.ID)
+	if err != nil {
+		http.Error(w, "Failed to load revisions", http.StatusInternalServerError)
+		return
+	}
+	if len(revisions) == 0 {
+		http.Error(w, "This blog has no revisions yet", http.StatusNotFound)
+		return
+	}
+
+	// Revisions are newest first, so each one's predecessor follows it
+	byID := make(map[int]*models.BlogRevision, len(revisions))
+	previous := make(map[int]*models.BlogRevision, len(revisions))
+	for i, revision := range revisions {
+		byID[revision.ID] = revision
+		previous[revision.ID] = revision
+		if i+1 < len(revisions) {
+			previous[revision.ID] = revisions[i+1]
+		}
+	}
+
+	// pick returns the revision named by a query parameter, or fallback when it is absent
+	pick := func(param string, fallback *models.BlogRevision) (*models.BlogRevision, bool) {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			return fallback, true
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || byID[id] == nil {
+			return nil, false
+		}
+		return byID[id], true
+	}
+
+	to, ok := pick("to", revisions[0])
+	if !ok {
+		http.Error(w, "Revision not found", http.StatusNotFound)
+		return
+	}
+	from, ok := pick("from", previous[to.ID])
+	if !ok {
+		http.Error(w, "Revision not found", http.StatusNotFound)
+		return
+	}
+
+	lines := services.DiffLines(from.Content, to.Content)
+	added, removed := 0, 0
+	for _, line := range lines {
+		switch line.Kind {
+		case services.DiffInsert:
+			added++
+		case services.DiffDelete:
+			removed++
+		}
+	}
+
+	data := map[string]interface{}{
+		"Title":     "Compare Revisions",
+		"User":      user,
+		"Blog":      blog,
+		"Revisions": revisions,
+		"From":      from,
+		"To":        to,
+		"Lines":     lines,
+		"Added":     added,
+		"Removed":   removed,
+	}
+
+	c.renderTemplate(w, r, "dashboard/blogs/diff", data)
+}
+
+// Restore saves an old revision's title, excerpt and content as a new revision.
+// The post's publication status, slug and category are left as they are.
+func (c *RevisionController) Restore(w http.ResponseWriter, r *http.Request) {
+	user, blog, ok := c.editableBlog(w, r)
+	if !ok {
+		return
+	}
+
+	revisionID, err := strconv.Atoi(mux.Vars(r)["revision"])
+	if err != nil {
+		http.Error(w, "Invalid revision ID", http.StatusBadRequest)
+		return
+	}
+
+	revision, err := c.RevisionModel.GetByID(revisionID)
+	if err != nil || revision.BlogID != blog.ID {
+		http.Error(w, "Revision not found", http.StatusNotFound)
+		return
+	}
+
+	restored, err := c.BlogModel.UpdateFrom(r.Context(), blog.ID, models.BlogInput{
+		Title:     revision.Title,
+		Content:   revision.Content,
+		Format:    revision.Format,
+		Excerpt:   revision.Excerpt,
+		Status:    policies.EditedBlogStatus(user, blog.Status, blog.Status),
+		PublishAt: blog.PublishAt,
+		EditorID:  user.ID,
+	})
+	if err != nil {
+		queryError(w, r, err, "Failed to restore revision", http.StatusInternalServerError)
+		return
+	}
+
+	c.Notifier.statusChanged(r, restored, blog.Status, user, "")
+
+	http.Redirect(w, r, "/dashboard/blogs/"+strconv.Itoa(blog.ID)+"/revisions?restored="+strconv.Itoa(revision.Number), http.StatusSeeOther)
+}
+
+// editableBlog loads the blog named in the URL, writing an error response
+// unless the current user may edit it
+func (c *RevisionController) editableBlog(w http.ResponseWriter, r *http.Request) (*models.User, *models.Blog, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid blog ID", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+
+	blog, err := c.BlogModel.GetByID(r.Context(), id)
+	if err != nil {
+		queryError(w, r, err, "Blog not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+
+	// Check if user can edit this blog
+	if !canEditBlog(r, user, blog) {
+		http.Error(w, "You don't have permission to edit this blog", http.StatusForbidden)
+		return nil, nil, false
+	}
+
+	return user, blog, true
+}
@@ -0,0 +1,228 @@
+// app/controllers/role_controller.go - Handles editing roles and the permissions they grant
+package controllers
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/bootstrap"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// RoleController handles the roles and permissions screen
+type RoleController struct {
+	*Controller
+	RoleModel *models.RoleModel
+}
+
+// NewRoleController creates a new RoleController
+func NewRoleController(app *bootstrap.App) *RoleController {
+	return &RoleController{
+		Controller: NewController(app),
+		RoleModel:  app.Roles,
+	}
+}
+
+// Index shows every role against every permission as a grid of checkboxes
+func (c *RoleController) Index(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermRolesManage)
+	if !ok {
+		return
+	}
+
+	extra := map[string]interface{}{}
+	if r.URL.Query().Get("saved") != "" {
+		extra["Success"] = "Role permissions saved. They apply from each user's next request."
+	}
+
+	c.renderRoles(w, r, user, extra)
+}
+
+// Update saves the permission grid. Each role's checkboxes are posted as
+// perm_<role id>; a role with none ticked loses all of its permissions.
+func (c *RoleController) Update(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermRolesManage)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	roles, err := c.RoleModel.GetAll()
+	if err != nil {
+		http.Error(w, "Failed to load roles", http.StatusInternalServerError)
+		return
+	}
+
+	grants := make(map[int][]string, len(roles))
+	for _, role := range roles {
+		permissions := r.Form["perm_"+strconv.Itoa(role.ID)]
+		grants[role.ID] = permissions
+
+		// Refuse to lock the editor out of this screen
+		if role.Name == user.Role && !containsString(permissions, models.PermRolesManage) {
+			c.renderRoles(w, r, user, map[string]interface{}{
+				"Error": "Your own role must keep the " + models.PermRolesManage + " permission",
+			})
+			return
+		}
+	}
+
+	if err := c.RoleModel.SetPermissions(grants); err != nil {
+		c.renderRoles(w, r, user, map[string]interface{}{"Error": "Failed to save role permissions"})
+		return
+	}
+
+	// Audit each role whose permissions changed, as stored
+	if saved, err := c.RoleModel.GetAll(); err == nil {
+		previous := make(map[int]*models.Role, len(roles))
+		for _, role := range roles {
+			previous[role.ID] = role
+		}
+		for _, role := range saved {
+			before, ok := previous[role.ID]
+			if !ok || reflect.DeepEqual(before.Permissions, role.Permissions) {
+				continue
+			}
+			c.recordAudit(r, user, models.AuditRolePermissions, "role", role.ID,
+				auditRolePermissions(before), auditRolePermissions(role))
+		}
+	}
+
+	http.Redirect(w, r, "/dashboard/roles?saved=1", http.StatusSeeOther)
+}
+
+// Store creates a role with no permissions
+func (c *RoleController) Store(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermRolesManage)
+	if !ok {
+		return
+	}
+
+	name := strings.ToLower(strings.TrimSpace(r.FormValue("name")))
+	label := strings.TrimSpace(r.FormValue("label"))
+	if label == "" {
+		label = name
+	}
+
+	if !models.IsValidRoleName(name) {
+		c.renderRoles(w, r, user, map[string]interface{}{
+			"Error": "Role names are 2-50 lowercase letters, digits, dashes or underscores, starting with a letter",
+		})
+		return
+	}
+
+	role, err := c.RoleModel.Create(name, label)
+	if err != nil {
+		message := "Failed to create role"
+		if strings.Contains(err.Error(), "already exists") {
+			message = "A role named \"" + name + "\" already exists"
+		}
+		c.renderRoles(w, r, user, map[string]interface{}{"Error": message})
+		return
+	}
+
+	c.recordAudit(r, user, models.AuditRoleCreated, "role", role.ID, nil, map[string]interface{}{
+		"name":  role.Name,
+		"label": role.Label,
+	})
+
+	c.renderRoles(w, r, user, map[string]interface{}{
+		"Success": "Role \"" + role.Label + "\" created. Tick the permissions it should grant and save.",
+	})
+}
+
+// Destroy deletes a role that no user holds
+func (c *RoleController) Destroy(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermRolesManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid role ID", http.StatusBadRequest)
+		return
+	}
+
+	role, err := c.RoleModel.GetByID(id)
+	if err != nil {
+		http.Error(w, "Role not found", http.StatusNotFound)
+		return
+	}
+
+	if err := c.RoleModel.Delete(id); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, "Role not found", http.StatusNotFound)
+		case strings.Contains(err.Error(), "assigned to users"):
+			c.renderRoles(w, r, user, map[string]interface{}{"Error": "Move this role's users to another role before deleting it"})
+		case strings.Contains(err.Error(), "default role"):
+			c.renderRoles(w, r, user, map[string]interface{}{"Error": "The default role for new accounts cannot be deleted"})
+		default:
+			c.renderRoles(w, r, user, map[string]interface{}{"Error": "Failed to delete role"})
+		}
+		return
+	}
+
+	before := auditRolePermissions(role)
+	before["name"] = role.Name
+	before["label"] = role.Label
+	c.recordAudit(r, user, models.AuditRoleDeleted, "role", id, before, nil)
+
+	http.Redirect(w, r, "/dashboard/roles", http.StatusSeeOther)
+}
+
+// auditRolePermissions is the snapshot of a role's grants stored in audit
+// events, one field per permission so changes read as grants and revocations
+func auditRolePermissions(role *models.Role) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(role.Permissions))
+	for permission, granted := range role.Permissions {
+		if granted {
+			snapshot[permission] = "granted"
+		}
+	}
+	return snapshot
+}
+
+// renderRoles renders the roles screen with extra template data
+func (c *RoleController) renderRoles(w http.ResponseWriter, r *http.Request, user *models.User, extra map[string]interface{}) {
+	roles, err := c.RoleModel.GetAll()
+	if err != nil {
+		roles = []*models.Role{} // Default to empty slice on error
+	}
+
+	permissions, err := c.RoleModel.GetPermissions()
+	if err != nil {
+		permissions = []*models.Permission{}
+	}
+
+	data := map[string]interface{}{
+		"Title":       "Roles & Permissions",
+		"User":        user,
+		"Roles":       roles,
+		"Permissions": permissions,
+		"DefaultRole": models.DefaultRole,
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	c.renderTemplate(w, r, "dashboard/roles", data)
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
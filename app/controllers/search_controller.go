@@ -0,0 +1,223 @@
+// app/controllers/search_controller.go - Handles public post search
+package controllers
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// snippetLength is the approximate size of highlighted result excerpts
+const snippetLength = 240
+
+// SearchController handles searching published posts
+type SearchController struct {
+	*Controller
+	Searcher  models.BlogSearcher
+	UserModel models.UserRepository
+	TagModel  *models.TagModel
+}
+
+// NewSearchController creates a new SearchController backed by MySQL full-text
+// search, or LIKE matching on other databases
+func NewSearchController(app *bootstrap.App) *SearchController {
+	return &SearchController{
+		Controller: NewController(app),
+		Searcher:   app.Searcher,
+		UserModel:  app.Users,
+		TagModel:   app.Tags,
+	}
+}
+
+// SearchResult is a matching post with a highlighted excerpt
+type SearchResult struct {
+	Blog    *models.Blog
+	Snippet template.HTML
+}
+
+// searchFilters holds the search form values as submitted
+type searchFilters struct {
+	Query  string
+	Author string
+	Tag    string
+	From   string
+	To     string
+}
+
+// Index searches published posts. Requests that accept JSON (the header
+// search box) get a compact result list instead of the HTML page.
+func (c *SearchController) Index(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	filters := searchFilters{
+		Query:  strings.TrimSpace(values.Get("q")),
+		Author: strings.TrimSpace(values.Get("author")),
+		Tag:    strings.TrimSpace(values.Get("tag")),
+		From:   strings.TrimSpace(values.Get("from")),
+		To:     strings.TrimSpace(values.Get("to")),
+	}
+	wantsJSON := strings.Contains(r.Header.Get("Accept"), "application/json")
+
+	// Get page parameter from URL (default to 1)
+	page := 1
+	if p, err := strconv.Atoi(values.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	// Calculate offset for pagination
+	limit := 12
+	offset := (page - 1) * limit
+
+	query, errorMsg := c.buildQuery(filters)
+	query.Limit = limit
+	query.Offset = offset
+
+	searched := errorMsg == "" && (filters.Query != "" || filters.Author != "" || filters.Tag != "" || filters.From != "" || filters.To != "")
+
+	var results []*SearchResult
+	total := 0
+	if searched {
+		blogs, count, err := c.Searcher.Search(r.Context(), query)
+		if err != nil {
+			if wantsJSON {
+				respondError(w, http.StatusInternalServerError, "search_failed", "Search is unavailable")
+				return
+			}
+			queryError(w, r, err, "Failed to search blogs", http.StatusInternalServerError)
+			return
+		}
+
+		terms := models.SearchTerms(filters.Query)
+		for _, blog := range blogs {
+			results = append(results, &SearchResult{
+				Blog:    blog,
+				Snippet: services.Highlight(services.PlainText(blog.Format, blog.Content), terms, snippetLength),
+			})
+		}
+		total = count
+	}
+
+	if wantsJSON {
+		c.respondSearchJSON(w, results, page, limit, total, errorMsg)
+		return
+	}
+
+	// Calculate pagination info
+	totalPages := (total + limit - 1) / limit // Ceiling division
+
+	// Check if user is logged in (for navigation)
+	user, _ := c.App.Middleware.GetCurrentUserFromSession(r)
+
+	authors, err := c.UserModel.GetAll(r.Context())
+	if err != nil {
+		authors = []*models.User{} // Default to empty slice on error
+	}
+	tags, err := c.TagModel.GetAll()
+	if err != nil {
+		tags = []*models.Tag{} // Default to empty slice on error
+	}
+
+	// Carry the filters across pages
+	pageQuery := url.Values{}
+	for key, value := range map[string]string{"q": filters.Query, "author": filters.Author, "tag": filters.Tag, "from": filters.From, "to": filters.To} {
+		if value != "" {
+			pageQuery.Set(key, value)
+		}
+	}
+
+	data := map[string]interface{}{
+		"Title":      "Search",
+		"User":       user,
+		"Filters":    filters,
+		"Results":    results,
+		"Total":      total,
+		"Searched":   searched,
+		"Error":      errorMsg,
+		"Authors":    authors,
+		"AllTags":    tags,
+		"Page":       page,
+		"TotalPages": totalPages,
+		"HasNext":    page < totalPages,
+		"HasPrev":    page > 1,
+		"NextPage":   page + 1,
+		"PrevPage":   page - 1,
+		"BaseURL":    "/search",                        // For pagination component
+		"PageQuery":  template.URL(pageQuery.Encode()), // Encoded by url.Values, safe to pass through
+	}
+
+	c.renderTemplate(w, r, "search", data)
+}
+
+// buildQuery validates the submitted filters and converts them into a search query
+func (c *SearchController) buildQuery(filters searchFilters) (models.SearchQuery, string) {
+	query := models.SearchQuery{Terms: filters.Query}
+
+	if filters.Query != "" && len(models.SearchTerms(filters.Query)) == 0 {
+		return query, "Enter at least one word to search for"
+	}
+
+	if filters.Author != "" {
+		id, err := strconv.Atoi(filters.Author)
+		if err != nil || id <= 0 {
+			return query, "Invalid author"
+		}
+		query.AuthorID = id
+	}
+
+	if filters.Tag != "" {
+		tag, err := c.TagModel.GetBySlug(filters.Tag)
+		if err != nil {
+			return query, "Unknown tag"
+		}
+		query.TagID = tag.ID
+	}
+
+	if filters.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", filters.From, time.Local)
+		if err != nil {
+			return query, "Dates must use the YYYY-MM-DD format"
+		}
+		query.From = &from
+	}
+
+	if filters.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", filters.To, time.Local)
+		if err != nil {
+			return query, "Dates must use the YYYY-MM-DD format"
+		}
+		// The end date is inclusive
+		to = to.AddDate(0, 0, 1)
+		query.To = &to
+	}
+
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return query, "The start date must be before the end date"
+	}
+
+	return query, ""
+}
+
+// respondSearchJSON writes search results in the API envelope format
+func (c *SearchController) respondSearchJSON(w http.ResponseWriter, results []*SearchResult, page, limit, total int, errorMsg string) {
+	if errorMsg != "" {
+		respondError(w, http.StatusUnprocessableEntity, "validation_failed", errorMsg)
+		return
+	}
+
+	data := make([]map[string]interface{}, 0, len(results))
+	for _, result := range results {
+		data = append(data, map[string]interface{}{
+			"id":      result.Blog.ID,
+			"title":   result.Blog.Title,
+			"url":     result.Blog.URL(),
+			"snippet": result.Snippet,
+		})
+	}
+
+	respondPaginated(w, data, newPaginationMeta(page, limit, total))
+}
@@ -0,0 +1,166 @@
+// app/controllers/session_controller.go - Handles signed-in devices and remote logout
+package controllers
+
+import (
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// SessionController handles listing and ending login sessions
+type SessionController struct {
+	*Controller
+	UserModel    models.UserRepository
+	SessionModel *models.SessionModel
+}
+
+// NewSessionController creates a new SessionController
+func NewSessionController(app *bootstrap.App) *SessionController {
+	return &SessionController{
+		Controller:   NewController(app),
+		UserModel:    app.Users,
+		SessionModel: app.Sessions,
+	}
+}
+
+// sessionView is a stored session with display details for templates
+type sessionView struct {
+	*models.Session
+	Device  string
+	Current bool
+}
+
+// userSessionViews lists a user's active sessions, marking the one making the request
+func (c *Controller) userSessionViews(r *http.Request, model *models.SessionModel, userID int) []*sessionView {
+	sessions, err := model.GetByUserID(userID)
+	if err != nil {
+		log.Printf("Session list error: %v", err)
+		return nil
+	}
+
+	current := c.App.Middleware.CurrentSessionHash(r)
+	views := make([]*sessionView, 0, len(sessions))
+	for _, session := range sessions {
+		views = append(views, &sessionView{
+			Session: session,
+			Device:  services.DescribeUserAgent(session.UserAgent),
+			Current: current != "" && session.TokenHash == current,
+		})
+	}
+	return views
+}
+
+// Destroy logs the current user out of one of their devices
+func (c *SessionController) Destroy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/dashboard/profile", http.StatusSeeOther)
+		return
+	}
+
+	user, ok := c.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	// Note whether this is the device making the request before it is gone
+	current := false
+	for _, session := range c.userSessionViews(r, c.SessionModel, user.ID) {
+		if session.ID == id {
+			current = session.Current
+		}
+	}
+
+	// Deletion only succeeds for sessions owned by the current user
+	if err := c.SessionModel.DeleteForUser(id, user.ID); err != nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	if current {
+		c.App.Middleware.ClearUserSession(w, r)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/dashboard/profile#sessions", http.StatusSeeOther)
+}
+
+// DestroyAll logs the current user out on every device, including this one
+func (c *SessionController) DestroyAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/dashboard/profile", http.StatusSeeOther)
+		return
+	}
+
+	user, ok := c.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.endSessions(r.Context(), user.ID); err != nil {
+		log.Printf("Logout everywhere error: %v", err)
+		http.Error(w, "Failed to log out other devices", http.StatusInternalServerError)
+		return
+	}
+
+	c.App.Middleware.ClearUserSession(w, r)
+	http.Redirect(w, r, "/login?sessions=ended", http.StatusSeeOther)
+}
+
+// AdminDestroy terminates every session of a user (requires users.manage)
+func (c *SessionController) AdminDestroy(w http.ResponseWriter, r *http.Request) {
+	admin, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := c.UserModel.GetByID(r.Context(), id); err != nil {
+		queryError(w, r, err, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if err := c.endSessions(r.Context(), id); err != nil {
+		log.Printf("Terminate sessions error: %v", err)
+		http.Error(w, "Failed to terminate sessions", http.StatusInternalServerError)
+		return
+	}
+
+	c.recordAudit(r, admin, models.AuditUserSessionsRevoked, "user", id, nil, nil)
+
+	http.Redirect(w, r, "/dashboard/users/"+strconv.Itoa(id)+"/edit?sessions=ended", http.StatusSeeOther)
+}
+
+// sessionUser returns the current user, refusing API tokens: a token must not
+// be able to sign its owner out of their browsers
+func (c *SessionController) sessionUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	if middleware.GetAPIToken(r) != nil {
+		http.Error(w, "Session management requires a browser session", http.StatusForbidden)
+		return nil, false
+	}
+
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return user, true
+}
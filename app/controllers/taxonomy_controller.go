@@ -0,0 +1,403 @@
+// app/controllers/taxonomy_controller.go - Handles category and tag administration
+package controllers
+
+import (
+	"fmt"
+	"go-web-app/app/models"
+	"go-web-app/bootstrap"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// TaxonomyController handles admin management of categories and tags
+type TaxonomyController struct {
+	*Controller
+	CategoryModel *models.CategoryModel
+	TagModel      *models.TagModel
+}
+
+// NewTaxonomyController creates a new TaxonomyController
+func NewTaxonomyController(app *bootstrap.App) *TaxonomyController {
+	return &TaxonomyController{
+		Controller:    NewController(app),
+		CategoryModel: app.Categories,
+		TagModel:      app.Tags,
+	}
+}
+
+// Categories lists all categories as a tree with a form to add one (requires taxonomy.manage)
+func (c *TaxonomyController) Categories(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	c.renderCategories(w, r, user, map[string]interface{}{})
+}
+
+// StoreCategory creates a new category (requires taxonomy.manage)
+func (c *TaxonomyController) StoreCategory(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	// Get form data
+	name := strings.TrimSpace(r.FormValue("name"))
+	slug := strings.TrimSpace(r.FormValue("slug"))
+	description := strings.TrimSpace(r.FormValue("description"))
+
+	parentID, err := parseParentID(r.FormValue("parent_id"))
+	if err != nil {
+		c.renderCategories(w, r, user, map[string]interface{}{"Error": "Invalid parent category"})
+		return
+	}
+
+	// Validate input
+	if name == "" {
+		c.renderCategories(w, r, user, map[string]interface{}{"Error": "Category name is required"})
+		return
+	}
+
+	category, err := c.CategoryModel.Create(name, slug, description, parentID)
+	if err != nil {
+		c.renderCategories(w, r, user, map[string]interface{}{"Error": categoryErrorMessage(err, "Failed to create category")})
+		return
+	}
+
+	c.renderCategories(w, r, user, map[string]interface{}{"Success": "Category \"" + category.Name + "\" created"})
+}
+
+// EditCategory shows the category edit form (requires taxonomy.manage)
+func (c *TaxonomyController) EditCategory(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	category, err := c.CategoryModel.GetByID(id)
+	if err != nil {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+
+	c.renderEditCategory(w, r, user, category, "")
+}
+
+// UpdateCategory updates a category (requires taxonomy.manage)
+func (c *TaxonomyController) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	category, err := c.CategoryModel.GetByID(id)
+	if err != nil {
+		http.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
+
+	// Get form data
+	name := strings.TrimSpace(r.FormValue("name"))
+	slug := strings.TrimSpace(r.FormValue("slug"))
+	description := strings.TrimSpace(r.FormValue("description"))
+
+	// Keep what was submitted so the form is not reset on error
+	category.Name = name
+	category.Description = description
+
+	parentID, err := parseParentID(r.FormValue("parent_id"))
+	if err != nil {
+		c.renderEditCategory(w, r, user, category, "Invalid parent category")
+		return
+	}
+	category.ParentID = parentID
+
+	// Validate input
+	if name == "" {
+		c.renderEditCategory(w, r, user, category, "Category name is required")
+		return
+	}
+
+	if _, err := c.CategoryModel.Update(id, name, slug, description, parentID); err != nil {
+		c.renderEditCategory(w, r, user, category, categoryErrorMessage(err, "Failed to update category"))
+		return
+	}
+
+	http.Redirect(w, r, "/dashboard/categories", http.StatusSeeOther)
+}
+
+// DeleteCategory deletes a category (requires taxonomy.manage)
+func (c *TaxonomyController) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authorize(w, r, models.PermTaxonomyManage); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.CategoryModel.Delete(id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Category not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete category", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/dashboard/categories", http.StatusSeeOther)
+}
+
+// Tags lists all tags with a form to add one (requires taxonomy.manage)
+func (c *TaxonomyController) Tags(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	c.renderTags(w, r, user, map[string]interface{}{})
+}
+
+// StoreTag creates a new tag (requires taxonomy.manage)
+func (c *TaxonomyController) StoreTag(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	names := models.ParseTagNames(r.FormValue("name"))
+	if len(names) != 1 {
+		c.renderTags(w, r, user, map[string]interface{}{"Error": "Enter a single tag name"})
+		return
+	}
+
+	tag, err := c.TagModel.Create(names[0])
+	if err != nil {
+		if strings.Contains(err.Error(), "tag already exists") {
+			c.renderTags(w, r, user, map[string]interface{}{"Error": "A tag with that name already exists"})
+			return
+		}
+		c.renderTags(w, r, user, map[string]interface{}{"Error": "Failed to create tag"})
+		return
+	}
+
+	c.renderTags(w, r, user, map[string]interface{}{"Success": "Tag \"" + tag.Name + "\" created"})
+}
+
+// EditTag shows the tag rename form (requires taxonomy.manage)
+func (c *TaxonomyController) EditTag(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := c.TagModel.GetByID(id)
+	if err != nil {
+		http.Error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+
+	c.renderTemplate(w, r, "dashboard/tags/edit", map[string]interface{}{
+		"Title": "Edit Tag",
+		"User":  user,
+		"Tag":   tag,
+	})
+}
+
+// UpdateTag renames a tag (requires taxonomy.manage)
+func (c *TaxonomyController) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	user, ok := authorize(w, r, models.PermTaxonomyManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := c.TagModel.GetByID(id)
+	if err != nil {
+		http.Error(w, "Tag not found", http.StatusNotFound)
+		return
+	}
+
+	// Helper function to show edit form with error
+	showEditWithError := func(errorMsg string) {
+		c.renderTemplate(w, r, "dashboard/tags/edit", map[string]interface{}{
+			"Title": "Edit Tag",
+			"User":  user,
+			"Tag":   tag,
+			"Error": errorMsg,
+		})
+	}
+
+	names := models.ParseTagNames(r.FormValue("name"))
+	if len(names) != 1 {
+		showEditWithError("Enter a single tag name")
+		return
+	}
+
+	if _, err := c.TagModel.Update(id, names[0]); err != nil {
+		tag.Name = names[0]
+		if strings.Contains(err.Error(), "tag already exists") {
+			showEditWithError("A tag with that name already exists")
+			return
+		}
+		showEditWithError("Failed to update tag")
+		return
+	}
+
+	http.Redirect(w, r, "/dashboard/tags", http.StatusSeeOther)
+}
+
+// DeleteTag deletes a tag and removes it from every post (requires taxonomy.manage)
+func (c *TaxonomyController) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	if _, ok := authorize(w, r, models.PermTaxonomyManage); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.TagModel.Delete(id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, "Tag not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete tag", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/dashboard/tags", http.StatusSeeOther)
+}
+
+// renderCategories renders the category list page with extra template data
+func (c *TaxonomyController) renderCategories(w http.ResponseWriter, r *http.Request, user *models.User, extra map[string]interface{}) {
+	categories, err := c.CategoryModel.GetTree()
+	if err != nil {
+		categories = []*models.Category{} // Default to empty slice on error
+	}
+
+	data := map[string]interface{}{
+		"Title":      "Categories",
+		"User":       user,
+		"Categories": categories,
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	c.renderTemplate(w, r, "dashboard/categories/index", data)
+}
+
+// renderEditCategory renders the category edit form
+func (c *TaxonomyController) renderEditCategory(w http.ResponseWriter, r *http.Request, user *models.User, category *models.Category, errorMsg string) {
+	categories, err := c.CategoryModel.GetTree()
+	if err != nil {
+		categories = []*models.Category{} // Default to empty slice on error
+	}
+
+	// A category cannot be moved below itself or its descendants
+	excluded := make(map[int]bool)
+	for _, id := range models.CategoryDescendantIDs(categories, category.ID) {
+		excluded[id] = true
+	}
+
+	var parents []*models.Category
+	for _, candidate := range categories {
+		if !excluded[candidate.ID] {
+			parents = append(parents, candidate)
+		}
+	}
+
+	selectedParent := 0
+	if category.ParentID != nil {
+		selectedParent = *category.ParentID
+	}
+
+	c.renderTemplate(w, r, "dashboard/categories/edit", map[string]interface{}{
+		"Title":          "Edit Category",
+		"User":           user,
+		"Category":       category,
+		"Parents":        parents,
+		"SelectedParent": selectedParent,
+		"Error":          errorMsg,
+	})
+}
+
+// renderTags renders the tag list page with extra template data
+func (c *TaxonomyController) renderTags(w http.ResponseWriter, r *http.Request, user *models.User, extra map[string]interface{}) {
+	tags, err := c.TagModel.GetAll()
+	if err != nil {
+		tags = []*models.Tag{} // Default to empty slice on error
+	}
+
+	data := map[string]interface{}{
+		"Title": "Tags",
+		"User":  user,
+		"Tags":  tags,
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	c.renderTemplate(w, r, "dashboard/tags/index", data)
+}
+
+// parseParentID parses the optional parent_id form field
+func parseParentID(value string) (*int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return nil, nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 0 {
+		return nil, fmt.Errorf("invalid parent category")
+	}
+
+	return &id, nil
+}
+
+// categoryErrorMessage maps category model errors to messages for the admin forms
+func categoryErrorMessage(err error, fallback string) string {
+	switch {
+	case strings.Contains(err.Error(), "slug already exists"):
+		return "That URL slug is already in use by another category"
+	case strings.Contains(err.Error(), "moved below itself"):
+		return "A category cannot be placed inside itself or one of its subcategories"
+	case strings.Contains(err.Error(), "parent category not found"):
+		return "The selected parent category no longer exists"
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,161 @@
+// app/controllers/token_controller.go - Handles personal API token management
+package controllers
+
+import (
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/bootstrap"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// TokenController handles minting and revoking personal API tokens
+type TokenController struct {
+	*Controller
+	TokenModel *models.APITokenModel
+}
+
+// NewTokenController creates a new TokenController
+func NewTokenController(app *bootstrap.App) *TokenController {
+	return &TokenController{
+		Controller: NewController(app),
+		TokenModel: app.APITokens,
+	}
+}
+
+// Index lists the current user's API tokens
+func (c *TokenController) Index(w http.ResponseWriter, r *http.Request) {
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	c.renderTokens(w, r, user, map[string]interface{}{})
+}
+
+// Store mints a new API token and shows its plaintext value once
+func (c *TokenController) Store(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/dashboard/profile/tokens", http.StatusSeeOther)
+		return
+	}
+
+	// Tokens may not be used to mint or revoke other tokens
+	if middleware.GetAPIToken(r) != nil {
+		http.Error(w, "Token management requires a browser session", http.StatusForbidden)
+		return
+	}
+
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		c.renderTokens(w, r, user, map[string]interface{}{"Error": "Invalid form submission"})
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	scopes := r.Form["scopes"]
+
+	// Validate input
+	if name == "" {
+		c.renderTokens(w, r, user, map[string]interface{}{"Error": "Token name is required"})
+		return
+	}
+
+	if len(scopes) == 0 {
+		c.renderTokens(w, r, user, map[string]interface{}{"Error": "Select at least one scope"})
+		return
+	}
+
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			c.renderTokens(w, r, user, map[string]interface{}{"Error": "Invalid scope selected"})
+			return
+		}
+
+		// Only admins may mint tokens that carry admin privileges
+		if scope == models.ScopeAdmin && !policies.IsAdministrator(user) {
+			c.renderTokens(w, r, user, map[string]interface{}{"Error": "Only administrators can create admin tokens"})
+			return
+		}
+	}
+
+	token, plain, err := c.TokenModel.Create(user.ID, name, scopes)
+	if err != nil {
+		c.renderTokens(w, r, user, map[string]interface{}{"Error": "Failed to create token"})
+		return
+	}
+
+	c.renderTokens(w, r, user, map[string]interface{}{
+		"Success":        "Token \"" + token.Name + "\" created. Copy it now, it will not be shown again.",
+		"PlainTextToken": plain,
+	})
+}
+
+// Revoke revokes one of the current user's API tokens
+func (c *TokenController) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, "/dashboard/profile/tokens", http.StatusSeeOther)
+		return
+	}
+
+	// Tokens may not be used to mint or revoke other tokens
+	if middleware.GetAPIToken(r) != nil {
+		http.Error(w, "Token management requires a browser session", http.StatusForbidden)
+		return
+	}
+
+	// Get token ID from URL
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	// Revoke only succeeds for tokens owned by the current user
+	if err := c.TokenModel.Revoke(id, user.ID); err != nil {
+		http.Error(w, "Token not found", http.StatusNotFound)
+		return
+	}
+
+	http.Redirect(w, r, "/dashboard/profile/tokens", http.StatusSeeOther)
+}
+
+// renderTokens renders the token management page with extra template data
+func (c *TokenController) renderTokens(w http.ResponseWriter, r *http.Request, user *models.User, extra map[string]interface{}) {
+	tokens, err := c.TokenModel.GetByUserID(user.ID)
+	if err != nil {
+		tokens = []*models.APIToken{} // Default to empty slice on error
+	}
+
+	data := map[string]interface{}{
+		"Title":         "API Tokens",
+		"User":          user,
+		"Tokens":        tokens,
+		"CanAdminScope": policies.IsAdministrator(user),
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	c.renderTemplate(w, r, "dashboard/tokens", data)
+}
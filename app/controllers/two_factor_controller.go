@@ -0,0 +1,248 @@
+// app/controllers/two_factor_controller.go - Handles two-factor authentication enrollment
+package controllers
+
+import (
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// twoFactorIssuer is the account label shown in authenticator apps
+const twoFactorIssuer = "Go Blog"
+
+// TwoFactorController handles enabling and disabling TOTP two-factor authentication
+type TwoFactorController struct {
+	*Controller
+	UserModel      models.UserRepository
+	TwoFactorModel *models.TwoFactorModel
+}
+
+// NewTwoFactorController creates a new TwoFactorController
+func NewTwoFactorController(app *bootstrap.App) *TwoFactorController {
+	return &TwoFactorController{
+		Controller:     NewController(app),
+		UserModel:      app.Users,
+		TwoFactorModel: app.TwoFactor,
+	}
+}
+
+// Show displays the two-factor settings, including an enrollment in progress
+func (c *TwoFactorController) Show(w http.ResponseWriter, r *http.Request) {
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	extra := map[string]interface{}{}
+	if r.URL.Query().Get("required") != "" && !user.HasTwoFactor() {
+		extra["Error"] = "Administrators must enable two-factor authentication before using the dashboard."
+	}
+
+	c.renderTwoFactor(w, r, user, extra)
+}
+
+// Enable generates a new secret and shows it for the user to scan
+func (c *TwoFactorController) Enable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, middleware.TwoFactorSetupPath, http.StatusSeeOther)
+		return
+	}
+
+	user, ok := c.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.HasTwoFactor() {
+		http.Redirect(w, r, middleware.TwoFactorSetupPath, http.StatusSeeOther)
+		return
+	}
+
+	secret, err := services.GenerateTOTPSecret()
+	if err != nil {
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Failed to generate secret"})
+		return
+	}
+
+	if err := c.TwoFactorModel.BeginEnrollment(user.ID, secret); err != nil {
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Failed to start setup"})
+		return
+	}
+
+	user.TwoFactorSecret = secret
+	c.renderTwoFactor(w, r, user, map[string]interface{}{})
+}
+
+// Confirm checks a code from the user's app and turns two-factor authentication on
+func (c *TwoFactorController) Confirm(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, middleware.TwoFactorSetupPath, http.StatusSeeOther)
+		return
+	}
+
+	user, ok := c.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if user.HasTwoFactor() || user.TwoFactorSecret == "" {
+		http.Redirect(w, r, middleware.TwoFactorSetupPath, http.StatusSeeOther)
+		return
+	}
+
+	step, valid := services.ValidateTOTP(user.TwoFactorSecret, r.FormValue("code"), time.Now())
+	if !valid {
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Invalid code. Check your device's clock and try again."})
+		return
+	}
+
+	codes, err := c.TwoFactorModel.Confirm(user.ID, step)
+	if err != nil {
+		log.Printf("Two-factor error: %v", err)
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Failed to enable two-factor authentication"})
+		return
+	}
+
+	now := time.Now()
+	user.TwoFactorConfirmedAt = &now
+	c.renderTwoFactor(w, r, user, map[string]interface{}{
+		"Success":       "Two-factor authentication is on. Save these recovery codes somewhere safe, they will not be shown again.",
+		"RecoveryCodes": codes,
+	})
+}
+
+// RegenerateCodes replaces the user's recovery codes after re-checking their password
+func (c *TwoFactorController) RegenerateCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, middleware.TwoFactorSetupPath, http.StatusSeeOther)
+		return
+	}
+
+	user, ok := c.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if !user.HasTwoFactor() {
+		http.Redirect(w, r, middleware.TwoFactorSetupPath, http.StatusSeeOther)
+		return
+	}
+
+	if !user.CheckPassword(r.FormValue("password")) {
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Password is incorrect"})
+		return
+	}
+
+	codes, err := c.TwoFactorModel.RegenerateRecoveryCodes(user.ID)
+	if err != nil {
+		log.Printf("Two-factor error: %v", err)
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Failed to generate recovery codes"})
+		return
+	}
+
+	c.renderTwoFactor(w, r, user, map[string]interface{}{
+		"Success":       "New recovery codes generated. Your old codes no longer work.",
+		"RecoveryCodes": codes,
+	})
+}
+
+// Disable turns two-factor authentication off after re-checking the user's password
+func (c *TwoFactorController) Disable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Redirect(w, r, middleware.TwoFactorSetupPath, http.StatusSeeOther)
+		return
+	}
+
+	user, ok := c.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	if policies.IsAdministrator(user) && c.App.Config.RequireAdmin2FA {
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Administrators are required to keep two-factor authentication enabled"})
+		return
+	}
+
+	if !user.CheckPassword(r.FormValue("password")) {
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Password is incorrect"})
+		return
+	}
+
+	if err := c.TwoFactorModel.Disable(user.ID); err != nil {
+		log.Printf("Two-factor error: %v", err)
+		c.renderTwoFactor(w, r, user, map[string]interface{}{"Error": "Failed to disable two-factor authentication"})
+		return
+	}
+
+	user.TwoFactorSecret = ""
+	user.TwoFactorConfirmedAt = nil
+	c.renderTwoFactor(w, r, user, map[string]interface{}{"Success": "Two-factor authentication is off."})
+}
+
+// sessionUser returns the current user, refusing API tokens: a token must not
+// be able to change how its owner signs in
+func (c *TwoFactorController) sessionUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	if middleware.GetAPIToken(r) != nil {
+		http.Error(w, "Two-factor settings require a browser session", http.StatusForbidden)
+		return nil, false
+	}
+
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// renderTwoFactor renders the two-factor settings page with extra template data
+func (c *TwoFactorController) renderTwoFactor(w http.ResponseWriter, r *http.Request, user *models.User, extra map[string]interface{}) {
+	data := map[string]interface{}{
+		"Title":     "Two-Factor Authentication",
+		"User":      user,
+		"Enabled":   user.HasTwoFactor(),
+		"Required":  policies.IsAdministrator(user) && c.App.Config.RequireAdmin2FA,
+		"CodesLeft": 0,
+	}
+
+	if user.HasTwoFactor() {
+		if count, err := c.TwoFactorModel.CountRecoveryCodes(user.ID); err == nil {
+			data["CodesLeft"] = count
+		}
+	} else if user.TwoFactorSecret != "" {
+		// Enrollment in progress: show the secret until it is confirmed
+		data["Secret"] = user.TwoFactorSecret
+		data["ProvisioningURI"] = services.TOTPProvisioningURI(twoFactorIssuer, user.Email, user.TwoFactorSecret)
+	}
+
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	// Keep the secret and recovery codes out of caches
+	w.Header().Set("Cache-Control", "no-store")
+	c.renderTemplate(w, r, "dashboard/two-factor", data)
+}
+
+// isTOTPCode reports whether code looks like an authenticator code rather than a recovery code
+func isTOTPCode(code string) bool {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != services.TOTPDigits {
+		return false
+	}
+	for _, ch := range code {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
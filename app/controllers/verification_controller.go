@@ -0,0 +1,179 @@
+// app/controllers/verification_controller.go - Handles email address verification
+package controllers
+
+import (
+	"errors"
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/bootstrap"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// verificationLinkTTL is how long an email verification link stays valid
+const verificationLinkTTL = 24 * time.Hour
+
+// VerificationController verifies email addresses through signed links
+type VerificationController struct {
+	*Controller
+	UserModel models.UserRepository
+	Mailer    services.Mailer
+}
+
+// NewVerificationController creates a new VerificationController
+func NewVerificationController(app *bootstrap.App) *VerificationController {
+	return &VerificationController{
+		Controller: NewController(app),
+		UserModel:  app.Users,
+		Mailer:     app.Mailer,
+	}
+}
+
+// signingKey returns the key verification links are signed with. Links are
+// neither issued nor accepted while APP_KEY is unset or a placeholder, as
+// anyone could then sign one for any address.
+func (c *Controller) signingKey() (string, error) {
+	if !c.App.Config.AppKeySet() {
+		return "", errors.New("APP_KEY is not set, refusing to sign verification links")
+	}
+	return c.App.Config.AppKey, nil
+}
+
+// verificationURL returns a signed, expiring link confirming user's current email address.
+// Changing the address invalidates earlier links.
+func (c *Controller) verificationURL(user *models.User, now time.Time) (string, error) {
+	key, err := c.signingKey()
+	if err != nil {
+		return "", err
+	}
+	base, err := c.mailURL()
+	if err != nil {
+		return "", err
+	}
+
+	id := strconv.Itoa(user.ID)
+	expires := strconv.FormatInt(now.Add(verificationLinkTTL).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", services.Sign(key, "verify-email", id, user.Email, expires))
+
+	return base + "/verify-email/" + id + "?" + query.Encode(), nil
+}
+
+// sendVerificationEmail emails user a link to confirm their address
+func (c *Controller) sendVerificationEmail(mailer services.Mailer, user *models.User) error {
+	link, err := c.verificationURL(user, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(services.Message{
+		To:      user.Email,
+		Subject: "Confirm your Go Blog email address",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Thanks for signing up to Go Blog. Confirm your email address to start writing:\n\n" +
+			link + "\n\n" +
+			"The link expires in 24 hours. You can request a new one from your dashboard.\n\n" +
+			"If you didn't create an account, you can ignore this email.\n",
+	})
+}
+
+// Verify confirms the email address named by a signed link
+func (c *VerificationController) Verify(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid verification link", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid verification link", http.StatusBadRequest)
+		return
+	}
+
+	key, err := c.signingKey()
+	if err != nil {
+		log.Printf("Verification error: %v", err)
+		http.Error(w, "Email verification is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	user, err := c.UserModel.GetByID(r.Context(), id)
+	if err != nil || !services.ValidSignature(key, query.Get("signature"), "verify-email", strconv.Itoa(id), user.Email, query.Get("expires")) {
+		http.Error(w, "Invalid verification link", http.StatusForbidden)
+		return
+	}
+
+	// Where to go afterwards depends on whether the visitor is signed in
+	target := "/login"
+	if current, _ := c.App.Middleware.GetCurrentUserFromSession(r); current != nil {
+		target = "/dashboard"
+	}
+
+	if time.Now().Unix() > expires {
+		http.Redirect(w, r, target+"?verification=expired", http.StatusSeeOther)
+		return
+	}
+
+	if err := c.UserModel.MarkVerified(r.Context(), user.ID); err != nil {
+		queryError(w, r, err, "Failed to verify email", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, target+"?verification=verified", http.StatusSeeOther)
+}
+
+// Resend emails the current user a new verification link
+func (c *VerificationController) Resend(w http.ResponseWriter, r *http.Request) {
+	// Get current user
+	user, err := middleware.GetCurrentUser(r)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	if user.IsVerified() {
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	if err := c.sendVerificationEmail(c.Mailer, user); err != nil {
+		log.Printf("Verification email error: %v", err)
+		http.Redirect(w, r, "/dashboard?verification=failed", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/dashboard?verification=sent", http.StatusSeeOther)
+}
+
+// MarkVerified verifies a user's email address without a link (requires users.manage)
+func (c *VerificationController) MarkVerified(w http.ResponseWriter, r *http.Request) {
+	admin, ok := authorize(w, r, models.PermUsersManage)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.UserModel.MarkVerified(r.Context(), id); err != nil {
+		queryError(w, r, err, "User not found", http.StatusNotFound)
+		return
+	}
+
+	c.recordAudit(r, admin, models.AuditUserVerified, "user", id, nil, nil)
+
+	http.Redirect(w, r, "/dashboard/users", http.StatusSeeOther)
+}
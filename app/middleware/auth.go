@@ -2,62 +2,276 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"go-web-app/app/models"
 	"go-web-app/config"
 	"log"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/gorilla/sessions"
 )
 
-// SessionStore holds the session store
-var SessionStore *sessions.CookieStore
+// Middleware holds what the HTTP middleware and session helpers depend on.
+// The application container builds one; tests can build their own.
+type Middleware struct {
+	Config       *config.Config
+	SessionStore sessions.Store
+	Users        models.UserRepository
+	Tokens       *models.APITokenModel
+}
+
+// New creates a Middleware
+func New(cfg *config.Config, store sessions.Store, users models.UserRepository, tokens *models.APITokenModel) *Middleware {
+	return &Middleware{
+		Config:       cfg,
+		SessionStore: store,
+		Users:        users,
+		Tokens:       tokens,
+	}
+}
 
-// InitSessions initializes the session store
-func InitSessions() {
-	SessionStore = sessions.NewCookieStore([]byte(config.AppConfig.SessionSecret))
-	SessionStore.Options = &sessions.Options{
+// NewSessionStore creates the session store selected by SESSION_DRIVER
+func NewSessionStore(cfg *config.Config, sessionModel *models.SessionModel) sessions.Store {
+	options := &sessions.Options{
 		Path:     "/",
 		MaxAge:   86400 * 7, // 7 days
 		HttpOnly: true,
 	}
+
+	switch cfg.SessionDriver {
+	case "cookie":
+		store := sessions.NewCookieStore([]byte(cfg.SessionSecret))
+		store.Options = options
+		return store
+	default:
+		store := NewDatabaseStore(sessionModel, []byte(cfg.SessionSecret))
+		store.Options = options
+		store.TrustProxyHeaders = cfg.TrustProxyHeaders
+		return store
+	}
 }
 
-// AuthMiddleware checks if user is authenticated
-func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+// AuthMiddleware checks if user is authenticated via the session cookie
+// or, alternatively, an "Authorization: Bearer" personal API token
+func (m *Middleware) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		session, err := SessionStore.Get(r, "session")
+		if bearerToken(r) != "" {
+			token, user, err := m.authenticateBearer(r)
+			if WriteInterrupted(w, r, err) {
+				return
+			}
+			if err != nil {
+				http.Error(w, "Invalid API token", http.StatusUnauthorized)
+				return
+			}
+
+			if !token.HasScope(requiredScope(r)) {
+				http.Error(w, "API token lacks the required scope", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withToken(r.Context(), token, user)))
+			return
+		}
+
+		session, err := m.SessionStore.Get(r, "session")
 		if err != nil {
 			log.Printf("Session error: %v", err)
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return
 		}
 
-		userID, ok := session.Values["user_id"]
-		if !ok || userID == nil {
+		user, err := m.sessionUser(r, session)
+		if err != nil {
+			WriteInterrupted(w, r, err)
+			return
+		}
+		if user == nil {
 			http.Redirect(w, r, "/login", http.StatusSeeOther)
 			return
 		}
 
-		// Add user ID to context for use in handlers
-		ctx := context.WithValue(r.Context(), "user_id", userID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		// Admins must enroll in two-factor authentication when the policy is on
+		if m.twoFactorRequired(r, user) {
+			http.Redirect(w, r, TwoFactorSetupPath+"?required=1", http.StatusSeeOther)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user)))
+	}
+}
+
+// APIAuthMiddleware checks if the API client is authenticated (session or bearer token),
+// responding with a JSON 401/403 instead of redirecting to the login page
+func (m *Middleware) APIAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if bearerToken(r) != "" {
+			token, user, err := m.authenticateBearer(r)
+			if WriteInterrupted(w, r, err) {
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusUnauthorized, "invalid_token", "Invalid or revoked API token")
+				return
+			}
+
+			if !token.HasScope(requiredScope(r)) {
+				writeJSONError(w, http.StatusForbidden, "insufficient_scope", "API token lacks the '"+requiredScope(r)+"' scope")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withToken(r.Context(), token, user)))
+			return
+		}
+
+		session, err := m.SessionStore.Get(r, "session")
+		if err != nil {
+			log.Printf("Session error: %v", err)
+		}
+
+		var user *models.User
+		if session != nil {
+			if user, err = m.sessionUser(r, session); err != nil {
+				WriteInterrupted(w, r, err)
+				return
+			}
+		}
+
+		if user == nil {
+			writeJSONError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+			return
+		}
+
+		if m.twoFactorRequired(r, user) {
+			writeJSONError(w, http.StatusForbidden, "two_factor_required", "Enable two-factor authentication to continue")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withUser(r.Context(), user)))
 	}
 }
 
+// APIOptionalAuthMiddleware resolves the session or bearer token user when present
+// but lets anonymous requests through (used for public API endpoints)
+func (m *Middleware) APIOptionalAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if bearerToken(r) != "" {
+			token, user, err := m.authenticateBearer(r)
+			if WriteInterrupted(w, r, err) {
+				return
+			}
+			if err != nil {
+				writeJSONError(w, http.StatusUnauthorized, "invalid_token", "Invalid or revoked API token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withToken(r.Context(), token, user)))
+			return
+		}
+
+		if session, err := m.SessionStore.Get(r, "session"); err == nil {
+			if user, _ := m.sessionUser(r, session); user != nil {
+				r = r.WithContext(withUser(r.Context(), user))
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
+
+// authenticateBearer validates the request's bearer token against the
+// api_tokens table and loads the user it belongs to
+func (m *Middleware) authenticateBearer(r *http.Request) (*models.APIToken, *models.User, error) {
+	token, err := m.Tokens.FindActive(bearerToken(r))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := m.Users.GetByID(r.Context(), token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := m.Tokens.TouchLastUsed(token.ID); err != nil {
+		log.Printf("Token usage error: %v", err)
+	}
+
+	return token, user, nil
+}
+
+// withUser stores the authenticated user in the request context
+func withUser(ctx context.Context, user *models.User) context.Context {
+	ctx = context.WithValue(ctx, "user_id", user.ID)
+	return context.WithValue(ctx, "user", user)
+}
+
+// withToken stores the token owner and the token itself in the request context
+func withToken(ctx context.Context, token *models.APIToken, user *models.User) context.Context {
+	return context.WithValue(withUser(ctx, user), "api_token", token)
+}
+
+// requiredScope maps the request method to the token scope it needs
+func requiredScope(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.ScopeRead
+	default:
+		return models.ScopeWrite
+	}
+}
+
+// GetAPIToken returns the API token used to authenticate the request, if any
+func GetAPIToken(r *http.Request) *models.APIToken {
+	token, _ := r.Context().Value("api_token").(*models.APIToken)
+	return token
+}
+
+// HasScope reports whether the request may act with the given scope.
+// Session-authenticated requests are not scope-restricted.
+func HasScope(r *http.Request, scope string) bool {
+	token := GetAPIToken(r)
+	if token == nil {
+		return true
+	}
+	return token.HasScope(scope)
+}
+
+// writeJSONError writes a JSON error envelope matching the API controllers
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{"code": code, "message": message},
+	})
+}
+
 // GuestMiddleware redirects authenticated users away from guest pages
-func GuestMiddleware(next http.HandlerFunc) http.HandlerFunc {
+func (m *Middleware) GuestMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		session, err := SessionStore.Get(r, "session")
+		session, err := m.SessionStore.Get(r, "session")
 		if err != nil {
 			// If session error, continue as guest
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		userID, ok := session.Values["user_id"]
-		if ok && userID != nil {
+		user, err := m.sessionUser(r, session)
+		if err != nil {
+			WriteInterrupted(w, r, err)
+			return
+		}
+
+		if user != nil {
 			// User is authenticated, redirect to dashboard
 			http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
 			return
@@ -75,12 +289,18 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// CORSMiddleware adds CORS headers
-func CORSMiddleware(next http.Handler) http.Handler {
+// CORSMiddleware adds CORS headers for the origins listed in CORS_ALLOWED_ORIGINS.
+// Other cross-origin requests get no CORS headers, so browsers keep them same-origin.
+func (m *Middleware) CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin != "" && m.isAllowedOrigin(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+CSRFHeaderName)
+		}
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -91,79 +311,111 @@ func CORSMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// GetCurrentUser returns the current authenticated user
-func GetCurrentUser(r *http.Request) (*models.User, error) {
-	userID := r.Context().Value("user_id")
-	if userID == nil {
-		return nil, nil
+// isAllowedOrigin reports whether origin is listed in the CORS configuration
+func (m *Middleware) isAllowedOrigin(origin string) bool {
+	if m.Config == nil {
+		return false
 	}
 
-	// Convert userID to int
-	var id int
-	switch v := userID.(type) {
-	case int:
-		id = v
-	case string:
-		var err error
-		id, err = strconv.Atoi(v)
-		if err != nil {
-			return nil, err
+	for _, allowed := range strings.Split(m.Config.CORSAllowedOrigins, ",") {
+		if allowed = strings.TrimSpace(allowed); allowed != "" && strings.EqualFold(allowed, origin) {
+			return true
 		}
-	default:
-		return nil, nil
 	}
+	return false
+}
 
-	// Get user from database
-	userModel := models.NewUserModel(config.Database)
-	return userModel.GetByID(id)
+// GetCurrentUser returns the current authenticated user, as loaded by
+// AuthMiddleware, APIAuthMiddleware or APIOptionalAuthMiddleware
+func GetCurrentUser(r *http.Request) (*models.User, error) {
+	user, _ := r.Context().Value("user").(*models.User)
+	return user, nil
 }
 
 // GetCurrentUserFromSession returns user from session (for non-protected routes)
-func GetCurrentUserFromSession(r *http.Request) (*models.User, error) {
-	session, err := SessionStore.Get(r, "session")
+func (m *Middleware) GetCurrentUserFromSession(r *http.Request) (*models.User, error) {
+	if user, _ := GetCurrentUser(r); user != nil {
+		return user, nil
+	}
+
+	session, err := m.SessionStore.Get(r, "session")
 	if err != nil {
 		return nil, nil // Don't error on homepage
 	}
 
-	userID, ok := session.Values["user_id"]
-	if !ok || userID == nil {
+	return m.sessionUser(r, session)
+}
+
+// sessionUser loads the user signed in to the session, or returns nil when
+// there is none or the user has since been signed out everywhere. The error
+// is only set when the request was interrupted (see InterruptedStatus).
+func (m *Middleware) sessionUser(r *http.Request, session *sessions.Session) (*models.User, error) {
+	userID, ok := session.Values["user_id"].(int)
+	if !ok {
 		return nil, nil
 	}
 
-	// Convert userID to int
-	var id int
-	switch v := userID.(type) {
-	case int:
-		id = v
-	case string:
-		var err error
-		id, err = strconv.Atoi(v)
-		if err != nil {
-			return nil, nil
+	user, err := m.Users.GetByID(r.Context(), userID)
+	if err != nil {
+		if InterruptedStatus(r, err) != 0 {
+			return nil, err
 		}
-	default:
+		log.Printf("Session error: %v", err)
 		return nil, nil
 	}
 
-	// Get user from database
-	userModel := models.NewUserModel(config.Database)
-	return userModel.GetByID(id)
+	if version, _ := session.Values["session_version"].(int); version != user.SessionVersion {
+		return nil, nil
+	}
+
+	// Signing the admin out everywhere also ends their impersonation
+	if !m.impersonatorSignedIn(r.Context(), session) {
+		return nil, nil
+	}
+
+	return user, nil
 }
 
 // SetUserSession sets user session data
-func SetUserSession(w http.ResponseWriter, r *http.Request, userID int) error {
-	session, err := SessionStore.Get(r, "session")
+func (m *Middleware) SetUserSession(w http.ResponseWriter, r *http.Request, user *models.User) error {
+	session, err := m.SessionStore.Get(r, "session")
 	if err != nil {
 		return err
 	}
 
-	session.Values["user_id"] = userID
+	// Start a new server-side session so a pre-login session ID can't be reused
+	if store, ok := m.SessionStore.(*DatabaseStore); ok {
+		if err := store.Renew(session); err != nil {
+			return err
+		}
+	}
+
+	session.Values["user_id"] = user.ID
+	session.Values["session_version"] = user.SessionVersion
+	clearPendingTwoFactor(session.Values)
+	clearImpersonation(session.Values)
+	// Issue a fresh CSRF token for the authenticated session
+	delete(session.Values, csrfSessionKey)
 	return session.Save(r, w)
 }
 
+// CurrentSessionHash returns the stored hash of the request's server-side
+// session ID, or "" when there is none (e.g. with the cookie session driver)
+func (m *Middleware) CurrentSessionHash(r *http.Request) string {
+	if _, ok := m.SessionStore.(*DatabaseStore); !ok {
+		return ""
+	}
+
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil || session.ID == "" {
+		return ""
+	}
+	return models.HashAPIToken(session.ID)
+}
+
 // ClearUserSession clears user session data
-func ClearUserSession(w http.ResponseWriter, r *http.Request) error {
-	session, err := SessionStore.Get(r, "session")
+func (m *Middleware) ClearUserSession(w http.ResponseWriter, r *http.Request) error {
+	session, err := m.SessionStore.Get(r, "session")
 	if err != nil {
 		return err
 	}
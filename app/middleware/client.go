@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIP returns the IP address of the client making the request. The
+// X-Forwarded-For header is only believed when TRUST_PROXY_HEADERS is on,
+// since anyone can send it; the entry added by our own proxy is the last one.
+func (m *Middleware) ClientIP(r *http.Request) string {
+	return clientIP(r, m.Config != nil && m.Config.TrustProxyHeaders)
+}
+
+// clientIP returns the request's client IP, taking it from X-Forwarded-For
+// when trustProxy is set
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			hops := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); net.ParseIP(ip) != nil {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"go-web-app/app/models"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/sessions"
+)
+
+const (
+	impersonatorKey        = "impersonator_id"
+	impersonatorVersionKey = "impersonator_version"
+)
+
+// StartImpersonation signs the session in as target while remembering admin,
+// so StopImpersonation can switch back without a password
+func (m *Middleware) StartImpersonation(w http.ResponseWriter, r *http.Request, admin, target *models.User) error {
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil {
+		return err
+	}
+
+	if _, ok := session.Values[impersonatorKey].(int); ok {
+		return fmt.Errorf("already impersonating a user")
+	}
+
+	session.Values[impersonatorKey] = admin.ID
+	session.Values[impersonatorVersionKey] = admin.SessionVersion
+	session.Values["user_id"] = target.ID
+	session.Values["session_version"] = target.SessionVersion
+	clearPendingTwoFactor(session.Values)
+	return session.Save(r, w)
+}
+
+// StopImpersonation signs the session back in as the admin who started
+// impersonating and returns their ID
+func (m *Middleware) StopImpersonation(w http.ResponseWriter, r *http.Request) (int, error) {
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil {
+		return 0, err
+	}
+
+	adminID, ok := session.Values[impersonatorKey].(int)
+	if !ok {
+		return 0, fmt.Errorf("not impersonating a user")
+	}
+
+	session.Values["user_id"] = adminID
+	session.Values["session_version"] = session.Values[impersonatorVersionKey]
+	clearImpersonation(session.Values)
+	return adminID, session.Save(r, w)
+}
+
+// GetImpersonator returns the admin impersonating the current user, or nil
+// when the session belongs to the user themselves or the admin has since
+// been signed out everywhere
+func (m *Middleware) GetImpersonator(r *http.Request) *models.User {
+	if m.SessionStore == nil {
+		return nil
+	}
+
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil {
+		return nil
+	}
+
+	adminID, ok := session.Values[impersonatorKey].(int)
+	if !ok || !m.impersonatorSignedIn(r.Context(), session) {
+		return nil
+	}
+
+	admin, err := m.Users.GetByID(r.Context(), adminID)
+	if err != nil {
+		log.Printf("Impersonation error: %v", err)
+		return nil
+	}
+	return admin
+}
+
+// NotWhileImpersonating refuses requests made while impersonating a user.
+// It guards actions only the account holder should take, such as changing
+// their password or two-factor settings.
+func (m *Middleware) NotWhileImpersonating(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if session, err := m.SessionStore.Get(r, "session"); err == nil {
+			if _, ok := session.Values[impersonatorKey].(int); ok {
+				http.Error(w, "This action is not available while impersonating a user", http.StatusForbidden)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// impersonatorSignedIn reports whether the admin behind an impersonated
+// session is still signed in. Sessions that are not impersonating pass.
+func (m *Middleware) impersonatorSignedIn(ctx context.Context, session *sessions.Session) bool {
+	adminID, ok := session.Values[impersonatorKey].(int)
+	if !ok {
+		return true
+	}
+
+	current, err := m.Users.GetSessionVersion(ctx, adminID)
+	if err != nil {
+		log.Printf("Session error: %v", err)
+		return false
+	}
+
+	version, _ := session.Values[impersonatorVersionKey].(int)
+	return version == current
+}
+
+// clearImpersonation removes the impersonating admin from session values
+func clearImpersonation(values map[interface{}]interface{}) {
+	delete(values, impersonatorKey)
+	delete(values, impersonatorVersionKey)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// StatusClientClosedRequest is the non-standard status (from nginx) for a
+// request the client abandoned before the response was ready
+const StatusClientClosedRequest = 499
+
+// InterruptedStatus returns the status for a database error caused by the
+// request being cut short: StatusClientClosedRequest when the client went
+// away, 503 Service Unavailable when a query ran out of time, or 0 for any
+// other error
+func InterruptedStatus(r *http.Request, err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, context.Canceled) || errors.Is(r.Context().Err(), context.Canceled):
+		return StatusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	default:
+		return 0
+	}
+}
+
+// WriteInterrupted answers a request whose database call was interrupted,
+// reporting false (and writing nothing) for any other error
+func WriteInterrupted(w http.ResponseWriter, r *http.Request, err error) bool {
+	status := InterruptedStatus(r, err)
+	if status == 0 {
+		return false
+	}
+
+	if status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "5")
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		writeJSONError(w, status, "request_interrupted", "The request was interrupted before it completed")
+	} else {
+		http.Error(w, "The request was interrupted before it completed", status)
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// RequirePermission only lets the request through when the current user's role
+// grants permission; with an API token, admin permissions also need the admin
+// scope (see policies.IsAdminPermission). It must run inside AuthMiddleware or APIAuthMiddleware,
+// which establish who the user is.
+func RequirePermission(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		isAPI := strings.HasPrefix(r.URL.Path, "/api/")
+
+		user, err := GetCurrentUser(r)
+		if err != nil || user == nil {
+			if err != nil {
+				log.Printf("Permission check error: %v", err)
+			}
+			if isAPI {
+				writeJSONError(w, http.StatusUnauthorized, "unauthenticated", "Authentication required")
+			} else {
+				http.Redirect(w, r, "/login", http.StatusSeeOther)
+			}
+			return
+		}
+
+		if !policies.Can(user, permission) {
+			if isAPI {
+				writeJSONError(w, http.StatusForbidden, "forbidden", "Missing the '"+permission+"' permission")
+			} else {
+				http.Error(w, "Access denied. You need the '"+permission+"' permission.", http.StatusForbidden)
+			}
+			return
+		}
+
+		if !HasPermissionScope(r, permission) {
+			if isAPI {
+				writeJSONError(w, http.StatusForbidden, "insufficient_scope", "API token lacks the '"+models.ScopeAdmin+"' scope")
+			} else {
+				http.Error(w, "API token lacks the '"+models.ScopeAdmin+"' scope", http.StatusForbidden)
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// HasPermissionScope reports whether the request's API token, if any, may use
+// permission: admin permissions need a token with the admin scope
+func HasPermissionScope(r *http.Request, permission string) bool {
+	return !policies.IsAdminPermission(permission) || HasScope(r, models.ScopeAdmin)
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"fmt"
+	"go-web-app/app/models"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+const (
+	// sessionTouchInterval limits how often a session's last-seen time is written
+	sessionTouchInterval = time.Minute
+	// sessionPruneInterval limits how often expired sessions are deleted
+	sessionPruneInterval = time.Hour
+)
+
+// DatabaseStore is a sessions.Store that keeps session values in the sessions
+// table. The cookie only carries a signed random session ID, so sessions can be
+// listed per user and revoked server-side. A guest's session, which holds no
+// more than a CSRF token, stays in its signed cookie instead, so visitors and
+// crawlers don't add rows to the table.
+type DatabaseStore struct {
+	Model   *models.SessionModel
+	Codecs  []securecookie.Codec
+	Options *sessions.Options
+	// TrustProxyHeaders records the client IP from X-Forwarded-For, as for Middleware.ClientIP
+	TrustProxyHeaders bool
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// NewDatabaseStore creates a DatabaseStore whose cookies are signed (and
+// optionally encrypted) with keyPairs, as for sessions.NewCookieStore
+func NewDatabaseStore(model *models.SessionModel, keyPairs ...[]byte) *DatabaseStore {
+	store := &DatabaseStore{
+		Model:  model,
+		Codecs: securecookie.CodecsFromPairs(keyPairs...),
+		Options: &sessions.Options{
+			Path:   "/",
+			MaxAge: 86400 * 30,
+		},
+	}
+	store.MaxAge(store.Options.MaxAge)
+	return store
+}
+
+// Get returns the named session for the request, caching it for the rest of the request
+func (s *DatabaseStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session named by the request's cookie. A missing, expired or
+// revoked session yields a fresh empty one.
+func (s *DatabaseStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+
+	var id string
+	if err := securecookie.DecodeMulti(name, cookie.Value, &id, s.Codecs...); err != nil {
+		// A guest's cookie carries the session values rather than an ID
+		values := make(map[interface{}]interface{})
+		if guestErr := securecookie.DecodeMulti(name, cookie.Value, &values, s.Codecs...); guestErr != nil {
+			return session, err
+		}
+		session.Values = values
+		session.IsNew = false
+		return session, nil
+	}
+
+	record, data, err := s.Model.Load(models.HashAPIToken(id))
+	if err != nil {
+		return session, nil
+	}
+
+	if err := (securecookie.GobEncoder{}).Deserialize(data, &session.Values); err != nil {
+		return session, fmt.Errorf("failed to decode session: %v", err)
+	}
+
+	session.ID = id
+	session.IsNew = false
+
+	// Keep the device list current without writing on every request
+	ip, userAgent := clientIP(r, s.TrustProxyHeaders), r.UserAgent()
+	if time.Since(record.LastSeenAt) > sessionTouchInterval || ip != record.IPAddress || userAgent != record.UserAgent {
+		if err := s.Model.Touch(record.ID, ip, userAgent); err != nil {
+			log.Printf("Session error: %v", err)
+		}
+	}
+
+	return session, nil
+}
+
+// Save writes the session to the database and sets its cookie; a guest's
+// session only goes in the cookie. A session with MaxAge <= 0 is deleted and
+// its cookie cleared.
+func (s *DatabaseStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge <= 0 {
+		if session.ID != "" {
+			if err := s.Model.Delete(models.HashAPIToken(session.ID)); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if !storedInDatabase(session.Values) {
+		return s.saveGuest(w, session)
+	}
+
+	if session.ID == "" {
+		key := securecookie.GenerateRandomKey(32)
+		if key == nil {
+			return fmt.Errorf("failed to generate session ID")
+		}
+		session.ID = base64.RawURLEncoding.EncodeToString(key)
+		s.pruneExpired()
+	}
+
+	data, err := (securecookie.GobEncoder{}).Serialize(session.Values)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %v", err)
+	}
+
+	// An impersonated session stays on the admin's device list, not the user's
+	userID, _ := session.Values["user_id"].(int)
+	if adminID, ok := session.Values[impersonatorKey].(int); ok {
+		userID = adminID
+	}
+	lifetime := time.Duration(session.Options.MaxAge) * time.Second
+	if err := s.Model.Save(models.HashAPIToken(session.ID), userID, data, clientIP(r, s.TrustProxyHeaders), r.UserAgent(), lifetime); err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.Codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// saveGuest writes a guest's session values into its cookie, dropping the
+// stored session it had, e.g. when a two-factor challenge is abandoned
+func (s *DatabaseStore) saveGuest(w http.ResponseWriter, session *sessions.Session) error {
+	if session.ID != "" {
+		if err := s.Model.Delete(models.HashAPIToken(session.ID)); err != nil {
+			return err
+		}
+		session.ID = ""
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.Values, s.Codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// storedInDatabase reports whether session values belong in the sessions
+// table: once a user has signed in, or while they wait on a second factor,
+// whose attempt count must not be reset by replaying an older cookie
+func storedInDatabase(values map[interface{}]interface{}) bool {
+	_, signedIn := values["user_id"].(int)
+	_, pending := values[pendingUserKey].(int)
+	return signedIn || pending
+}
+
+// Renew deletes the stored session and clears its ID so the next Save issues a
+// new one. Called at login so an ID planted before it cannot be used after it.
+func (s *DatabaseStore) Renew(session *sessions.Session) error {
+	if session.ID != "" {
+		if err := s.Model.Delete(models.HashAPIToken(session.ID)); err != nil {
+			return err
+		}
+	}
+	session.ID = ""
+	return nil
+}
+
+// MaxAge sets the maximum age for the store and its cookie codecs
+func (s *DatabaseStore) MaxAge(age int) {
+	s.Options.MaxAge = age
+
+	for _, codec := range s.Codecs {
+		if sc, ok := codec.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(age)
+		}
+	}
+}
+
+// pruneExpired deletes expired sessions at most once per sessionPruneInterval
+func (s *DatabaseStore) pruneExpired() {
+	s.mu.Lock()
+	if time.Since(s.lastPruned) < sessionPruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPruned = time.Now()
+	s.mu.Unlock()
+
+	if _, err := s.Model.DeleteExpired(); err != nil {
+		log.Printf("Session error: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// TwoFactorChallengeTTL is how long a password-verified login may wait for its second factor
+	TwoFactorChallengeTTL = 5 * time.Minute
+	// TwoFactorMaxAttempts is how many wrong codes end the challenge and send the user back to /login
+	TwoFactorMaxAttempts = 5
+
+	// TwoFactorSetupPath is where users enroll in two-factor authentication
+	TwoFactorSetupPath = "/dashboard/profile/two-factor"
+
+	pendingUserKey     = "two_factor_user_id"
+	pendingExpiresKey  = "two_factor_expires"
+	pendingAttemptsKey = "two_factor_attempts"
+)
+
+// SetPendingTwoFactor remembers a user who has passed the password check but
+// still has to enter a second factor. The user is not signed in yet.
+func (m *Middleware) SetPendingTwoFactor(w http.ResponseWriter, r *http.Request, user *models.User) error {
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil {
+		return err
+	}
+
+	session.Values[pendingUserKey] = user.ID
+	session.Values[pendingExpiresKey] = time.Now().Add(TwoFactorChallengeTTL).Unix()
+	session.Values[pendingAttemptsKey] = 0
+	return session.Save(r, w)
+}
+
+// GetPendingTwoFactor returns the ID of the user waiting on a second factor,
+// or false when there is no challenge or it has expired
+func (m *Middleware) GetPendingTwoFactor(r *http.Request) (int, bool) {
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil {
+		return 0, false
+	}
+
+	userID, ok := session.Values[pendingUserKey].(int)
+	if !ok {
+		return 0, false
+	}
+
+	expires, _ := session.Values[pendingExpiresKey].(int64)
+	if time.Now().Unix() > expires {
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// FailPendingTwoFactor counts a wrong code and reports how many attempts are
+// left. The challenge is dropped once none remain.
+func (m *Middleware) FailPendingTwoFactor(w http.ResponseWriter, r *http.Request) (int, error) {
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil {
+		return 0, err
+	}
+
+	attempts, _ := session.Values[pendingAttemptsKey].(int)
+	attempts++
+	session.Values[pendingAttemptsKey] = attempts
+
+	remaining := TwoFactorMaxAttempts - attempts
+	if remaining <= 0 {
+		clearPendingTwoFactor(session.Values)
+		remaining = 0
+	}
+
+	return remaining, session.Save(r, w)
+}
+
+// ClearPendingTwoFactor abandons the current two-factor challenge
+func (m *Middleware) ClearPendingTwoFactor(w http.ResponseWriter, r *http.Request) error {
+	session, err := m.SessionStore.Get(r, "session")
+	if err != nil {
+		return err
+	}
+
+	clearPendingTwoFactor(session.Values)
+	return session.Save(r, w)
+}
+
+// clearPendingTwoFactor removes the challenge keys from session values
+func clearPendingTwoFactor(values map[interface{}]interface{}) {
+	delete(values, pendingUserKey)
+	delete(values, pendingExpiresKey)
+	delete(values, pendingAttemptsKey)
+}
+
+// twoFactorRequired reports whether the admin two-factor policy blocks the
+// session user until they enroll. The enrollment pages are always allowed.
+func (m *Middleware) twoFactorRequired(r *http.Request, user *models.User) bool {
+	if m.Config == nil || !m.Config.RequireAdmin2FA {
+		return false
+	}
+
+	if strings.HasPrefix(r.URL.Path, TwoFactorSetupPath) {
+		return false
+	}
+
+	return policies.IsAdministrator(user) && !user.HasTwoFactor()
+}
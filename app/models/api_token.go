@@ -0,0 +1,241 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"go-web-app/database/dialect"
+	"strings"
+	"time"
+)
+
+// Token scopes that can be granted to a personal API token
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// APITokenPrefix is prepended to every generated token so they are easy to recognise
+const APITokenPrefix = "gwa_"
+
+// APIToken represents a personal API token (only the hash is stored)
+type APIToken struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APITokenModel handles API token database operations
+type APITokenModel struct {
+	DB *sql.DB
+}
+
+// NewAPITokenModel creates a new APITokenModel instance
+func NewAPITokenModel(db *sql.DB) *APITokenModel {
+	return &APITokenModel{DB: db}
+}
+
+// IsRevoked reports whether the token has been revoked
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// HasScope checks if the token was granted the given scope.
+// The admin scope implies write, and write implies read.
+func (t *APIToken) HasScope(scope string) bool {
+	return ScopesAllow(t.Scopes, scope)
+}
+
+// ScopesAllow checks whether a list of granted scopes satisfies the required scope
+func ScopesAllow(granted []string, required string) bool {
+	for _, s := range granted {
+		switch {
+		case s == required:
+			return true
+		case s == ScopeAdmin:
+			return true
+		case s == ScopeWrite && required == ScopeRead:
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope reports whether scope is a known token scope
+func IsValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeWrite || scope == ScopeAdmin
+}
+
+// HashAPIToken returns the hex-encoded SHA-256 hash of a plaintext token
+func HashAPIToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateAPIToken creates a new random plaintext token
+func generateAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return APITokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// Create mints a new token for a user and returns it along with the plaintext value.
+// The plaintext is never stored and cannot be recovered later.
+func (m *APITokenModel) Create(userID int, name string, scopes []string) (*APIToken, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !IsValidScope(scope) {
+			return nil, "", fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+
+	plain, err := generateAPIToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %v", err)
+	}
+
+	query := `INSERT INTO api_tokens (user_id, name, token_hash, scopes, created_at)
+			  VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	id, err := dialect.Of(m.DB).InsertID(context.Background(), m.DB, query, userID, name, HashAPIToken(plain), strings.Join(scopes, ","))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create token: %v", err)
+	}
+
+	token, err := m.GetByID(int(id))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return token, plain, nil
+}
+
+// GetByID retrieves a token by ID
+func (m *APITokenModel) GetByID(id int) (*APIToken, error) {
+	query := `SELECT id, user_id, name, token_hash, scopes, last_used_at, revoked_at, created_at
+			  FROM api_tokens WHERE id = ?`
+
+	token, err := scanAPIToken(m.DB.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("token not found")
+		}
+		return nil, fmt.Errorf("failed to get token: %v", err)
+	}
+
+	return token, nil
+}
+
+// GetByUserID retrieves all tokens belonging to a user, newest first
+func (m *APITokenModel) GetByUserID(userID int) ([]*APIToken, error) {
+	query := `SELECT id, user_id, name, token_hash, scopes, last_used_at, revoked_at, created_at
+			  FROM api_tokens WHERE user_id = ? ORDER BY created_at DESC`
+
+	rows, err := m.DB.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %v", err)
+	}
+	defer rows.Close()
+
+	var tokens []*APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan token: %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// FindActive looks up a non-revoked token by its plaintext value
+func (m *APITokenModel) FindActive(plain string) (*APIToken, error) {
+	query := `SELECT id, user_id, name, token_hash, scopes, last_used_at, revoked_at, created_at
+			  FROM api_tokens WHERE token_hash = ? AND revoked_at IS NULL`
+
+	token, err := scanAPIToken(m.DB.QueryRow(query, HashAPIToken(plain)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid token")
+		}
+		return nil, fmt.Errorf("failed to look up token: %v", err)
+	}
+
+	return token, nil
+}
+
+// TouchLastUsed records that a token has just been used
+func (m *APITokenModel) TouchLastUsed(id int) error {
+	_, err := m.DB.Exec(`UPDATE api_tokens SET last_used_at = CURRENT_TIMESTAMP WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to update token usage: %v", err)
+	}
+	return nil
+}
+
+// Revoke revokes a token owned by the given user
+func (m *APITokenModel) Revoke(id, userID int) error {
+	query := `UPDATE api_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND revoked_at IS NULL`
+
+	result, err := m.DB.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("token not found")
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAPIToken scans a single api_tokens row
+func scanAPIToken(row rowScanner) (*APIToken, error) {
+	token := &APIToken{}
+	var scopes string
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.Name, &token.TokenHash, &scopes,
+		&lastUsedAt, &revokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return token, nil
+}
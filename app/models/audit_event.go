@@ -0,0 +1,324 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditUserCreated          = "user.created"
+	AuditUserUpdated          = "user.updated"
+	AuditUserRoleChanged      = "user.role_changed"
+	AuditUserDeleted          = "user.deleted"
+	AuditUserVerified         = "user.verified"
+	AuditUserUnlocked         = "user.unlocked"
+	AuditUserSessionsRevoked  = "user.sessions_revoked"
+	AuditRoleCreated          = "role.created"
+	AuditRoleDeleted          = "role.deleted"
+	AuditRolePermissions      = "role.permissions_changed"
+	AuditBlogDeleted          = "blog.deleted"
+	AuditImpersonationStarted = "impersonation.started"
+	AuditImpersonationStopped = "impersonation.stopped"
+)
+
+// AuditActions lists every audited action, for the audit log filter
+var AuditActions = []string{
+	AuditUserCreated, AuditUserUpdated, AuditUserRoleChanged, AuditUserDeleted,
+	AuditUserVerified, AuditUserUnlocked, AuditUserSessionsRevoked,
+	AuditRoleCreated, AuditRoleDeleted, AuditRolePermissions,
+	AuditBlogDeleted, AuditImpersonationStarted, AuditImpersonationStopped,
+}
+
+// AuditTargetTypes lists the kinds of record audit events point at
+var AuditTargetTypes = []string{"user", "role", "blog"}
+
+// AuditEvent records a sensitive action: who did it, to what, and how the
+// record looked before and after (as JSON, empty when not applicable)
+type AuditEvent struct {
+	ID         int64     `json:"id"`
+	ActorID    *int      `json:"actor_id"`
+	ActorName  string    `json:"actor_name"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   *int      `json:"target_id"`
+	Before     string    `json:"before,omitempty"`
+	After      string    `json:"after,omitempty"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuditChange is one field that differs between an event's before and after
+type AuditChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// Changes compares the before and after JSON objects field by field, listing
+// added, removed and changed fields in name order. It returns nil when either
+// side is not a JSON object.
+func (e *AuditEvent) Changes() []AuditChange {
+	before, ok := auditObject(e.Before)
+	if !ok {
+		return nil
+	}
+	after, ok := auditObject(e.After)
+	if !ok {
+		return nil
+	}
+
+	fields := map[string]bool{}
+	for field := range before {
+		fields[field] = true
+	}
+	for field := range after {
+		fields[field] = true
+	}
+
+	var changes []AuditChange
+	for field := range fields {
+		if reflect.DeepEqual(before[field], after[field]) {
+			continue
+		}
+		changes = append(changes, AuditChange{
+			Field:  field,
+			Before: auditValue(before[field]),
+			After:  auditValue(after[field]),
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// auditObject decodes before/after data as a JSON object; empty data is an
+// empty object so additions and removals still show as changes
+func auditObject(data string) (map[string]interface{}, bool) {
+	object := map[string]interface{}{}
+	if data == "" {
+		return object, true
+	}
+	if err := json.Unmarshal([]byte(data), &object); err != nil {
+		return nil, false
+	}
+	return object, true
+}
+
+// auditValue formats a decoded JSON value for display
+func auditValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// AuditQuery filters the audit log. Every filter is optional; zero values
+// mean "no restriction".
+type AuditQuery struct {
+	Action     string
+	ActorID    int
+	TargetType string
+	TargetID   int
+	From       *time.Time // Only events on or after this time
+	To         *time.Time // Only events before this time
+	Limit      int        // 0 returns every matching event
+	Offset     int
+}
+
+// AuditActor is someone who appears in the audit log
+type AuditActor struct {
+	ID   int
+	Name string
+}
+
+// AuditModel handles audit event database operations
+type AuditModel struct {
+	DB *sql.DB
+}
+
+// NewAuditModel creates a new AuditModel instance
+func NewAuditModel(db *sql.DB) *AuditModel {
+	return &AuditModel{DB: db}
+}
+
+// Record stores an audit event; its ID and CreatedAt are ignored. Inside a
+// Transaction the event is only kept if the unit of work commits.
+func (m *AuditModel) Record(ctx context.Context, event *AuditEvent) error {
+	query := `INSERT INTO audit_events (actor_id, actor_name, action, target_type, target_id, before_data, after_data, ip_address, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	_, err := conn(ctx, m.DB).ExecContext(ctx, query, nullableID(event.ActorID), event.ActorName, event.Action, event.TargetType,
+		nullableID(event.TargetID), nullableString(event.Before), nullableString(event.After), event.IPAddress)
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+
+	return nil
+}
+
+// auditSelect lists the columns scanned by scanAuditEvent
+const auditSelect = `SELECT id, actor_id, actor_name, action, target_type, target_id,
+	COALESCE(before_data, ''), COALESCE(after_data, ''), ip_address, created_at
+	FROM audit_events`
+
+// Search returns the events matching q, newest first
+func (m *AuditModel) Search(q AuditQuery) ([]*AuditEvent, error) {
+	var events []*AuditEvent
+	err := m.Each(q, func(event *AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Each calls fn with every event matching q, newest first, without holding
+// them all in memory. It stops at the first error fn returns.
+func (m *AuditModel) Each(q AuditQuery, fn func(*AuditEvent) error) error {
+	where, args := auditFilter(q)
+	query := auditSelect + where + ` ORDER BY created_at DESC, id DESC`
+	if q.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, q.Limit, q.Offset)
+	}
+
+	rows, err := m.DB.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get audit events: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read audit events: %v", err)
+	}
+	return nil
+}
+
+// Count returns the number of events matching the same filters as Search
+func (m *AuditModel) Count(q AuditQuery) (int, error) {
+	where, args := auditFilter(q)
+
+	var count int
+	err := m.DB.QueryRow(`SELECT COUNT(*) FROM audit_events`+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count audit events: %v", err)
+	}
+
+	return count, nil
+}
+
+// GetActors returns everyone who has audit events and still has an account
+func (m *AuditModel) GetActors() ([]*AuditActor, error) {
+	query := `SELECT DISTINCT u.id, u.name FROM audit_events a
+			  JOIN users u ON u.id = a.actor_id ORDER BY u.name`
+
+	rows, err := m.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit actors: %v", err)
+	}
+	defer rows.Close()
+
+	var actors []*AuditActor
+	for rows.Next() {
+		actor := &AuditActor{}
+		if err := rows.Scan(&actor.ID, &actor.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan audit actor: %v", err)
+		}
+		actors = append(actors, actor)
+	}
+
+	return actors, nil
+}
+
+// scanAuditEvent scans one row selected with auditSelect
+func scanAuditEvent(rows *sql.Rows) (*AuditEvent, error) {
+	event := &AuditEvent{}
+	var actorID, targetID sql.NullInt64
+	err := rows.Scan(&event.ID, &actorID, &event.ActorName, &event.Action, &event.TargetType, &targetID,
+		&event.Before, &event.After, &event.IPAddress, &event.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan audit event: %v", err)
+	}
+
+	if actorID.Valid {
+		id := int(actorID.Int64)
+		event.ActorID = &id
+	}
+	if targetID.Valid {
+		id := int(targetID.Int64)
+		event.TargetID = &id
+	}
+	return event, nil
+}
+
+// auditFilter builds the WHERE clause shared by Each and Count
+func auditFilter(q AuditQuery) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if q.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, q.Action)
+	}
+	if q.ActorID > 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, q.ActorID)
+	}
+	if q.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, q.TargetType)
+	}
+	if q.TargetID > 0 {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, q.TargetID)
+	}
+	if q.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *q.From)
+	}
+	if q.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *q.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullableString stores an empty string as NULL
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -1,28 +1,49 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"go-web-app/database/dialect"
+	"strings"
 	"time"
 )
 
+// Blog publication statuses
+const (
+	BlogDraft            = "draft"
+	BlogPendingReview    = "pending_review"    // Submitted and waiting for an editor
+	BlogChangesRequested = "changes_requested" // Sent back to the author with review notes
+	BlogScheduled        = "scheduled"         // Published automatically once PublishAt has passed
+	BlogPublished        = "published"
+)
+
 // Blog represents a blog post in the system
 type Blog struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Excerpt   string    `json:"excerpt"`
-	Status    string    `json:"status"`
-	UserID    int       `json:"user_id"`
-	UserName  string    `json:"user_name,omitempty"` // For displaying author name
-	User      *User     `json:"user,omitempty"`      // For template access
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Content     string     `json:"content"`
+	Format      string     `json:"format"` // "markdown" or legacy "plain"
+	Excerpt     string     `json:"excerpt"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`   // When a scheduled post goes live
+	PublishedAt *time.Time `json:"published_at,omitempty"` // When the post actually went live
+	UserID      int        `json:"user_id"`
+	UserName    string     `json:"user_name,omitempty"` // For displaying author name
+	User        *User      `json:"user,omitempty"`      // For template access
+	CategoryID  *int       `json:"category_id"`
+	Category    *Category  `json:"category,omitempty"`
+	Tags        []*Tag     `json:"tags,omitempty"` // Only loaded where needed, see TagModel.GetByBlogID
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // BlogModel handles blog database operations
 type BlogModel struct {
 	DB *sql.DB
+	// QueryTimeout bounds each method's queries; zero waits as long as the caller's context allows
+	QueryTimeout time.Duration
 }
 
 // NewBlogModel creates a new BlogModel instance
@@ -30,238 +51,647 @@ func NewBlogModel(db *sql.DB) *BlogModel {
 	return &BlogModel{DB: db}
 }
 
-// Create creates a new blog post in the database
-func (m *BlogModel) Create(title, content, excerpt, status string, userID int) (*Blog, error) {
-	query := `INSERT INTO blogs (title, content, excerpt, status, user_id, created_at, updated_at) 
-			  VALUES (?, ?, ?, ?, ?, NOW(), NOW())`
-
-	result, err := m.DB.Exec(query, title, content, excerpt, status, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create blog: %v", err)
+// IsValidBlogStatus reports whether status is a known publication status
+func IsValidBlogStatus(status string) bool {
+	switch status {
+	case BlogDraft, BlogPendingReview, BlogChangesRequested, BlogScheduled, BlogPublished:
+		return true
 	}
+	return false
+}
 
-	// Get the inserted blog ID
-	id, err := result.LastInsertId()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get blog ID: %v", err)
+// StatusLabel returns the post's status for display
+func (b *Blog) StatusLabel() string {
+	switch b.Status {
+	case BlogPendingReview:
+		return "In review"
+	case BlogChangesRequested:
+		return "Changes requested"
 	}
+	return b.Status
+}
 
-	// Return the created blog
-	return m.GetByID(int(id))
+// IsPublished reports whether the post is publicly visible. A scheduled post
+// counts as published once its publish time has passed, even if the
+// background publisher has not flipped its status yet.
+func (b *Blog) IsPublished() bool {
+	if b.Status == BlogScheduled {
+		return b.PublishAt != nil && !b.PublishAt.After(time.Now())
+	}
+	return b.Status == BlogPublished
 }
 
-// GetByID retrieves a blog by ID
-func (m *BlogModel) GetByID(id int) (*Blog, error) {
-	blog := &Blog{}
-	query := `SELECT b.id, b.title, b.content, b.excerpt, b.status, b.user_id, u.name as user_name,
-			  b.created_at, b.updated_at 
+// publishedCondition matches publicly visible posts in queries aliasing blogs as b (see Blog.IsPublished)
+const publishedCondition = `(b.status = 'published' OR (b.status = 'scheduled' AND b.publish_at <= CURRENT_TIMESTAMP))`
+
+// blogSelect is the common column list (with author details) used by blog queries
+const blogSelect = `SELECT b.id, b.title, COALESCE(b.slug, ''), b.content, b.format, b.excerpt, b.status, b.publish_at, b.published_at, b.user_id,
+			  u.name as user_name, u.email as user_email, b.category_id, c.name, c.slug, b.created_at, b.updated_at
 			  FROM blogs b
 			  LEFT JOIN users u ON b.user_id = u.id
-			  WHERE b.id = ?`
+			  LEFT JOIN categories c ON b.category_id = c.id`
 
-	err := m.DB.QueryRow(query, id).Scan(
-		&blog.ID, &blog.Title, &blog.Content, &blog.Excerpt, &blog.Status, &blog.UserID, &blog.UserName,
-		&blog.CreatedAt, &blog.UpdatedAt,
-	)
+// scanBlog scans a row selected with blogSelect
+func scanBlog(row rowScanner) (*Blog, error) {
+	blog := &Blog{}
+	var excerpt, userName, userEmail, categoryName, categorySlug sql.NullString
+	var categoryID sql.NullInt64
+	var publishAt, publishedAt sql.NullTime
 
+	err := row.Scan(
+		&blog.ID, &blog.Title, &blog.Slug, &blog.Content, &blog.Format, &excerpt, &blog.Status, &publishAt, &publishedAt, &blog.UserID,
+		&userName, &userEmail, &categoryID, &categoryName, &categorySlug, &blog.CreatedAt, &blog.UpdatedAt,
+	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("blog not found")
+		return nil, err
+	}
+
+	blog.Excerpt = excerpt.String
+	blog.UserName = userName.String
+
+	if publishAt.Valid {
+		blog.PublishAt = &publishAt.Time
+	}
+	if publishedAt.Valid {
+		blog.PublishedAt = &publishedAt.Time
+	}
+
+	// Populate User field for template access
+	if blog.UserName != "" {
+		blog.User = &User{
+			ID:    blog.UserID,
+			Name:  blog.UserName,
+			Email: userEmail.String,
 		}
-		return nil, fmt.Errorf("failed to get blog: %v", err)
+	}
+
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		blog.CategoryID = &id
+		blog.Category = &Category{ID: id, Name: categoryName.String, Slug: categorySlug.String}
 	}
 
 	return blog, nil
 }
 
-// GetAll retrieves all blog posts with pagination (public posts)
-func (m *BlogModel) GetAll(limit, offset int) ([]*Blog, error) {
-	query := `SELECT b.id, b.title, b.content, b.excerpt, b.status, b.user_id, u.name as user_name,
-			  b.created_at, b.updated_at 
-			  FROM blogs b
-			  LEFT JOIN users u ON b.user_id = u.id
-			  WHERE b.status = 'published'
-			  ORDER BY b.created_at DESC
-			  LIMIT ? OFFSET ?`
-
-	rows, err := m.DB.Query(query, limit, offset)
+// queryBlogs runs a blogSelect based query and scans every row
+func (m *BlogModel) queryBlogs(ctx context.Context, query string, args ...interface{}) ([]*Blog, error) {
+	rows, err := conn(ctx, m.DB).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get blogs: %v", err)
+		return nil, err
 	}
 	defer rows.Close()
 
 	var blogs []*Blog
 	for rows.Next() {
-		blog := &Blog{}
-		err := rows.Scan(
-			&blog.ID, &blog.Title, &blog.Content, &blog.Excerpt, &blog.Status, &blog.UserID, &blog.UserName,
-			&blog.CreatedAt, &blog.UpdatedAt,
-		)
+		blog, err := scanBlog(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan blog: %v", err)
+			return nil, fmt.Errorf("failed to scan blog: %w", err)
 		}
 		blogs = append(blogs, blog)
 	}
 
-	return blogs, nil
+	return blogs, rows.Err()
 }
 
-// GetByUserID retrieves all blog posts by a specific user
-func (m *BlogModel) GetByUserID(userID int) ([]*Blog, error) {
-	query := `SELECT b.id, b.title, b.content, b.excerpt, b.status, b.user_id, u.name as user_name, u.email as user_email,
-			  b.created_at, b.updated_at 
-			  FROM blogs b
-			  LEFT JOIN users u ON b.user_id = u.id
-			  WHERE b.user_id = ?
-			  ORDER BY b.created_at DESC`
+// URL returns the public URL of the blog post
+func (b *Blog) URL() string {
+	if b.Slug == "" {
+		return fmt.Sprintf("/blog/%d", b.ID)
+	}
+	return "/blog/" + b.Slug
+}
 
-	rows, err := m.DB.Query(query, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user blogs: %v", err)
+// BlogInput holds the editable fields of a blog post
+type BlogInput struct {
+	Title   string
+	Slug    string // Manual slug override; empty generates one from the title
+	Content string
+	Format  string // Empty defaults to markdown for new posts and is kept on update
+	Excerpt string
+	Status  string
+	UserID  int
+	// CategoryID is nil to keep the current category on update; 0 clears it
+	CategoryID *int
+	// PublishAt is when a scheduled post goes live; ignored for other statuses
+	PublishAt *time.Time
+	// EditorID is the user saving this version, recorded on its revision; 0 means the author
+	EditorID int
+}
+
+// Create creates a new blog post in the database with a slug generated from the title
+func (m *BlogModel) Create(ctx context.Context, title, content, excerpt, status string, userID int) (*Blog, error) {
+	return m.CreateFrom(ctx, BlogInput{
+		Title:   title,
+		Content: content,
+		Excerpt: excerpt,
+		Status:  status,
+		UserID:  userID,
+	})
+}
+
+// CreateFrom creates a new blog post from input.
+// An empty slug is generated from the title, with a numeric suffix on collision.
+func (m *BlogModel) CreateFrom(ctx context.Context, in BlogInput) (*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	if in.Status == BlogScheduled && in.PublishAt == nil {
+		return nil, fmt.Errorf("scheduled blogs need a publish time")
 	}
-	defer rows.Close()
 
-	var blogs []*Blog
-	for rows.Next() {
-		blog := &Blog{}
-		var userEmail sql.NullString
+	if in.Format == "" {
+		in.Format = "markdown"
+	}
 
-		err := rows.Scan(
-			&blog.ID, &blog.Title, &blog.Content, &blog.Excerpt, &blog.Status, &blog.UserID, &blog.UserName, &userEmail,
-			&blog.CreatedAt, &blog.UpdatedAt,
-		)
+	// The post, as read back, and its first revision are saved together
+	var blog *Blog
+	err := Transaction(ctx, m.DB, func(ctx context.Context) error {
+		slug, err := m.resolveSlug(ctx, in.Title, in.Slug, 0)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan blog: %v", err)
+			return err
 		}
 
-		// Populate User field for template access
-		if blog.UserName != "" {
-			blog.User = &User{
-				ID:    blog.UserID,
-				Name:  blog.UserName,
-				Email: userEmail.String,
-			}
+		query := `INSERT INTO blogs (title, slug, content, format, excerpt, status, publish_at, published_at, user_id, category_id, created_at, updated_at) 
+				  VALUES (?, ?, ?, ?, ?, ?, ?, CASE WHEN ? = 'published' THEN CURRENT_TIMESTAMP ELSE NULL END, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+
+		id, err := dialect.Of(m.DB).InsertID(ctx, conn(ctx, m.DB), query, in.Title, slug, in.Content, in.Format, in.Excerpt, in.Status, schedulingTime(in),
+			in.Status, in.UserID, nullableID(in.CategoryID))
+		if err != nil {
+			return fmt.Errorf("failed to create blog: %w", err)
 		}
 
-		blogs = append(blogs, blog)
+		blog, err = m.GetByID(ctx, int(id))
+		if err != nil {
+			return err
+		}
+
+		// The first revision starts the post's history
+		editorID := in.EditorID
+		if editorID == 0 {
+			editorID = in.UserID
+		}
+		return NewRevisionModel(m.DB).Record(ctx, blog, editorID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return blogs, nil
+	// Return the created blog
+	return blog, nil
 }
 
-// GetByUserIDPaginated retrieves blogs by user ID with pagination
-func (m *BlogModel) GetByUserIDPaginated(userID, limit, offset int) ([]*Blog, error) {
-	query := `SELECT b.id, b.title, b.content, b.excerpt, b.status, b.user_id, u.name as user_name, u.email as user_email,
-			  b.created_at, b.updated_at 
-			  FROM blogs b
-			  LEFT JOIN users u ON b.user_id = u.id
-			  WHERE b.user_id = ?
-			  ORDER BY b.created_at DESC LIMIT ? OFFSET ?`
+// GetByID retrieves a blog by ID
+func (m *BlogModel) GetByID(ctx context.Context, id int) (*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
 
-	rows, err := m.DB.Query(query, userID, limit, offset)
+	blog, err := scanBlog(conn(ctx, m.DB).QueryRowContext(ctx, blogSelect+` WHERE b.id = ?`, id))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user blogs: %v", err)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("blog not found")
+		}
+		return nil, fmt.Errorf("failed to get blog: %w", err)
 	}
-	defer rows.Close()
 
-	var blogs []*Blog
-	for rows.Next() {
-		blog := &Blog{}
-		var userEmail sql.NullString
+	return blog, nil
+}
+
+// GetBySlug retrieves a blog by its current slug
+func (m *BlogModel) GetBySlug(ctx context.Context, slug string) (*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	blog, err := scanBlog(conn(ctx, m.DB).QueryRowContext(ctx, blogSelect+` WHERE b.slug = ?`, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("blog not found")
+		}
+		return nil, fmt.Errorf("failed to get blog: %w", err)
+	}
+
+	return blog, nil
+}
+
+// FindRedirect returns the ID of the blog that previously used slug
+func (m *BlogModel) FindRedirect(ctx context.Context, slug string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	var blogID int
+	err := conn(ctx, m.DB).QueryRowContext(ctx, `SELECT blog_id FROM blog_slug_redirects WHERE old_slug = ?`, slug).Scan(&blogID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("blog not found")
+		}
+		return 0, fmt.Errorf("failed to look up slug redirect: %w", err)
+	}
+
+	return blogID, nil
+}
+
+// SlugExists checks whether slug is used by another blog, either currently or as a redirect
+func (m *BlogModel) SlugExists(ctx context.Context, slug string, excludeID int) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	var count int
+	query := `SELECT
+				(SELECT COUNT(*) FROM blogs WHERE slug = ? AND id != ?) +
+				(SELECT COUNT(*) FROM blog_slug_redirects WHERE old_slug = ? AND blog_id != ?)`
+
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, slug, excludeID, slug, excludeID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check slug: %w", err)
+	}
+
+	return count > 0, nil
+}
 
-		err := rows.Scan(
-			&blog.ID, &blog.Title, &blog.Content, &blog.Excerpt, &blog.Status, &blog.UserID, &blog.UserName, &userEmail,
-			&blog.CreatedAt, &blog.UpdatedAt,
-		)
+// resolveSlug returns the slug to store for a blog. A manual slug must be free;
+// a generated one is suffixed (-2, -3, ...) until it is unique.
+func (m *BlogModel) resolveSlug(ctx context.Context, title, manual string, blogID int) (string, error) {
+	if manual != "" {
+		slug := Slugify(manual)
+		exists, err := m.SlugExists(ctx, slug, blogID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan blog: %v", err)
+			return "", err
 		}
+		if exists {
+			return "", fmt.Errorf("slug already exists")
+		}
+		return slug, nil
+	}
 
-		// Populate User field for template access
-		if blog.UserName != "" {
-			blog.User = &User{
-				ID:    blog.UserID,
-				Name:  blog.UserName,
-				Email: userEmail.String,
-			}
+	base := Slugify(title)
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := m.SlugExists(ctx, slug, blogID)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
 		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
 
-		blogs = append(blogs, blog)
+// GetAll retrieves published blog posts with pagination (public posts).
+// Scheduled posts appear once their publish time has passed.
+func (m *BlogModel) GetAll(ctx context.Context, limit, offset int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := blogSelect + `
+			  WHERE ` + publishedCondition + `
+			  ORDER BY b.created_at DESC
+			  LIMIT ? OFFSET ?`
+
+	blogs, err := m.queryBlogs(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blogs: %w", err)
+	}
+
+	return blogs, nil
+}
+
+// GetPublishedByUser retrieves published posts written by a user
+func (m *BlogModel) GetPublishedByUser(ctx context.Context, userID, limit, offset int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := blogSelect + `
+			  WHERE ` + publishedCondition + ` AND b.user_id = ?
+			  ORDER BY b.created_at DESC
+			  LIMIT ? OFFSET ?`
+
+	blogs, err := m.queryBlogs(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user blogs: %w", err)
+	}
+
+	return blogs, nil
+}
+
+// GetPublishedByCategories retrieves published posts filed under any of the given categories
+func (m *BlogModel) GetPublishedByCategories(ctx context.Context, categoryIDs []int, limit, offset int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	if len(categoryIDs) == 0 {
+		return []*Blog{}, nil
+	}
+
+	placeholders, args := inClause(categoryIDs)
+	query := blogSelect + `
+			  WHERE ` + publishedCondition + ` AND b.category_id IN (` + placeholders + `)
+			  ORDER BY b.created_at DESC
+			  LIMIT ? OFFSET ?`
+
+	blogs, err := m.queryBlogs(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category blogs: %w", err)
+	}
+
+	return blogs, nil
+}
+
+// CountPublishedByCategories returns the number of published posts filed under any of the given categories
+func (m *BlogModel) CountPublishedByCategories(ctx context.Context, categoryIDs []int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	if len(categoryIDs) == 0 {
+		return 0, nil
+	}
+
+	var count int
+	placeholders, args := inClause(categoryIDs)
+	query := `SELECT COUNT(*) FROM blogs b WHERE ` + publishedCondition + ` AND b.category_id IN (` + placeholders + `)`
+
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count category blogs: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetPublishedByTag retrieves published posts carrying a tag
+func (m *BlogModel) GetPublishedByTag(ctx context.Context, tagID, limit, offset int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := blogSelect + `
+			  INNER JOIN blog_tags bt ON bt.blog_id = b.id
+			  WHERE ` + publishedCondition + ` AND bt.tag_id = ?
+			  ORDER BY b.created_at DESC
+			  LIMIT ? OFFSET ?`
+
+	blogs, err := m.queryBlogs(ctx, query, tagID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag blogs: %w", err)
+	}
+
+	return blogs, nil
+}
+
+// CountPublishedByTag returns the number of published posts carrying a tag
+func (m *BlogModel) CountPublishedByTag(ctx context.Context, tagID int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM blogs b
+			  INNER JOIN blog_tags bt ON bt.blog_id = b.id
+			  WHERE ` + publishedCondition + ` AND bt.tag_id = ?`
+
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, tagID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tag blogs: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetByUserID retrieves all blog posts by a specific user
+func (m *BlogModel) GetByUserID(ctx context.Context, userID int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := blogSelect + `
+			  WHERE b.user_id = ?
+			  ORDER BY b.created_at DESC`
+
+	blogs, err := m.queryBlogs(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user blogs: %w", err)
+	}
+
+	return blogs, nil
+}
+
+// GetByUserIDPaginated retrieves blogs by user ID with pagination
+func (m *BlogModel) GetByUserIDPaginated(ctx context.Context, userID, limit, offset int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := blogSelect + `
+			  WHERE b.user_id = ?
+			  ORDER BY b.created_at DESC LIMIT ? OFFSET ?`
+
+	blogs, err := m.queryBlogs(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user blogs: %w", err)
 	}
 
 	return blogs, nil
 }
 
 // GetAllBlogs retrieves all blog posts for admin users with pagination
-func (m *BlogModel) GetAllBlogs(limit, offset int) ([]*Blog, error) {
-	query := `SELECT b.id, b.title, b.content, b.excerpt, b.status, b.user_id, u.name as user_name, u.email as user_email,
-			  b.created_at, b.updated_at 
-			  FROM blogs b
-			  LEFT JOIN users u ON b.user_id = u.id
+func (m *BlogModel) GetAllBlogs(ctx context.Context, limit, offset int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := blogSelect + `
 			  ORDER BY b.created_at DESC
 			  LIMIT ? OFFSET ?`
 
-	rows, err := m.DB.Query(query, limit, offset)
+	blogs, err := m.queryBlogs(ctx, query, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all blogs: %v", err)
+		return nil, fmt.Errorf("failed to get all blogs: %w", err)
 	}
-	defer rows.Close()
 
-	var blogs []*Blog
-	for rows.Next() {
-		blog := &Blog{}
-		var userEmail sql.NullString
+	return blogs, nil
+}
+
+// GetByStatus retrieves blogs with a specific status, least recently updated first
+func (m *BlogModel) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := blogSelect + `
+			  WHERE b.status = ?
+			  ORDER BY b.updated_at ASC, b.id ASC
+			  LIMIT ? OFFSET ?`
+
+	blogs, err := m.queryBlogs(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blogs by status: %w", err)
+	}
+
+	return blogs, nil
+}
+
+// Update updates a blog post, regenerating the slug if the title changed
+func (m *BlogModel) Update(ctx context.Context, id int, title, content, excerpt, status string) (*Blog, error) {
+	return m.UpdateFrom(ctx, id, BlogInput{
+		Title:   title,
+		Content: content,
+		Excerpt: excerpt,
+		Status:  status,
+	})
+}
+
+// UpdateFrom updates a blog post from input; in.UserID is ignored.
+// When the slug changes the previous one is kept as a redirect, and the saved
+// version is recorded as a new revision.
+func (m *BlogModel) UpdateFrom(ctx context.Context, id int, in BlogInput) (*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	if in.Status == BlogScheduled && in.PublishAt == nil {
+		return nil, fmt.Errorf("scheduled blogs need a publish time")
+	}
+
+	// The change, the old slug's redirect and the new revision are saved together
+	var blog *Blog
+	err := Transaction(ctx, m.DB, func(ctx context.Context) error {
+		current, err := m.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		newSlug := current.Slug
+		switch {
+		case in.Slug != "" && Slugify(in.Slug) != current.Slug:
+			newSlug, err = m.resolveSlug(ctx, in.Title, in.Slug, id)
+		case in.Slug == "" && (in.Title != current.Title || current.Slug == ""):
+			newSlug, err = m.resolveSlug(ctx, in.Title, "", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		format := in.Format
+		if format == "" {
+			format = current.Format
+		}
+
+		categoryID := in.CategoryID
+		if categoryID == nil {
+			categoryID = current.CategoryID
+		}
 
-		err := rows.Scan(
-			&blog.ID, &blog.Title, &blog.Content, &blog.Excerpt, &blog.Status, &blog.UserID, &blog.UserName, &userEmail,
-			&blog.CreatedAt, &blog.UpdatedAt,
-		)
+		// published_at keeps the first time the post went live
+		query := `UPDATE blogs SET title = ?, slug = ?, content = ?, format = ?, excerpt = ?, status = ?, publish_at = ?,
+				  published_at = CASE WHEN ? = 'published' THEN COALESCE(published_at, CURRENT_TIMESTAMP) ELSE published_at END, category_id = ?, updated_at = CURRENT_TIMESTAMP 
+				  WHERE id = ?`
+
+		_, err = conn(ctx, m.DB).ExecContext(ctx, query, in.Title, newSlug, in.Content, format, in.Excerpt, in.Status, schedulingTime(in),
+			in.Status, nullableID(categoryID), id)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan blog: %v", err)
+			return fmt.Errorf("failed to update blog: %w", err)
 		}
 
-		// Populate User field for template access
-		if blog.UserName != "" {
-			blog.User = &User{
-				ID:    blog.UserID,
-				Name:  blog.UserName,
-				Email: userEmail.String,
+		// Keep the old slug so existing links keep working
+		if current.Slug != "" && current.Slug != newSlug {
+			if err := m.addSlugRedirect(ctx, current.Slug, id); err != nil {
+				return err
 			}
 		}
 
-		blogs = append(blogs, blog)
+		blog, err = m.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Keep the saved version so it can be compared or restored later
+		editorID := in.EditorID
+		if editorID == 0 {
+			editorID = blog.UserID
+		}
+		return NewRevisionModel(m.DB).Record(ctx, blog, editorID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return blogs, nil
+	// Return the updated blog
+	return blog, nil
 }
 
-// Update updates a blog post
-func (m *BlogModel) Update(id int, title, content, excerpt, status string) (*Blog, error) {
-	query := `UPDATE blogs SET title = ?, content = ?, excerpt = ?, status = ?, updated_at = NOW() 
-			  WHERE id = ?`
+// TransitionStatus moves a post from one status to another on behalf of userID,
+// leaving its content alone and recording the change as a revision. It fails
+// when the post no longer has status from, so two reviewers cannot both act on
+// the same submission. Scheduling needs a publish time; use UpdateFrom for that.
+func (m *BlogModel) TransitionStatus(ctx context.Context, id int, from, to string, userID int) (*Blog, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	if to == BlogScheduled {
+		return nil, fmt.Errorf("scheduled blogs need a publish time")
+	}
+
+	query := `UPDATE blogs SET status = ?, publish_at = NULL,
+			  published_at = CASE WHEN ? = 'published' THEN COALESCE(published_at, CURRENT_TIMESTAMP) ELSE published_at END, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = ? AND status = ?`
+
+	// The new status and its revision are saved together
+	var blog *Blog
+	err := Transaction(ctx, m.DB, func(ctx context.Context) error {
+		result, err := conn(ctx, m.DB).ExecContext(ctx, query, to, to, id, from)
+		if err != nil {
+			return fmt.Errorf("failed to change blog status: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("blog status has changed")
+		}
+
+		blog, err = m.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	_, err := m.DB.Exec(query, title, content, excerpt, status, id)
+		return NewRevisionModel(m.DB).Record(ctx, blog, userID)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update blog: %v", err)
+		return nil, err
 	}
 
-	// Return the updated blog
-	return m.GetByID(id)
+	return blog, nil
+}
+
+// addSlugRedirect records that oldSlug now belongs to blogID
+func (m *BlogModel) addSlugRedirect(ctx context.Context, oldSlug string, blogID int) error {
+	// A blog may reclaim one of its own previous slugs; drop that redirect first
+	if _, err := conn(ctx, m.DB).ExecContext(ctx, `DELETE FROM blog_slug_redirects WHERE blog_id = ? AND old_slug = (SELECT slug FROM blogs WHERE id = ?)`, blogID, blogID); err != nil {
+		return fmt.Errorf("failed to clean slug redirects: %w", err)
+	}
+
+	query := dialect.Of(m.DB).Upsert(`INSERT INTO blog_slug_redirects (old_slug, blog_id, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)`,
+		"old_slug", "blog_id")
+
+	if _, err := conn(ctx, m.DB).ExecContext(ctx, query, oldSlug, blogID); err != nil {
+		return fmt.Errorf("failed to record slug redirect: %w", err)
+	}
+
+	return nil
 }
 
 // Delete deletes a blog post
-func (m *BlogModel) Delete(id int) error {
+func (m *BlogModel) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	query := `DELETE FROM blogs WHERE id = ?`
 
-	result, err := m.DB.Exec(query, id)
+	result, err := conn(ctx, m.DB).ExecContext(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete blog: %v", err)
+		return fmt.Errorf("failed to delete blog: %w", err)
 	}
 
 	// Check if any rows were affected
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %v", err)
+		return fmt.Errorf("failed to get affected rows: %w", err)
 	}
 
 	if rowsAffected == 0 {
@@ -272,91 +702,149 @@ func (m *BlogModel) Delete(id int) error {
 }
 
 // Count returns the total number of blog posts
-func (m *BlogModel) Count() (int, error) {
+func (m *BlogModel) Count(ctx context.Context) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM blogs`
 
-	err := m.DB.QueryRow(query).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count blogs: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountPublished returns the number of publicly visible blog posts (see GetAll)
+func (m *BlogModel) CountPublished(ctx context.Context) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	var count int
+	query := `SELECT COUNT(*) FROM blogs b WHERE ` + publishedCondition
+
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count blogs: %v", err)
+		return 0, fmt.Errorf("failed to count published blogs: %w", err)
 	}
 
 	return count, nil
 }
 
+// PublishDue publishes every scheduled post whose publish time has passed,
+// recording when it did so, and returns how many posts were published
+func (m *BlogModel) PublishDue(ctx context.Context) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE blogs SET status = 'published', published_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			  WHERE status = 'scheduled' AND publish_at <= CURRENT_TIMESTAMP`
+
+	result, err := conn(ctx, m.DB).ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish scheduled blogs: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
 // CountByStatus returns the number of blogs with a specific status
-func (m *BlogModel) CountByStatus(status string) (int, error) {
+func (m *BlogModel) CountByStatus(ctx context.Context, status string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM blogs WHERE status = ?`
 
-	err := m.DB.QueryRow(query, status).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, status).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count blogs by status: %v", err)
+		return 0, fmt.Errorf("failed to count blogs by status: %w", err)
 	}
 
 	return count, nil
 }
 
 // CountUserBlogsByStatus returns the number of blogs by status for a specific user
-func (m *BlogModel) CountUserBlogsByStatus(userID int, status string) (int, error) {
+func (m *BlogModel) CountUserBlogsByStatus(ctx context.Context, userID int, status string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM blogs WHERE user_id = ? AND status = ?`
 
-	err := m.DB.QueryRow(query, userID, status).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, userID, status).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count user blogs by status: %v", err)
+		return 0, fmt.Errorf("failed to count user blogs by status: %w", err)
 	}
 
 	return count, nil
 }
 
 // CountUserBlogs returns the total number of blog posts for a specific user
-func (m *BlogModel) CountUserBlogs(userID int) (int, error) {
+func (m *BlogModel) CountUserBlogs(ctx context.Context, userID int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM blogs WHERE user_id = ?`
 
-	err := m.DB.QueryRow(query, userID).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, userID).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count user blogs: %v", err)
+		return 0, fmt.Errorf("failed to count user blogs: %w", err)
 	}
 
 	return count, nil
 }
 
-// CanUserEdit checks if a user can edit a specific blog post
-func (m *BlogModel) CanUserEdit(blogID, userID int) (bool, error) {
+// OwnerID returns the ID of the user who wrote a blog post
+func (m *BlogModel) OwnerID(ctx context.Context, blogID int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var ownerID int
 	query := `SELECT user_id FROM blogs WHERE id = ?`
 
-	err := m.DB.QueryRow(query, blogID).Scan(&ownerID)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, blogID).Scan(&ownerID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return false, fmt.Errorf("blog not found")
+			return 0, fmt.Errorf("blog not found")
 		}
-		return false, fmt.Errorf("failed to check blog ownership: %v", err)
+		return 0, fmt.Errorf("failed to check blog ownership: %w", err)
 	}
 
-	return ownerID == userID, nil
+	return ownerID, nil
 }
 
-// CanUserDelete checks if a user can delete a specific blog post (owner or admin)
-func (m *BlogModel) CanUserDelete(blogID, userID int, userRole string) (bool, error) {
-	// Admin users can delete any blog
-	if userRole == "admin" {
-		return true, nil
+// schedulingTime returns the publish_at value to store for input: its PublishAt for scheduled posts, otherwise NULL
+func schedulingTime(in BlogInput) interface{} {
+	if in.Status != BlogScheduled || in.PublishAt == nil {
+		return nil
 	}
+	return *in.PublishAt
+}
 
-	// Check if user owns the blog
-	var ownerID int
-	query := `SELECT user_id FROM blogs WHERE id = ?`
-
-	err := m.DB.QueryRow(query, blogID).Scan(&ownerID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return false, fmt.Errorf("blog not found")
-		}
-		return false, fmt.Errorf("failed to check blog ownership: %v", err)
+// nullableID maps a missing or zero foreign key to SQL NULL
+func nullableID(id *int) interface{} {
+	if id == nil || *id == 0 {
+		return nil
 	}
+	return *id
+}
 
-	return ownerID == userID, nil
+// inClause builds "?, ?, ?" placeholders and matching arguments for an IN (...) list
+func inClause(ids []int) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ", "), args
 }
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Steps recorded in a post's review history
+const (
+	ReviewSubmitted        = "submitted"
+	ReviewWithdrawn        = "withdrawn"
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+)
+
+// BlogReview is one step of a post's editorial review
+type BlogReview struct {
+	ID        int       `json:"id"`
+	BlogID    int       `json:"blog_id"`
+	UserID    *int      `json:"user_id"`
+	UserName  string    `json:"user_name,omitempty"` // For displaying who took the step
+	Action    string    `json:"action"`
+	Notes     string    `json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BlogReviewModel handles blog review database operations
+type BlogReviewModel struct {
+	DB *sql.DB
+}
+
+// NewBlogReviewModel creates a new BlogReviewModel instance
+func NewBlogReviewModel(db *sql.DB) *BlogReviewModel {
+	return &BlogReviewModel{DB: db}
+}
+
+// ReviewAction returns the review step taken when a post moves from one status
+// to another, or "" when the change is not part of the review workflow
+func ReviewAction(from, to string) string {
+	switch {
+	case from == to:
+		return ""
+	case to == BlogPendingReview:
+		return ReviewSubmitted
+	case to == BlogChangesRequested:
+		return ReviewChangesRequested
+	case from == BlogPendingReview && (to == BlogPublished || to == BlogScheduled):
+		return ReviewApproved
+	case from == BlogPendingReview:
+		return ReviewWithdrawn
+	}
+	return ""
+}
+
+// Record adds a step taken by userID to a post's review history
+func (m *BlogReviewModel) Record(blogID, userID int, action, notes string) error {
+	query := `INSERT INTO blog_reviews (blog_id, user_id, action, notes, created_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	_, err := m.DB.Exec(query, blogID, nullableID(&userID), action, notes)
+	if err != nil {
+		return fmt.Errorf("failed to record blog review: %v", err)
+	}
+
+	return nil
+}
+
+// GetByBlogID retrieves a post's review history, newest first
+func (m *BlogReviewModel) GetByBlogID(blogID int) ([]*BlogReview, error) {
+	query := `SELECT r.id, r.blog_id, r.user_id, u.name, r.action, r.notes, r.created_at
+			  FROM blog_reviews r
+			  LEFT JOIN users u ON r.user_id = u.id
+			  WHERE r.blog_id = ?
+			  ORDER BY r.id DESC`
+
+	rows, err := m.DB.Query(query, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blog reviews: %v", err)
+	}
+	defer rows.Close()
+
+	var reviews []*BlogReview
+	for rows.Next() {
+		review := &BlogReview{}
+		var userID sql.NullInt64
+		var userName, notes sql.NullString
+
+		err := rows.Scan(&review.ID, &review.BlogID, &userID, &userName, &review.Action, &notes, &review.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blog review: %v", err)
+		}
+
+		if userID.Valid {
+			id := int(userID.Int64)
+			review.UserID = &id
+		}
+		review.UserName = userName.String
+		review.Notes = notes.String
+
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
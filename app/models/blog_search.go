@@ -0,0 +1,197 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"go-web-app/database/dialect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxSearchTerms bounds how many words of a query are sent to the index
+const maxSearchTerms = 10
+
+// SearchQuery describes a search over published blog posts.
+// Every filter is optional; zero values mean "no restriction".
+type SearchQuery struct {
+	Terms    string     // Free text matched against title, excerpt and content
+	AuthorID int        // Only posts by this user
+	TagID    int        // Only posts carrying this tag
+	From     *time.Time // Only posts created on or after this time
+	To       *time.Time // Only posts created before this time
+	Limit    int
+	Offset   int
+}
+
+// BlogSearcher finds published blog posts matching a query, ordered by
+// relevance, and reports the total number of matches for pagination
+type BlogSearcher interface {
+	Search(ctx context.Context, q SearchQuery) ([]*Blog, int, error)
+}
+
+// NewBlogSearcher returns the best searcher for the database behind blogs:
+// the FULLTEXT index on MySQL, and LIKE matching elsewhere
+func NewBlogSearcher(blogs *BlogModel) BlogSearcher {
+	if dialect.Of(blogs.DB).Name() == dialect.MySQL {
+		return NewFullTextSearcher(blogs)
+	}
+	return NewLikeSearcher(blogs)
+}
+
+// FullTextSearcher implements BlogSearcher using the MySQL FULLTEXT index on
+// title, excerpt and content (see migration 009)
+type FullTextSearcher struct {
+	Blogs *BlogModel
+}
+
+// NewFullTextSearcher creates a new FullTextSearcher over the given BlogModel
+func NewFullTextSearcher(blogs *BlogModel) *FullTextSearcher {
+	return &FullTextSearcher{Blogs: blogs}
+}
+
+// Search runs the query. Without search terms matches are ordered newest first.
+func (s *FullTextSearcher) Search(ctx context.Context, q SearchQuery) ([]*Blog, int, error) {
+	booleanQuery := FullTextQuery(q.Terms)
+
+	var conditions []string
+	var args []interface{}
+
+	if booleanQuery != "" {
+		conditions = append(conditions, "MATCH(b.title, b.excerpt, b.content) AGAINST (? IN BOOLEAN MODE)")
+		args = append(args, booleanQuery)
+	}
+
+	orderBy := " ORDER BY b.created_at DESC"
+	orderArgs := []interface{}{}
+	if booleanQuery != "" {
+		// MySQL evaluates an identical MATCH expression only once per row
+		orderBy = " ORDER BY MATCH(b.title, b.excerpt, b.content) AGAINST (? IN BOOLEAN MODE) DESC, b.created_at DESC"
+		orderArgs = append(orderArgs, booleanQuery)
+	}
+
+	return s.Blogs.search(ctx, q, conditions, args, orderBy, orderArgs)
+}
+
+// LikeSearcher implements BlogSearcher with LIKE matching, for databases
+// without a FULLTEXT index. Posts match any of the search words anywhere in
+// the title, excerpt or content, and rank by how many of the words they contain.
+type LikeSearcher struct {
+	Blogs *BlogModel
+}
+
+// NewLikeSearcher creates a new LikeSearcher over the given BlogModel
+func NewLikeSearcher(blogs *BlogModel) *LikeSearcher {
+	return &LikeSearcher{Blogs: blogs}
+}
+
+// Search runs the query. Without search terms matches are ordered newest first.
+func (s *LikeSearcher) Search(ctx context.Context, q SearchQuery) ([]*Blog, int, error) {
+	terms := SearchTerms(q.Terms)
+
+	var matches []string
+	var args, orderArgs []interface{}
+	for _, term := range terms {
+		// Terms hold only letters and digits, so there are no wildcards to escape
+		matches = append(matches, "(LOWER(b.title) LIKE ? OR LOWER(b.excerpt) LIKE ? OR LOWER(b.content) LIKE ?)")
+		pattern := "%" + term + "%"
+		args = append(args, pattern, pattern, pattern)
+		orderArgs = append(orderArgs, pattern, pattern, pattern)
+	}
+
+	var conditions []string
+	orderBy := " ORDER BY b.created_at DESC"
+	if len(matches) > 0 {
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+		ranks := make([]string, len(matches))
+		for i, match := range matches {
+			ranks[i] = "CASE WHEN " + match + " THEN 1 ELSE 0 END"
+		}
+		orderBy = " ORDER BY (" + strings.Join(ranks, " + ") + ") DESC, b.created_at DESC"
+	}
+
+	return s.Blogs.search(ctx, q, conditions, args, orderBy, orderArgs)
+}
+
+// search runs a search over published posts with the searcher's text
+// conditions, adding the query's filters and pagination
+func (m *BlogModel) search(ctx context.Context, q SearchQuery, conditions []string, args []interface{}, orderBy string, orderArgs []interface{}) ([]*Blog, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	conditions = append([]string{publishedCondition}, conditions...)
+
+	if q.AuthorID > 0 {
+		conditions = append(conditions, "b.user_id = ?")
+		args = append(args, q.AuthorID)
+	}
+	if q.TagID > 0 {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM blog_tags bt WHERE bt.blog_id = b.id AND bt.tag_id = ?)")
+		args = append(args, q.TagID)
+	}
+	if q.From != nil {
+		conditions = append(conditions, "b.created_at >= ?")
+		args = append(args, *q.From)
+	}
+	if q.To != nil {
+		conditions = append(conditions, "b.created_at < ?")
+		args = append(args, *q.To)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	// Get total match count for pagination
+	var total int
+	err := conn(ctx, m.DB).QueryRowContext(ctx, `SELECT COUNT(*) FROM blogs b`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	if total == 0 {
+		return []*Blog{}, 0, nil
+	}
+
+	query := blogSelect + where + orderBy + " LIMIT ? OFFSET ?"
+	queryArgs := append(append(args, orderArgs...), q.Limit, q.Offset)
+
+	blogs, err := m.queryBlogs(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search blogs: %w", err)
+	}
+
+	return blogs, total, nil
+}
+
+// SearchTerms splits a user query into lowercase words, dropping punctuation
+// and duplicates. At most maxSearchTerms words are kept.
+func SearchTerms(input string) []string {
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// FullTextQuery converts a user query into a MySQL boolean-mode search string.
+// Each word is matched as a prefix; posts matching more words rank higher.
+// Operators typed by the user are stripped so they cannot alter the query.
+func FullTextQuery(input string) string {
+	terms := SearchTerms(input)
+	for i, term := range terms {
+		terms[i] = term + "*"
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,356 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Category represents a node in the hierarchical blog category tree
+type Category struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description,omitempty"`
+	ParentID    *int      `json:"parent_id,omitempty"`
+	Depth       int       `json:"-"` // Nesting level, set by BuildCategoryTree
+	BlogCount   int       `json:"blog_count,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// CategoryModel handles category database operations
+type CategoryModel struct {
+	DB *sql.DB
+}
+
+// NewCategoryModel creates a new CategoryModel instance
+func NewCategoryModel(db *sql.DB) *CategoryModel {
+	return &CategoryModel{DB: db}
+}
+
+// URL returns the public URL of the category listing
+func (c *Category) URL() string {
+	return "/category/" + c.Slug
+}
+
+// Indent returns a prefix for showing the category at its depth in a flat list
+func (c *Category) Indent() string {
+	return strings.Repeat("— ", c.Depth)
+}
+
+// categorySelect is the common column list used by category queries
+const categorySelect = `SELECT c.id, c.name, c.slug, COALESCE(c.description, ''), c.parent_id, c.created_at, c.updated_at,
+			  (SELECT COUNT(*) FROM blogs b WHERE b.category_id = c.id) AS blog_count
+			  FROM categories c`
+
+// scanCategory scans a row selected with categorySelect
+func scanCategory(row rowScanner) (*Category, error) {
+	category := &Category{}
+	var parentID sql.NullInt64
+
+	err := row.Scan(
+		&category.ID, &category.Name, &category.Slug, &category.Description, &parentID,
+		&category.CreatedAt, &category.UpdatedAt, &category.BlogCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		category.ParentID = &id
+	}
+
+	return category, nil
+}
+
+// Create creates a new category. An empty slug is generated from the name.
+func (m *CategoryModel) Create(name, slug, description string, parentID *int) (*Category, error) {
+	slug, err := m.resolveSlug(name, slug, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		if _, err := m.GetByID(*parentID); err != nil {
+			return nil, fmt.Errorf("parent category not found")
+		}
+	}
+
+	query := `INSERT INTO categories (name, slug, description, parent_id, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+
+	id, err := dialect.Of(m.DB).InsertID(context.Background(), m.DB, query, name, slug, description, nullableID(parentID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %v", err)
+	}
+
+	return m.GetByID(int(id))
+}
+
+// GetByID retrieves a category by ID
+func (m *CategoryModel) GetByID(id int) (*Category, error) {
+	category, err := scanCategory(m.DB.QueryRow(categorySelect+` WHERE c.id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %v", err)
+	}
+
+	return category, nil
+}
+
+// GetBySlug retrieves a category by slug
+func (m *CategoryModel) GetBySlug(slug string) (*Category, error) {
+	category, err := scanCategory(m.DB.QueryRow(categorySelect+` WHERE c.slug = ?`, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %v", err)
+	}
+
+	return category, nil
+}
+
+// GetAll retrieves every category ordered by name
+func (m *CategoryModel) GetAll() ([]*Category, error) {
+	rows, err := m.DB.Query(categorySelect + ` ORDER BY c.name ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %v", err)
+	}
+	defer rows.Close()
+
+	var categories []*Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// GetTree retrieves every category in tree order, see BuildCategoryTree
+func (m *CategoryModel) GetTree() ([]*Category, error) {
+	categories, err := m.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return BuildCategoryTree(categories), nil
+}
+
+// GetChildren retrieves the direct subcategories of a category
+func (m *CategoryModel) GetChildren(id int) ([]*Category, error) {
+	rows, err := m.DB.Query(categorySelect+` WHERE c.parent_id = ? ORDER BY c.name ASC`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subcategories: %v", err)
+	}
+	defer rows.Close()
+
+	var categories []*Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// DescendantIDs returns the ID of a category together with all of its descendants
+func (m *CategoryModel) DescendantIDs(id int) ([]int, error) {
+	categories, err := m.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return CategoryDescendantIDs(categories, id), nil
+}
+
+// Update updates a category. Moving a category below itself or one of its
+// descendants is rejected so the tree never contains a cycle.
+func (m *CategoryModel) Update(id int, name, slug, description string, parentID *int) (*Category, error) {
+	current, err := m.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	newSlug := current.Slug
+	if slug != "" && Slugify(slug) != current.Slug {
+		newSlug, err = m.resolveSlug(name, slug, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if parentID != nil {
+		descendants, err := m.DescendantIDs(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, descendantID := range descendants {
+			if descendantID == *parentID {
+				return nil, fmt.Errorf("category cannot be moved below itself")
+			}
+		}
+		if _, err := m.GetByID(*parentID); err != nil {
+			return nil, fmt.Errorf("parent category not found")
+		}
+	}
+
+	query := `UPDATE categories SET name = ?, slug = ?, description = ?, parent_id = ?, updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = ?`
+
+	_, err = m.DB.Exec(query, name, newSlug, description, nullableID(parentID), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update category: %v", err)
+	}
+
+	return m.GetByID(id)
+}
+
+// Delete deletes a category. Its posts become uncategorised and its
+// subcategories move up to the deleted category's parent.
+func (m *CategoryModel) Delete(id int) error {
+	current, err := m.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.DB.Exec(`UPDATE categories SET parent_id = ? WHERE parent_id = ?`, nullableID(current.ParentID), id)
+	if err != nil {
+		return fmt.Errorf("failed to reparent subcategories: %v", err)
+	}
+
+	result, err := m.DB.Exec(`DELETE FROM categories WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("category not found")
+	}
+
+	return nil
+}
+
+// resolveSlug returns a unique slug for a category. A manual slug must be free;
+// a generated one is suffixed (-2, -3, ...) until it is unique.
+func (m *CategoryModel) resolveSlug(name, manual string, categoryID int) (string, error) {
+	exists := func(slug string) (bool, error) {
+		var count int
+		err := m.DB.QueryRow(`SELECT COUNT(*) FROM categories WHERE slug = ? AND id != ?`, slug, categoryID).Scan(&count)
+		if err != nil {
+			return false, fmt.Errorf("failed to check slug: %v", err)
+		}
+		return count > 0, nil
+	}
+
+	if manual != "" {
+		slug := Slugify(manual)
+		taken, err := exists(slug)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", fmt.Errorf("slug already exists")
+		}
+		return slug, nil
+	}
+
+	base := Slugify(name)
+	slug := base
+	for i := 2; ; i++ {
+		taken, err := exists(slug)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// BuildCategoryTree orders categories depth-first (parents before their
+// children, siblings by name) and sets each category's Depth.
+// Categories whose parent is missing are treated as roots.
+func BuildCategoryTree(categories []*Category) []*Category {
+	byID := make(map[int]*Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	children := make(map[int][]*Category)
+	var roots []*Category
+	for _, c := range categories {
+		if c.ParentID != nil && byID[*c.ParentID] != nil && *c.ParentID != c.ID {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	byName := func(list []*Category) {
+		sort.SliceStable(list, func(i, j int) bool {
+			return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+		})
+	}
+
+	tree := make([]*Category, 0, len(categories))
+	visited := make(map[int]bool, len(categories))
+	var walk func(list []*Category, depth int)
+	walk = func(list []*Category, depth int) {
+		byName(list)
+		for _, c := range list {
+			if visited[c.ID] {
+				continue
+			}
+			visited[c.ID] = true
+			c.Depth = depth
+			tree = append(tree, c)
+			walk(children[c.ID], depth+1)
+		}
+	}
+	walk(roots, 0)
+
+	return tree
+}
+
+// CategoryDescendantIDs returns id followed by the IDs of every category below it
+func CategoryDescendantIDs(categories []*Category, id int) []int {
+	children := make(map[int][]int)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	ids := []int{id}
+	seen := map[int]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,285 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"time"
+)
+
+// Comment moderation statuses
+const (
+	CommentPending  = "pending"
+	CommentApproved = "approved"
+	CommentHidden   = "hidden"
+)
+
+// MaxCommentLength is the longest comment body accepted
+const MaxCommentLength = 5000
+
+// Comment represents a reader comment on a blog post; replies point at their parent
+type Comment struct {
+	ID        int        `json:"id"`
+	BlogID    int        `json:"blog_id"`
+	UserID    int        `json:"user_id"`
+	ParentID  *int       `json:"parent_id,omitempty"`
+	Body      string     `json:"body"`
+	Status    string     `json:"status"`
+	UserName  string     `json:"user_name,omitempty"`  // For displaying the commenter
+	BlogTitle string     `json:"blog_title,omitempty"` // For the moderation queue
+	BlogSlug  string     `json:"blog_slug,omitempty"`  // For the moderation queue
+	Replies   []*Comment `json:"replies,omitempty"`    // Set by BuildCommentTree
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CommentModel handles comment database operations
+type CommentModel struct {
+	DB *sql.DB
+}
+
+// NewCommentModel creates a new CommentModel instance
+func NewCommentModel(db *sql.DB) *CommentModel {
+	return &CommentModel{DB: db}
+}
+
+// IsApproved reports whether the comment is publicly visible
+func (c *Comment) IsApproved() bool {
+	return c.Status == CommentApproved
+}
+
+// IsPending reports whether the comment awaits moderation
+func (c *Comment) IsPending() bool {
+	return c.Status == CommentPending
+}
+
+// BlogURL returns the public URL of the comment on its blog post
+func (c *Comment) BlogURL() string {
+	blog := &Blog{ID: c.BlogID, Slug: c.BlogSlug}
+	return fmt.Sprintf("%s#comment-%d", blog.URL(), c.ID)
+}
+
+// IsValidCommentStatus reports whether status is a known comment status
+func IsValidCommentStatus(status string) bool {
+	return status == CommentPending || status == CommentApproved || status == CommentHidden
+}
+
+// commentSelect is the common column list (with commenter and post details) used by comment queries
+const commentSelect = `SELECT c.id, c.blog_id, c.user_id, c.parent_id, c.body, c.status,
+			  u.name, b.title, COALESCE(b.slug, ''), c.created_at, c.updated_at
+			  FROM comments c
+			  LEFT JOIN users u ON c.user_id = u.id
+			  LEFT JOIN blogs b ON c.blog_id = b.id`
+
+// scanComment scans a row selected with commentSelect
+func scanComment(row rowScanner) (*Comment, error) {
+	comment := &Comment{}
+	var parentID sql.NullInt64
+	var userName, blogTitle sql.NullString
+
+	err := row.Scan(
+		&comment.ID, &comment.BlogID, &comment.UserID, &parentID, &comment.Body, &comment.Status,
+		&userName, &blogTitle, &comment.BlogSlug, &comment.CreatedAt, &comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	comment.UserName = userName.String
+	comment.BlogTitle = blogTitle.String
+
+	return comment, nil
+}
+
+// queryComments runs a commentSelect based query and scans every row
+func (m *CommentModel) queryComments(query string, args ...interface{}) ([]*Comment, error) {
+	rows, err := m.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %v", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// Create adds a comment to a blog post. A reply must belong to the same post.
+func (m *CommentModel) Create(blogID, userID int, parentID *int, body, status string) (*Comment, error) {
+	if !IsValidCommentStatus(status) {
+		return nil, fmt.Errorf("invalid comment status")
+	}
+
+	if parentID != nil {
+		parent, err := m.GetByID(*parentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent comment not found")
+		}
+		if parent.BlogID != blogID {
+			return nil, fmt.Errorf("parent comment belongs to another blog")
+		}
+	}
+
+	query := `INSERT INTO comments (blog_id, user_id, parent_id, body, status, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+
+	id, err := dialect.Of(m.DB).InsertID(context.Background(), m.DB, query, blogID, userID, nullableID(parentID), body, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %v", err)
+	}
+
+	return m.GetByID(int(id))
+}
+
+// GetByID retrieves a comment by ID
+func (m *CommentModel) GetByID(id int) (*Comment, error) {
+	comment, err := scanComment(m.DB.QueryRow(commentSelect+` WHERE c.id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, fmt.Errorf("failed to get comment: %v", err)
+	}
+
+	return comment, nil
+}
+
+// GetByBlogID retrieves every comment on a blog post, oldest first
+func (m *CommentModel) GetByBlogID(blogID int) ([]*Comment, error) {
+	comments, err := m.queryComments(commentSelect+` WHERE c.blog_id = ? ORDER BY c.created_at ASC, c.id ASC`, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %v", err)
+	}
+
+	return comments, nil
+}
+
+// GetByStatus retrieves comments with a status across all posts, oldest first
+func (m *CommentModel) GetByStatus(status string, limit, offset int) ([]*Comment, error) {
+	query := commentSelect + `
+			  WHERE c.status = ?
+			  ORDER BY c.created_at ASC, c.id ASC
+			  LIMIT ? OFFSET ?`
+
+	comments, err := m.queryComments(query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %v", err)
+	}
+
+	return comments, nil
+}
+
+// CountByStatus returns the number of comments with a status
+func (m *CommentModel) CountByStatus(status string) (int, error) {
+	var count int
+	err := m.DB.QueryRow(`SELECT COUNT(*) FROM comments WHERE status = ?`, status).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments by status: %v", err)
+	}
+
+	return count, nil
+}
+
+// SetStatus changes the moderation status of a comment
+func (m *CommentModel) SetStatus(id int, status string) error {
+	affected, err := m.SetStatusBulk([]int{id}, status)
+	if err != nil {
+		return err
+	}
+
+	// MySQL reports zero affected rows when the status is unchanged, so confirm the comment exists
+	if affected == 0 {
+		if _, err := m.GetByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetStatusBulk changes the moderation status of several comments and
+// returns how many were changed
+func (m *CommentModel) SetStatusBulk(ids []int, status string) (int, error) {
+	if !IsValidCommentStatus(status) {
+		return 0, fmt.Errorf("invalid comment status")
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	placeholders, args := inClause(ids)
+	query := `UPDATE comments SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id IN (` + placeholders + `)`
+
+	result, err := m.DB.Exec(query, append([]interface{}{status}, args...)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update comment status: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// Delete deletes a comment together with its replies
+func (m *CommentModel) Delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM comments WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("comment not found")
+	}
+
+	return nil
+}
+
+// BuildCommentTree nests comments under their parents and returns the top-level
+// comments in their original order. Comments for which visible returns false are
+// dropped together with their replies, as are replies whose parent is missing.
+func BuildCommentTree(comments []*Comment, visible func(*Comment) bool) []*Comment {
+	byID := make(map[int]*Comment, len(comments))
+	for _, c := range comments {
+		c.Replies = nil
+		if visible(c) {
+			byID[c.ID] = c
+		}
+	}
+
+	var roots []*Comment
+	for _, c := range comments {
+		if byID[c.ID] == nil {
+			continue
+		}
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		if parent := byID[*c.ParentID]; parent != nil && parent != c {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+
+	// Replies attached to a dropped ancestor are unreachable from roots, so they disappear with it
+	return roots
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Impersonation actions
+const (
+	ImpersonationStarted = "started"
+	ImpersonationStopped = "stopped"
+)
+
+// ImpersonationEvent records an admin starting or stopping impersonation of a
+// user. Names are copied so entries stay readable after an account is deleted.
+type ImpersonationEvent struct {
+	ID        int64     `json:"id"`
+	AdminID   *int      `json:"admin_id"`
+	AdminName string    `json:"admin_name"`
+	UserID    *int      `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Action    string    `json:"action"`
+	IPAddress string    `json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ImpersonationModel handles impersonation event database operations
+type ImpersonationModel struct {
+	DB *sql.DB
+}
+
+// NewImpersonationModel creates a new ImpersonationModel instance
+func NewImpersonationModel(db *sql.DB) *ImpersonationModel {
+	return &ImpersonationModel{DB: db}
+}
+
+// Record stores an impersonation event; its ID and CreatedAt are ignored. Inside a
+// Transaction the event is only kept if the unit of work commits.
+func (m *ImpersonationModel) Record(ctx context.Context, event *ImpersonationEvent) error {
+	query := `INSERT INTO impersonation_events (admin_id, admin_name, user_id, user_name, action, ip_address, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	_, err := conn(ctx, m.DB).ExecContext(ctx, query, nullableID(event.AdminID), event.AdminName,
+		nullableID(event.UserID), event.UserName, event.Action, event.IPAddress)
+	if err != nil {
+		return fmt.Errorf("failed to record impersonation event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"strings"
+	"time"
+)
+
+// LoginAttempt represents one sign-in attempt for an email address
+type LoginAttempt struct {
+	ID         int        `json:"id"`
+	Email      string     `json:"email"`
+	IPAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent"`
+	Successful bool       `json:"successful"`
+	ClearedAt  *time.Time `json:"cleared_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// LoginAttemptModel handles login attempt database operations
+type LoginAttemptModel struct {
+	DB *sql.DB
+}
+
+// NewLoginAttemptModel creates a new LoginAttemptModel instance
+func NewLoginAttemptModel(db *sql.DB) *LoginAttemptModel {
+	return &LoginAttemptModel{DB: db}
+}
+
+// NormalizeLoginEmail lower-cases and trims an email so attempts on one
+// account are counted together however it was typed
+func NormalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Record stores a login attempt. A successful attempt clears the earlier
+// failures for the same email so they no longer count towards throttling.
+func (m *LoginAttemptModel) Record(email, ip, userAgent string, successful bool) error {
+	email = NormalizeLoginEmail(email)
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	query := `INSERT INTO login_attempts (email, ip_address, user_agent, successful, created_at)
+			  VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	if _, err := m.DB.Exec(query, email, ip, userAgent, successful); err != nil {
+		return fmt.Errorf("failed to record login attempt: %v", err)
+	}
+
+	if successful {
+		return m.Clear(email)
+	}
+
+	return nil
+}
+
+// Clear marks an email's outstanding failures as cleared, lifting any backoff
+// or lockout. The attempts themselves are kept for the activity log.
+func (m *LoginAttemptModel) Clear(email string) error {
+	query := `UPDATE login_attempts SET cleared_at = CURRENT_TIMESTAMP
+			  WHERE email = ? AND successful = FALSE AND cleared_at IS NULL`
+
+	if _, err := m.DB.Exec(query, NormalizeLoginEmail(email)); err != nil {
+		return fmt.Errorf("failed to clear login attempts: %v", err)
+	}
+
+	return nil
+}
+
+// FailuresByEmail counts an email's uncleared failures within the window and
+// returns the time since the most recent one
+func (m *LoginAttemptModel) FailuresByEmail(email string, window time.Duration) (int, time.Duration, error) {
+	return m.failures("email", NormalizeLoginEmail(email), window)
+}
+
+// FailuresByIP counts a client IP's uncleared failures within the window and
+// returns the time since the most recent one
+func (m *LoginAttemptModel) FailuresByIP(ip string, window time.Duration) (int, time.Duration, error) {
+	return m.failures("ip_address", ip, window)
+}
+
+// failures counts uncleared failures where column matches value. The age of
+// the last failure is computed by the database so it agrees with created_at.
+func (m *LoginAttemptModel) failures(column, value string, window time.Duration) (int, time.Duration, error) {
+	d := dialect.Of(m.DB)
+	query := `SELECT COUNT(*), COALESCE(` + d.SecondsSince("MAX(created_at)") + `, 0)
+			  FROM login_attempts
+			  WHERE ` + column + ` = ? AND successful = FALSE AND cleared_at IS NULL
+			  AND created_at > ` + d.AddSeconds("CURRENT_TIMESTAMP")
+
+	var count int
+	var seconds int64
+	err := m.DB.QueryRow(query, value, -int(window.Seconds())).Scan(&count, &seconds)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count login failures: %v", err)
+	}
+
+	return count, time.Duration(seconds) * time.Second, nil
+}
+
+// GetRecent retrieves login attempts newest first, optionally filtered by
+// email and/or IP address
+func (m *LoginAttemptModel) GetRecent(email, ip string, limit, offset int) ([]*LoginAttempt, error) {
+	where, args := loginAttemptFilter(email, ip)
+	query := `SELECT id, email, ip_address, user_agent, successful, cleared_at, created_at
+			  FROM login_attempts` + where + `
+			  ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+
+	rows, err := m.DB.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login attempts: %v", err)
+	}
+	defer rows.Close()
+
+	var attempts []*LoginAttempt
+	for rows.Next() {
+		attempt := &LoginAttempt{}
+		var clearedAt sql.NullTime
+		err := rows.Scan(&attempt.ID, &attempt.Email, &attempt.IPAddress, &attempt.UserAgent,
+			&attempt.Successful, &clearedAt, &attempt.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan login attempt: %v", err)
+		}
+		if clearedAt.Valid {
+			attempt.ClearedAt = &clearedAt.Time
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
+
+// Count returns the number of login attempts matching the same filters as GetRecent
+func (m *LoginAttemptModel) Count(email, ip string) (int, error) {
+	where, args := loginAttemptFilter(email, ip)
+
+	var count int
+	err := m.DB.QueryRow(`SELECT COUNT(*) FROM login_attempts`+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count login attempts: %v", err)
+	}
+
+	return count, nil
+}
+
+// loginAttemptFilter builds the WHERE clause for the email and IP filters
+func loginAttemptFilter(email, ip string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if email = NormalizeLoginEmail(email); email != "" {
+		conditions = append(conditions, "email = ?")
+		args = append(args, email)
+	}
+	if ip = strings.TrimSpace(ip); ip != "" {
+		conditions = append(conditions, "ip_address = ?")
+		args = append(args, ip)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
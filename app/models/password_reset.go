@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"go-web-app/database/dialect"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordResetTTL is how long a password reset link stays valid
+const PasswordResetTTL = time.Hour
+
+// PasswordReset represents a single-use password reset token (only the hash is stored)
+type PasswordReset struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PasswordResetModel handles password reset token database operations
+type PasswordResetModel struct {
+	DB *sql.DB
+}
+
+// NewPasswordResetModel creates a new PasswordResetModel instance
+func NewPasswordResetModel(db *sql.DB) *PasswordResetModel {
+	return &PasswordResetModel{DB: db}
+}
+
+// Create issues a reset token for a user, replacing any unused ones, and
+// returns the plaintext token. The plaintext is never stored.
+func (m *PasswordResetModel) Create(userID int) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %v", err)
+	}
+	plain := hex.EncodeToString(buf)
+
+	// Only the most recently requested link works
+	_, err := m.DB.Exec(`DELETE FROM password_resets WHERE user_id = ? AND used_at IS NULL`, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to clear old reset tokens: %v", err)
+	}
+
+	query := `INSERT INTO password_resets (user_id, token_hash, expires_at, created_at)
+			  VALUES (?, ?, ` + dialect.Of(m.DB).AddSeconds("CURRENT_TIMESTAMP") + `, CURRENT_TIMESTAMP)`
+
+	_, err = m.DB.Exec(query, userID, HashAPIToken(plain), int(PasswordResetTTL.Seconds()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create reset token: %v", err)
+	}
+
+	return plain, nil
+}
+
+// FindValid looks up an unused, unexpired reset token by its plaintext value
+func (m *PasswordResetModel) FindValid(plain string) (*PasswordReset, error) {
+	reset, err := scanPasswordReset(m.DB.QueryRow(passwordResetSelect+` WHERE token_hash = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP`, HashAPIToken(plain)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid or expired reset token")
+		}
+		return nil, fmt.Errorf("failed to look up reset token: %v", err)
+	}
+
+	return reset, nil
+}
+
+// Reset sets a new password using a valid token and returns the user ID. The
+// token is used up, and the user's session version is bumped so every existing
+// login session is signed out. All of it happens in one unit of work (see
+// Transaction).
+func (m *PasswordResetModel) Reset(ctx context.Context, plain, newPassword string) (int, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	var userID int
+	err = Transaction(ctx, m.DB, func(ctx context.Context) error {
+		db := conn(ctx, m.DB)
+
+		// Lock the token so two concurrent requests cannot both use it
+		reset, err := scanPasswordReset(db.QueryRowContext(ctx, passwordResetSelect+` WHERE token_hash = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP`+dialect.Of(m.DB).ForUpdate(), HashAPIToken(plain)))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("invalid or expired reset token")
+			}
+			return fmt.Errorf("failed to look up reset token: %w", err)
+		}
+
+		query := `UPDATE users SET password = ?, session_version = session_version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+		if _, err := db.ExecContext(ctx, query, string(hashedPassword), reset.UserID); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		// Use up this token along with any other outstanding ones
+		if _, err := db.ExecContext(ctx, `UPDATE password_resets SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND used_at IS NULL`, reset.UserID); err != nil {
+			return fmt.Errorf("failed to use reset token: %w", err)
+		}
+
+		userID = reset.UserID
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// passwordResetSelect is the common column list used by password reset queries
+const passwordResetSelect = `SELECT id, user_id, token_hash, expires_at, used_at, created_at FROM password_resets`
+
+// scanPasswordReset scans a row selected with passwordResetSelect
+func scanPasswordReset(row rowScanner) (*PasswordReset, error) {
+	reset := &PasswordReset{}
+	var usedAt sql.NullTime
+
+	err := row.Scan(&reset.ID, &reset.UserID, &reset.TokenHash, &reset.ExpiresAt, &usedAt, &reset.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		reset.UsedAt = &usedAt.Time
+	}
+
+	return reset, nil
+}
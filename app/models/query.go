@@ -0,0 +1,15 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// withQueryTimeout bounds ctx by a model's QueryTimeout. A zero timeout only
+// follows ctx, which is cancelled when the client disconnects.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,62 @@
+package models
+
+import "context"
+
+// BlogRepository stores blog posts. BlogModel implements it for every
+// supported database (MySQL, PostgreSQL and SQLite), adapting the SQL to the
+// driver the connection was opened with; see database/dialect.
+type BlogRepository interface {
+	Create(ctx context.Context, title, content, excerpt, status string, userID int) (*Blog, error)
+	CreateFrom(ctx context.Context, in BlogInput) (*Blog, error)
+	GetByID(ctx context.Context, id int) (*Blog, error)
+	GetBySlug(ctx context.Context, slug string) (*Blog, error)
+	FindRedirect(ctx context.Context, slug string) (int, error)
+	SlugExists(ctx context.Context, slug string, excludeID int) (bool, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*Blog, error)
+	GetPublishedByUser(ctx context.Context, userID, limit, offset int) ([]*Blog, error)
+	GetPublishedByCategories(ctx context.Context, categoryIDs []int, limit, offset int) ([]*Blog, error)
+	CountPublishedByCategories(ctx context.Context, categoryIDs []int) (int, error)
+	GetPublishedByTag(ctx context.Context, tagID, limit, offset int) ([]*Blog, error)
+	CountPublishedByTag(ctx context.Context, tagID int) (int, error)
+	GetByUserID(ctx context.Context, userID int) ([]*Blog, error)
+	GetByUserIDPaginated(ctx context.Context, userID, limit, offset int) ([]*Blog, error)
+	GetAllBlogs(ctx context.Context, limit, offset int) ([]*Blog, error)
+	GetByStatus(ctx context.Context, status string, limit, offset int) ([]*Blog, error)
+	Update(ctx context.Context, id int, title, content, excerpt, status string) (*Blog, error)
+	UpdateFrom(ctx context.Context, id int, in BlogInput) (*Blog, error)
+	TransitionStatus(ctx context.Context, id int, from, to string, userID int) (*Blog, error)
+	Delete(ctx context.Context, id int) error
+	Count(ctx context.Context) (int, error)
+	CountPublished(ctx context.Context) (int, error)
+	PublishDue(ctx context.Context) (int, error)
+	CountByStatus(ctx context.Context, status string) (int, error)
+	CountUserBlogsByStatus(ctx context.Context, userID int, status string) (int, error)
+	CountUserBlogs(ctx context.Context, userID int) (int, error)
+	OwnerID(ctx context.Context, blogID int) (int, error)
+}
+
+// UserRepository stores user accounts. UserModel implements it for every
+// supported database.
+type UserRepository interface {
+	Create(ctx context.Context, name, email, password string) (*User, error)
+	GetByID(ctx context.Context, id int) (*User, error)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetSessionVersion(ctx context.Context, id int) (int, error)
+	BumpSessionVersion(ctx context.Context, id int) error
+	MarkVerified(ctx context.Context, id int) error
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	GetAll(ctx context.Context) ([]*User, error)
+	GetAllPaginated(ctx context.Context, limit, offset int) ([]*User, error)
+	Authenticate(ctx context.Context, email, password string) (*User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	Delete(ctx context.Context, id int) error
+	Count(ctx context.Context) (int, error)
+	CountByRole(ctx context.Context, role string) (int, error)
+	Update(ctx context.Context, id int, name, email, role string, password *string) error
+	UpdateProfile(ctx context.Context, userID int, name, email, currentPassword string, newPassword *string) error
+}
+
+var (
+	_ BlogRepository = (*BlogModel)(nil)
+	_ UserRepository = (*UserModel)(nil)
+)
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// BlogRevision is a saved version of a blog post
+type BlogRevision struct {
+	ID        int       `json:"id"`
+	BlogID    int       `json:"blog_id"`
+	Number    int       `json:"number"` // 1 for the first version of the post
+	UserID    *int      `json:"user_id"`
+	UserName  string    `json:"user_name,omitempty"` // For displaying who saved it
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	Format    string    `json:"format"`
+	Excerpt   string    `json:"excerpt"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RevisionModel handles blog revision database operations
+type RevisionModel struct {
+	DB *sql.DB
+}
+
+// NewRevisionModel creates a new RevisionModel instance
+func NewRevisionModel(db *sql.DB) *RevisionModel {
+	return &RevisionModel{DB: db}
+}
+
+// revisionSelect is the common column list (with the saving user's name) used by revision queries
+const revisionSelect = `SELECT r.id, r.blog_id,
+			  (SELECT COUNT(*) FROM blog_revisions r2 WHERE r2.blog_id = r.blog_id AND r2.id <= r.id) AS number,
+			  r.user_id, u.name, r.title, r.content, r.format, r.excerpt, r.status, r.created_at
+			  FROM blog_revisions r
+			  LEFT JOIN users u ON r.user_id = u.id`
+
+// scanRevision scans a row selected with revisionSelect
+func scanRevision(row rowScanner) (*BlogRevision, error) {
+	revision := &BlogRevision{}
+	var userID sql.NullInt64
+	var userName, excerpt sql.NullString
+
+	err := row.Scan(
+		&revision.ID, &revision.BlogID, &revision.Number, &userID, &userName,
+		&revision.Title, &revision.Content, &revision.Format, &excerpt, &revision.Status, &revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID.Valid {
+		id := int(userID.Int64)
+		revision.UserID = &id
+	}
+	revision.UserName = userName.String
+	revision.Excerpt = excerpt.String
+
+	return revision, nil
+}
+
+// Record saves the current state of blog as a new revision by userID.
+// Nothing is recorded when the post is unchanged since its latest revision.
+// Inside a Transaction the revision is saved with the rest of the unit of work.
+func (m *RevisionModel) Record(ctx context.Context, blog *Blog, userID int) error {
+	latest, err := scanRevision(conn(ctx, m.DB).QueryRowContext(ctx, revisionSelect+` WHERE r.blog_id = ? ORDER BY r.id DESC LIMIT 1`, blog.ID))
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get latest revision: %w", err)
+	}
+	if latest != nil && latest.Title == blog.Title && latest.Content == blog.Content &&
+		latest.Format == blog.Format && latest.Excerpt == blog.Excerpt && latest.Status == blog.Status {
+		return nil
+	}
+
+	query := `INSERT INTO blog_revisions (blog_id, user_id, title, content, format, excerpt, status, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+
+	_, err = conn(ctx, m.DB).ExecContext(ctx, query, blog.ID, nullableID(&userID), blog.Title, blog.Content, blog.Format, blog.Excerpt, blog.Status)
+	if err != nil {
+		return fmt.Errorf("failed to record blog revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a revision by ID
+func (m *RevisionModel) GetByID(id int) (*BlogRevision, error) {
+	revision, err := scanRevision(m.DB.QueryRow(revisionSelect+` WHERE r.id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("revision not found")
+		}
+		return nil, fmt.Errorf("failed to get revision: %v", err)
+	}
+
+	return revision, nil
+}
+
+// GetByBlogID retrieves every revision of a blog post, newest first
+func (m *RevisionModel) GetByBlogID(blogID int) ([]*BlogRevision, error) {
+	rows, err := m.DB.Query(revisionSelect+` WHERE r.blog_id = ? ORDER BY r.id DESC`, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %v", err)
+	}
+	defer rows.Close()
+
+	var revisions []*BlogRevision
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %v", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
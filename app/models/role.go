@@ -0,0 +1,285 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Permissions checked by the application. New ones also need a row in the
+// permissions table so they can be granted from the roles screen.
+const (
+	PermBlogsCreate      = "blogs.create"
+	PermBlogsPublish     = "blogs.publish"
+	PermBlogsEditAny     = "blogs.edit_any"
+	PermBlogsDeleteAny   = "blogs.delete_any"
+	PermBlogsReview      = "blogs.review"
+	PermCommentsModerate = "comments.moderate"
+	PermTaxonomyManage   = "taxonomy.manage"
+	PermUsersManage      = "users.manage"
+	PermRolesManage      = "roles.manage"
+	PermAuditView        = "audit.view"
+)
+
+// DefaultRole is given to newly registered users
+const DefaultRole = "user"
+
+// roleNamePattern limits role names to what is safe in URLs and form values
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+// Permission is a named capability that roles can grant
+type Permission struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Role is a named set of permissions assigned to users
+type Role struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Label       string          `json:"label"`
+	Permissions map[string]bool `json:"-"`
+	UserCount   int             `json:"user_count"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Grants reports whether the role includes permission
+func (r *Role) Grants(permission string) bool {
+	return r.Permissions[permission]
+}
+
+// RoleModel handles role and permission database operations
+type RoleModel struct {
+	DB *sql.DB
+}
+
+// NewRoleModel creates a new RoleModel instance
+func NewRoleModel(db *sql.DB) *RoleModel {
+	return &RoleModel{DB: db}
+}
+
+// IsValidRoleName checks the format of a new role name
+func IsValidRoleName(name string) bool {
+	return roleNamePattern.MatchString(name)
+}
+
+// roleSelect is the common column list used by role queries
+const roleSelect = `SELECT r.id, r.name, r.label, r.created_at, r.updated_at,
+			  (SELECT COUNT(*) FROM users u WHERE u.role = r.name) AS user_count
+			  FROM roles r`
+
+// scanRole scans a row selected with roleSelect
+func scanRole(row rowScanner) (*Role, error) {
+	role := &Role{Permissions: map[string]bool{}}
+	err := row.Scan(&role.ID, &role.Name, &role.Label, &role.CreatedAt, &role.UpdatedAt, &role.UserCount)
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// GetAll retrieves every role with its permissions, oldest first
+func (m *RoleModel) GetAll() ([]*Role, error) {
+	rows, err := m.DB.Query(roleSelect + ` ORDER BY r.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %v", err)
+	}
+	defer rows.Close()
+
+	var roles []*Role
+	byID := map[int]*Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan role: %v", err)
+		}
+		roles = append(roles, role)
+		byID[role.ID] = role
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get roles: %v", err)
+	}
+
+	grants, err := m.DB.Query(`SELECT rp.role_id, p.name FROM role_permissions rp
+			  JOIN permissions p ON p.id = rp.permission_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %v", err)
+	}
+	defer grants.Close()
+
+	for grants.Next() {
+		var roleID int
+		var name string
+		if err := grants.Scan(&roleID, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %v", err)
+		}
+		if role, ok := byID[roleID]; ok {
+			role.Permissions[name] = true
+		}
+	}
+
+	return roles, grants.Err()
+}
+
+// GetByID retrieves a role and its permissions
+func (m *RoleModel) GetByID(id int) (*Role, error) {
+	role, err := scanRole(m.DB.QueryRow(roleSelect+` WHERE r.id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("role not found")
+		}
+		return nil, fmt.Errorf("failed to get role: %v", err)
+	}
+
+	role.Permissions, err = m.PermissionsFor(role.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// Exists checks whether a role with the given name exists
+func (m *RoleModel) Exists(name string) (bool, error) {
+	var count int
+	err := m.DB.QueryRow(`SELECT COUNT(*) FROM roles WHERE name = ?`, name).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check role: %v", err)
+	}
+
+	return count > 0, nil
+}
+
+// Create adds a role without any permissions
+func (m *RoleModel) Create(name, label string) (*Role, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !IsValidRoleName(name) {
+		return nil, fmt.Errorf("invalid role name")
+	}
+
+	exists, err := m.Exists(name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("role already exists")
+	}
+
+	id, err := dialect.Of(m.DB).InsertID(context.Background(), m.DB, `INSERT INTO roles (name, label, created_at, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`, name, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role: %v", err)
+	}
+
+	return m.GetByID(int(id))
+}
+
+// Delete removes a role that no user holds
+func (m *RoleModel) Delete(id int) error {
+	role, err := m.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if role.Name == DefaultRole {
+		return fmt.Errorf("cannot delete the default role")
+	}
+	if role.UserCount > 0 {
+		return fmt.Errorf("role is still assigned to users")
+	}
+
+	if _, err := m.DB.Exec(`DELETE FROM roles WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete role: %v", err)
+	}
+
+	return nil
+}
+
+// GetPermissions retrieves every known permission
+func (m *RoleModel) GetPermissions() ([]*Permission, error) {
+	rows, err := m.DB.Query(`SELECT id, name, description FROM permissions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %v", err)
+	}
+	defer rows.Close()
+
+	var permissions []*Permission
+	for rows.Next() {
+		permission := &Permission{}
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %v", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+// PermissionsFor returns the set of permissions granted to the named role
+func (m *RoleModel) PermissionsFor(roleName string) (map[string]bool, error) {
+	return rolePermissions(context.Background(), m.DB, roleName)
+}
+
+// SetPermissions replaces the permissions granted to each role, keyed by role
+// ID. Unknown permission names are ignored.
+func (m *RoleModel) SetPermissions(grants map[int][]string) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for roleID, permissions := range grants {
+		if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role_id = ?`, roleID); err != nil {
+			return fmt.Errorf("failed to clear role permissions: %v", err)
+		}
+
+		for _, name := range permissions {
+			_, err := tx.Exec(dialect.Of(m.DB).InsertIgnore(`INSERT INTO role_permissions (role_id, permission_id)
+					  SELECT r.id, p.id FROM roles r, permissions p WHERE r.id = ? AND p.name = ?`), roleID, name)
+			if err != nil {
+				return fmt.Errorf("failed to grant permission: %v", err)
+			}
+		}
+
+		if _, err := tx.Exec(`UPDATE roles SET updated_at = CURRENT_TIMESTAMP WHERE id = ?`, roleID); err != nil {
+			return fmt.Errorf("failed to update role: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save role permissions: %v", err)
+	}
+
+	return nil
+}
+
+// rolePermissions loads the permission set of a role by name
+func rolePermissions(ctx context.Context, db Conn, roleName string) (map[string]bool, error) {
+	query := `SELECT p.name FROM role_permissions rp
+			  JOIN roles r ON r.id = rp.role_id
+			  JOIN permissions p ON p.id = rp.permission_id
+			  WHERE r.name = ?`
+
+	rows, err := db.QueryContext(ctx, query, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %v", err)
+	}
+	defer rows.Close()
+
+	permissions := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %v", err)
+		}
+		permissions[name] = true
+	}
+
+	return permissions, rows.Err()
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"time"
+)
+
+// Session represents a server-side login session (a browser or device). The
+// session ID itself lives only in the user's cookie; TokenHash is its hash.
+type Session struct {
+	ID         int       `json:"id"`
+	TokenHash  string    `json:"-"`
+	UserID     *int      `json:"user_id,omitempty"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SessionModel handles session database operations
+type SessionModel struct {
+	DB *sql.DB
+}
+
+// NewSessionModel creates a new SessionModel instance
+func NewSessionModel(db *sql.DB) *SessionModel {
+	return &SessionModel{DB: db}
+}
+
+// sessionSelect is the column list shared by session queries (without the data blob)
+const sessionSelect = `SELECT id, token_hash, user_id, ip_address, user_agent, last_seen_at, expires_at, created_at FROM sessions`
+
+// scanSession scans a row selected with sessionSelect; extra receives any
+// columns selected after the standard ones
+func scanSession(row rowScanner, extra ...interface{}) (*Session, error) {
+	session := &Session{}
+	var userID sql.NullInt64
+
+	dest := []interface{}{&session.ID, &session.TokenHash, &userID, &session.IPAddress, &session.UserAgent,
+		&session.LastSeenAt, &session.ExpiresAt, &session.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if userID.Valid {
+		id := int(userID.Int64)
+		session.UserID = &id
+	}
+
+	return session, nil
+}
+
+// Load returns an unexpired session and its encoded data by token hash
+func (m *SessionModel) Load(tokenHash string) (*Session, []byte, error) {
+	query := `SELECT id, token_hash, user_id, ip_address, user_agent, last_seen_at, expires_at, created_at, data
+			  FROM sessions WHERE token_hash = ? AND expires_at > CURRENT_TIMESTAMP`
+
+	var data []byte
+	session, err := scanSession(m.DB.QueryRow(query, tokenHash), &data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("session not found")
+		}
+		return nil, nil, fmt.Errorf("failed to load session: %v", err)
+	}
+
+	return session, data, nil
+}
+
+// Save creates or updates a session's data, owner and client details and
+// extends it to expire lifetime from now. A userID of 0 means a guest session.
+func (m *SessionModel) Save(tokenHash string, userID int, data []byte, ip, userAgent string, lifetime time.Duration) error {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	var owner interface{}
+	if userID > 0 {
+		owner = userID
+	}
+
+	d := dialect.Of(m.DB)
+	query := d.Upsert(`INSERT INTO sessions (token_hash, user_id, data, ip_address, user_agent, last_seen_at, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, `+d.AddSeconds("CURRENT_TIMESTAMP")+`, CURRENT_TIMESTAMP)`,
+		"token_hash", "user_id", "data", "ip_address", "user_agent", "last_seen_at", "expires_at")
+
+	_, err := m.DB.Exec(query, tokenHash, owner, data, ip, userAgent, int(lifetime.Seconds()))
+	if err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+
+	return nil
+}
+
+// Touch records that a session was just used from the given client
+func (m *SessionModel) Touch(id int, ip, userAgent string) error {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	query := `UPDATE sessions SET last_seen_at = CURRENT_TIMESTAMP, ip_address = ?, user_agent = ? WHERE id = ?`
+	if _, err := m.DB.Exec(query, ip, userAgent, id); err != nil {
+		return fmt.Errorf("failed to touch session: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes a session by token hash
+func (m *SessionModel) Delete(tokenHash string) error {
+	if _, err := m.DB.Exec(`DELETE FROM sessions WHERE token_hash = ?`, tokenHash); err != nil {
+		return fmt.Errorf("failed to delete session: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteForUser removes one of a user's sessions; it fails when the session
+// does not exist or belongs to someone else
+func (m *SessionModel) DeleteForUser(id, userID int) error {
+	result, err := m.DB.Exec(`DELETE FROM sessions WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found")
+	}
+
+	return nil
+}
+
+// DeleteAllForUser signs a user out of every session, optionally keeping the
+// one with exceptHash, and returns how many were removed
+func (m *SessionModel) DeleteAllForUser(userID int, exceptHash string) (int64, error) {
+	result, err := m.DB.Exec(`DELETE FROM sessions WHERE user_id = ? AND token_hash <> ?`, userID, exceptHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %v", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetByUserID retrieves a user's active sessions, most recently used first
+func (m *SessionModel) GetByUserID(userID int) ([]*Session, error) {
+	rows, err := m.DB.Query(sessionSelect+` WHERE user_id = ? AND expires_at > CURRENT_TIMESTAMP ORDER BY last_seen_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %v", err)
+	}
+	defer rows.Close()
+
+	var sessions []*Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// DeleteExpired removes expired sessions and returns how many were removed
+func (m *SessionModel) DeleteExpired() (int64, error) {
+	result, err := m.DB.Exec(`DELETE FROM sessions WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %v", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength keeps slugs well inside the VARCHAR(255) column, leaving room for suffixes
+const maxSlugLength = 200
+
+// Slugify converts a title into a lowercase, hyphen-separated URL slug.
+// Accented letters are folded to ASCII and anything else is dropped.
+// Purely numeric results are prefixed so they never collide with legacy /blog/{id} URLs.
+func Slugify(title string) string {
+	var b strings.Builder
+	lastHyphen := true // suppress leading hyphens
+
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Skip combining marks left over from decomposing accented letters
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(unicode.ToLower(r))
+			lastHyphen = false
+		default:
+			if !lastHyphen {
+				b.WriteByte('-')
+				lastHyphen = true
+			}
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.Trim(slug[:maxSlugLength], "-")
+	}
+
+	if slug == "" {
+		return "post"
+	}
+	if isNumeric(slug) {
+		return "post-" + slug
+	}
+	return slug
+}
+
+// isNumeric reports whether s consists only of ASCII digits
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,269 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"strings"
+	"time"
+)
+
+// maxTagNameLength matches the tags.name column
+const maxTagNameLength = 100
+
+// Tag represents a free-form label that can be attached to many blog posts
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	BlogCount int       `json:"blog_count,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// TagModel handles tag database operations
+type TagModel struct {
+	DB *sql.DB
+}
+
+// NewTagModel creates a new TagModel instance
+func NewTagModel(db *sql.DB) *TagModel {
+	return &TagModel{DB: db}
+}
+
+// URL returns the public URL of the tag listing
+func (t *Tag) URL() string {
+	return "/tag/" + t.Slug
+}
+
+// tagSelect is the common column list used by tag queries
+const tagSelect = `SELECT t.id, t.name, t.slug, t.created_at,
+			  (SELECT COUNT(*) FROM blog_tags bt WHERE bt.tag_id = t.id) AS blog_count
+			  FROM tags t`
+
+// scanTag scans a row selected with tagSelect
+func scanTag(row rowScanner) (*Tag, error) {
+	tag := &Tag{}
+	if err := row.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt, &tag.BlogCount); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// queryTags runs a tagSelect based query and scans every row
+func (m *TagModel) queryTags(query string, args ...interface{}) ([]*Tag, error) {
+	rows, err := m.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// Create creates a new tag with a slug generated from its name
+func (m *TagModel) Create(name string) (*Tag, error) {
+	slug := Slugify(name)
+
+	exists, err := m.slugExists(slug, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("tag already exists")
+	}
+
+	id, err := dialect.Of(m.DB).InsertID(context.Background(), m.DB, `INSERT INTO tags (name, slug, created_at, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`, name, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %v", err)
+	}
+
+	return m.GetByID(int(id))
+}
+
+// GetByID retrieves a tag by ID
+func (m *TagModel) GetByID(id int) (*Tag, error) {
+	tag, err := scanTag(m.DB.QueryRow(tagSelect+` WHERE t.id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tag not found")
+		}
+		return nil, fmt.Errorf("failed to get tag: %v", err)
+	}
+
+	return tag, nil
+}
+
+// GetBySlug retrieves a tag by slug
+func (m *TagModel) GetBySlug(slug string) (*Tag, error) {
+	tag, err := scanTag(m.DB.QueryRow(tagSelect+` WHERE t.slug = ?`, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tag not found")
+		}
+		return nil, fmt.Errorf("failed to get tag: %v", err)
+	}
+
+	return tag, nil
+}
+
+// GetAll retrieves every tag ordered by name
+func (m *TagModel) GetAll() ([]*Tag, error) {
+	tags, err := m.queryTags(tagSelect + ` ORDER BY t.name ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+
+	return tags, nil
+}
+
+// GetByBlogID retrieves the tags attached to a blog post
+func (m *TagModel) GetByBlogID(blogID int) ([]*Tag, error) {
+	query := tagSelect + `
+			  INNER JOIN blog_tags bt2 ON bt2.tag_id = t.id
+			  WHERE bt2.blog_id = ?
+			  ORDER BY t.name ASC`
+
+	tags, err := m.queryTags(query, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blog tags: %v", err)
+	}
+
+	return tags, nil
+}
+
+// Update renames a tag, regenerating its slug
+func (m *TagModel) Update(id int, name string) (*Tag, error) {
+	slug := Slugify(name)
+
+	exists, err := m.slugExists(slug, id)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("tag already exists")
+	}
+
+	result, err := m.DB.Exec(`UPDATE tags SET name = ?, slug = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, name, slug, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tag: %v", err)
+	}
+
+	// MySQL reports zero affected rows when nothing changed, so confirm the tag exists
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		if _, err := m.GetByID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.GetByID(id)
+}
+
+// Delete deletes a tag and detaches it from every post
+func (m *TagModel) Delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM tags WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found")
+	}
+
+	return nil
+}
+
+// FindOrCreate returns the IDs of the named tags, creating any that do not exist yet
+func (m *TagModel) FindOrCreate(names []string) ([]int, error) {
+	var ids []int
+	for _, name := range names {
+		tag, err := m.GetBySlug(Slugify(name))
+		if err != nil {
+			if !strings.Contains(err.Error(), "not found") {
+				return nil, err
+			}
+			tag, err = m.Create(name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ids = append(ids, tag.ID)
+	}
+
+	return ids, nil
+}
+
+// SyncBlogTags replaces the tags attached to a blog post
+func (m *TagModel) SyncBlogTags(blogID int, tagIDs []int) error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM blog_tags WHERE blog_id = ?`, blogID); err != nil {
+		return fmt.Errorf("failed to clear blog tags: %v", err)
+	}
+
+	for _, tagID := range tagIDs {
+		if _, err := tx.Exec(dialect.Of(m.DB).InsertIgnore(`INSERT INTO blog_tags (blog_id, tag_id) VALUES (?, ?)`), blogID, tagID); err != nil {
+			return fmt.Errorf("failed to attach tag: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save blog tags: %v", err)
+	}
+
+	return nil
+}
+
+// slugExists checks whether slug is used by another tag
+func (m *TagModel) slugExists(slug string, excludeID int) (bool, error) {
+	var count int
+	err := m.DB.QueryRow(`SELECT COUNT(*) FROM tags WHERE slug = ? AND id != ?`, slug, excludeID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check tag: %v", err)
+	}
+	return count > 0, nil
+}
+
+// ParseTagNames splits a comma-separated list of tag names, trimming blanks
+// and dropping duplicates (compared by slug) while keeping the first spelling.
+func ParseTagNames(input string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(input, ",") {
+		name := strings.Join(strings.Fields(part), " ")
+		if name == "" {
+			continue
+		}
+		if runes := []rune(name); len(runes) > maxTagNameLength {
+			name = strings.TrimSpace(string(runes[:maxTagNameLength]))
+		}
+
+		slug := Slugify(name)
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"math/rand"
+	"time"
+)
+
+// Conn runs queries; *sql.DB and *sql.Tx both satisfy it
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txAttempts is how many times Transaction runs a unit of work that keeps
+// losing deadlocks before returning the error
+const txAttempts = 3
+
+// txKey is the context key of the transaction a unit of work runs in
+type txKey struct{}
+
+// Transaction runs fn as one unit of work. BlogModel and UserModel methods,
+// RevisionModel.Record, AuditModel.Record, ImpersonationModel.Record and
+// PasswordResetModel.Reset called with the ctx fn is given run in the same transaction, which is committed
+// when fn returns nil and rolled back when it returns an error or panics. When the database aborts
+// the transaction to break a deadlock, fn runs again from the start, so it
+// must not have side effects outside the database. Called from inside another
+// unit of work, fn joins that transaction instead of starting one.
+func Transaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	retryable := dialect.Of(db).Retryable
+	for attempt := 1; ; attempt++ {
+		err := runTransaction(ctx, db, fn)
+		if err == nil || attempt == txAttempts || !retryable(err) {
+			return err
+		}
+
+		// Wait a little longer each time, with jitter so the losers of a
+		// deadlock don't collide again
+		backoff := time.Duration(attempt*attempt)*10*time.Millisecond + time.Duration(rand.Int63n(int64(10*time.Millisecond)))
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// runTransaction makes one attempt at a unit of work
+func runTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	// Rolls back on an error or panic; a no-op once committed
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// conn returns the transaction ctx is running in, or db outside a unit of work
+func conn(ctx context.Context, db *sql.DB) Conn {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// RecoveryCodeCount is how many recovery codes are issued at a time
+const RecoveryCodeCount = 8
+
+// TwoFactorModel handles TOTP enrollment and recovery code database operations
+type TwoFactorModel struct {
+	DB *sql.DB
+}
+
+// NewTwoFactorModel creates a new TwoFactorModel instance
+func NewTwoFactorModel(db *sql.DB) *TwoFactorModel {
+	return &TwoFactorModel{DB: db}
+}
+
+// BeginEnrollment stores a new, unconfirmed secret for a user who has not
+// enabled two-factor authentication yet
+func (m *TwoFactorModel) BeginEnrollment(userID int, secret string) error {
+	query := `UPDATE users SET two_factor_secret = ?, two_factor_last_step = NULL
+			  WHERE id = ? AND two_factor_confirmed_at IS NULL`
+
+	result, err := m.DB.Exec(query, secret, userID)
+	if err != nil {
+		return fmt.Errorf("failed to start two-factor enrollment: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	return nil
+}
+
+// Confirm turns on two-factor authentication once the user has proven their
+// app works, records the code's time step and returns fresh recovery codes
+func (m *TwoFactorModel) Confirm(userID int, step int64) ([]string, error) {
+	query := `UPDATE users SET two_factor_confirmed_at = CURRENT_TIMESTAMP, two_factor_last_step = ?
+			  WHERE id = ? AND two_factor_secret IS NOT NULL AND two_factor_confirmed_at IS NULL`
+
+	result, err := m.DB.Exec(query, step, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("no two-factor enrollment in progress")
+	}
+
+	return m.RegenerateRecoveryCodes(userID)
+}
+
+// Disable turns off two-factor authentication and deletes the recovery codes
+func (m *TwoFactorModel) Disable(userID int) error {
+	query := `UPDATE users SET two_factor_secret = NULL, two_factor_confirmed_at = NULL, two_factor_last_step = NULL
+			  WHERE id = ?`
+
+	if _, err := m.DB.Exec(query, userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %v", err)
+	}
+
+	if _, err := m.DB.Exec(`DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %v", err)
+	}
+
+	return nil
+}
+
+// UseStep records a successfully validated TOTP time step. It reports false
+// when that step (or a later one) was already used, so a code that has been
+// seen cannot be replayed.
+func (m *TwoFactorModel) UseStep(userID int, step int64) (bool, error) {
+	query := `UPDATE users SET two_factor_last_step = ?
+			  WHERE id = ? AND (two_factor_last_step IS NULL OR two_factor_last_step < ?)`
+
+	result, err := m.DB.Exec(query, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record two-factor code: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes and returns the new
+// plaintext codes. Only their hashes are stored.
+func (m *TwoFactorModel) RegenerateRecoveryCodes(userID int) ([]string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %v", err)
+		}
+		code := hex.EncodeToString(buf)
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %v", err)
+	}
+
+	for _, code := range codes {
+		query := `INSERT INTO recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)`
+		if _, err := tx.Exec(query, userID, hashRecoveryCode(code)); err != nil {
+			return nil, fmt.Errorf("failed to store recovery code: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit recovery codes: %v", err)
+	}
+
+	return codes, nil
+}
+
+// UseRecoveryCode spends one of a user's unused recovery codes, reporting
+// whether the code was valid
+func (m *TwoFactorModel) UseRecoveryCode(userID int, code string) (bool, error) {
+	// The derived table lets MySQL select from the table being updated
+	query := `UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP
+			  WHERE used_at IS NULL AND id = (
+				  SELECT id FROM (
+					  SELECT id FROM recovery_codes WHERE user_id = ? AND code_hash = ? AND used_at IS NULL ORDER BY id LIMIT 1
+				  ) AS code
+			  )`
+
+	result, err := m.DB.Exec(query, userID, hashRecoveryCode(code))
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %v", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left
+func (m *TwoFactorModel) CountRecoveryCodes(userID int) (int, error) {
+	var count int
+	err := m.DB.QueryRow(`SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %v", err)
+	}
+
+	return count, nil
+}
+
+// hashRecoveryCode normalises a recovery code as typed by the user and hashes it
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) == 10 && !strings.Contains(code, "-") {
+		code = code[:5] + "-" + code[5:]
+	}
+	return HashAPIToken(code)
+}
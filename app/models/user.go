@@ -1,8 +1,10 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"go-web-app/database/dialect"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -17,11 +19,23 @@ type User struct {
 	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// SessionVersion is bumped to invalidate every existing login session
+	SessionVersion int `json:"-"`
+	// EmailVerifiedAt is when the user confirmed their email address; nil until then
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// TwoFactorSecret is the TOTP secret; it only protects logins once TwoFactorConfirmedAt is set
+	TwoFactorSecret      string     `json:"-"`
+	TwoFactorConfirmedAt *time.Time `json:"two_factor_confirmed_at"`
+	// Permissions is the set granted by the user's role, loaded with single-user lookups
+	Permissions map[string]bool `json:"-"`
 }
 
 // UserModel handles user database operations
 type UserModel struct {
 	DB *sql.DB
+	// QueryTimeout bounds each method's queries; zero waits as long as the caller's context allows
+	QueryTimeout time.Duration
 }
 
 // NewUserModel creates a new UserModel instance
@@ -30,104 +44,197 @@ func NewUserModel(db *sql.DB) *UserModel {
 }
 
 // Create creates a new user in the database
-func (m *UserModel) Create(name, email, password string) (*User, error) {
+func (m *UserModel) Create(ctx context.Context, name, email, password string) (*User, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %v", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Insert user into database (default role is 'user')
+	// Insert user into database with the default role
 	query := `INSERT INTO users (name, email, password, role, created_at, updated_at) 
-			  VALUES (?, ?, ?, 'user', NOW(), NOW())`
-
-	result, err := m.DB.Exec(query, name, email, string(hashedPassword))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %v", err)
-	}
+			  VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
 
-	// Get the inserted user ID
-	id, err := result.LastInsertId()
+	id, err := dialect.Of(m.DB).InsertID(ctx, conn(ctx, m.DB), query, name, email, string(hashedPassword), DefaultRole)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user ID: %v", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	// Return the created user
-	return m.GetByID(int(id))
+	return m.GetByID(ctx, int(id))
 }
 
-// GetByID retrieves a user by ID
-func (m *UserModel) GetByID(id int) (*User, error) {
+// userSelect is the full column list (including credentials) used by single-user queries
+const userSelect = `SELECT id, name, email, password, role, session_version, email_verified_at,
+			  two_factor_secret, two_factor_confirmed_at, created_at, updated_at
+			  FROM users`
+
+// scanUser scans a row selected with userSelect
+func scanUser(row rowScanner) (*User, error) {
 	user := &User{}
-	query := `SELECT id, name, email, password, role, created_at, updated_at 
-			  FROM users WHERE id = ?`
+	var verifiedAt, confirmedAt sql.NullTime
+	var secret sql.NullString
 
-	err := m.DB.QueryRow(query, id).Scan(
-		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role,
-		&user.CreatedAt, &user.UpdatedAt,
+	err := row.Scan(
+		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.SessionVersion, &verifiedAt,
+		&secret, &confirmedAt, &user.CreatedAt, &user.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
+	user.TwoFactorSecret = secret.String
+	if confirmedAt.Valid {
+		user.TwoFactorConfirmedAt = &confirmedAt.Time
+	}
+
+	return user, nil
+}
+
+// GetByID retrieves a user by ID
+func (m *UserModel) GetByID(ctx context.Context, id int) (*User, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
 
+	user, err := scanUser(conn(ctx, m.DB).QueryRowContext(ctx, userSelect+` WHERE id = ?`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
 		}
-		return nil, fmt.Errorf("failed to get user: %v", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.Permissions, err = rolePermissions(ctx, conn(ctx, m.DB), user.Role)
+	if err != nil {
+		return nil, err
 	}
 
 	return user, nil
 }
 
 // GetByEmail retrieves a user by email
-func (m *UserModel) GetByEmail(email string) (*User, error) {
-	user := &User{}
-	query := `SELECT id, name, email, password, role, created_at, updated_at 
-			  FROM users WHERE email = ?`
-
-	err := m.DB.QueryRow(query, email).Scan(
-		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role,
-		&user.CreatedAt, &user.UpdatedAt,
-	)
+func (m *UserModel) GetByEmail(ctx context.Context, email string) (*User, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
 
+	user, err := scanUser(conn(ctx, m.DB).QueryRowContext(ctx, userSelect+` WHERE email = ?`, email))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("user not found")
 		}
-		return nil, fmt.Errorf("failed to get user: %v", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.Permissions, err = rolePermissions(ctx, conn(ctx, m.DB), user.Role)
+	if err != nil {
+		return nil, err
 	}
 
 	return user, nil
 }
 
+// GetSessionVersion returns the user's current session version
+func (m *UserModel) GetSessionVersion(ctx context.Context, id int) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	var version int
+	err := conn(ctx, m.DB).QueryRowContext(ctx, `SELECT session_version FROM users WHERE id = ?`, id).Scan(&version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("user not found")
+		}
+		return 0, fmt.Errorf("failed to get session version: %w", err)
+	}
+
+	return version, nil
+}
+
+// BumpSessionVersion signs the user out of every existing login session
+func (m *UserModel) BumpSessionVersion(ctx context.Context, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	_, err := conn(ctx, m.DB).ExecContext(ctx, `UPDATE users SET session_version = session_version + 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to bump session version: %w", err)
+	}
+
+	return nil
+}
+
+// MarkVerified records that the user has confirmed their email address.
+// Verifying an already verified user keeps the original time.
+func (m *UserModel) MarkVerified(ctx context.Context, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	result, err := conn(ctx, m.DB).ExecContext(ctx, `UPDATE users SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP) WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to verify user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	// MySQL reports zero affected rows when nothing changed, so confirm the user exists
+	if rowsAffected == 0 {
+		if _, err := m.GetByID(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ExistsByEmail checks if a user exists with the given email
-func (m *UserModel) ExistsByEmail(email string) (bool, error) {
+func (m *UserModel) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM users WHERE email = ?`
 
-	err := m.DB.QueryRow(query, email).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, email).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("failed to check user existence: %v", err)
+		return false, fmt.Errorf("failed to check user existence: %w", err)
 	}
 
 	return count > 0, nil
 }
 
 // GetAll retrieves all users
-func (m *UserModel) GetAll() ([]*User, error) {
-	query := `SELECT id, name, email, role, created_at, updated_at 
+func (m *UserModel) GetAll(ctx context.Context) ([]*User, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT id, name, email, email_verified_at, role, created_at, updated_at 
 			  FROM users ORDER BY created_at DESC`
 
-	rows, err := m.DB.Query(query)
+	rows, err := conn(ctx, m.DB).QueryContext(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get users: %v", err)
+		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 	defer rows.Close()
 
 	var users []*User
 	for rows.Next() {
 		user := &User{}
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+		var verifiedAt sql.NullTime
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &verifiedAt, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %v", err)
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if verifiedAt.Valid {
+			user.EmailVerifiedAt = &verifiedAt.Time
 		}
 		users = append(users, user)
 	}
@@ -136,22 +243,29 @@ func (m *UserModel) GetAll() ([]*User, error) {
 }
 
 // GetAllPaginated retrieves users with pagination
-func (m *UserModel) GetAllPaginated(limit, offset int) ([]*User, error) {
-	query := `SELECT id, name, email, role, created_at, updated_at 
+func (m *UserModel) GetAllPaginated(ctx context.Context, limit, offset int) ([]*User, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT id, name, email, email_verified_at, role, created_at, updated_at 
 			  FROM users ORDER BY created_at DESC LIMIT ? OFFSET ?`
 
-	rows, err := m.DB.Query(query, limit, offset)
+	rows, err := conn(ctx, m.DB).QueryContext(ctx, query, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get users: %v", err)
+		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 	defer rows.Close()
 
 	var users []*User
 	for rows.Next() {
 		user := &User{}
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+		var verifiedAt sql.NullTime
+		err := rows.Scan(&user.ID, &user.Name, &user.Email, &verifiedAt, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %v", err)
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		if verifiedAt.Valid {
+			user.EmailVerifiedAt = &verifiedAt.Time
 		}
 		users = append(users, user)
 	}
@@ -160,8 +274,8 @@ func (m *UserModel) GetAllPaginated(limit, offset int) ([]*User, error) {
 }
 
 // Authenticate checks if the provided password matches the user's password
-func (m *UserModel) Authenticate(email, password string) (*User, error) {
-	user, err := m.GetByEmail(email)
+func (m *UserModel) Authenticate(ctx context.Context, email, password string) (*User, error) {
+	user, err := m.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
@@ -176,109 +290,134 @@ func (m *UserModel) Authenticate(email, password string) (*User, error) {
 }
 
 // EmailExists checks if an email already exists in the database
-func (m *UserModel) EmailExists(email string) (bool, error) {
+func (m *UserModel) EmailExists(ctx context.Context, email string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM users WHERE email = ?`
 
-	err := m.DB.QueryRow(query, email).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, email).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("failed to check email: %v", err)
+		return false, fmt.Errorf("failed to check email: %w", err)
 	}
 
 	return count > 0, nil
 }
 
-// IsAdmin checks if the user has admin role
-func (u *User) IsAdmin() bool {
-	return u.Role == "admin"
+// Can reports whether the user's role grants permission. Prefer the helpers in
+// app/policies when the answer also depends on who owns a resource.
+func (u *User) Can(permission string) bool {
+	return u.Permissions[permission]
+}
+
+// IsVerified checks if the user has confirmed their email address
+func (u *User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
-// IsAuthor checks if the user has author role
-func (u *User) IsAuthor() bool {
-	return u.Role == "author"
+// HasTwoFactor checks if the user has confirmed two-factor authentication
+func (u *User) HasTwoFactor() bool {
+	return u.TwoFactorConfirmedAt != nil && u.TwoFactorSecret != ""
 }
 
-// CanManageBlogs checks if user can manage blogs (admin or author)
-func (u *User) CanManageBlogs() bool {
-	return u.Role == "admin" || u.Role == "author"
+// CheckPassword reports whether password matches the user's password hash
+func (u *User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) == nil
 }
 
 // Delete deletes a user by ID (admin only operation)
-func (m *UserModel) Delete(id int) error {
-	// First, check if this is the main admin user (protect main admin)
-	var email string
-	err := m.DB.QueryRow("SELECT email FROM users WHERE id = ?", id).Scan(&email)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("user not found")
+func (m *UserModel) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
+	// The user and their posts go together or not at all. Run it inside a
+	// caller's Transaction to delete other records (e.g. audit) atomically too.
+	return Transaction(ctx, m.DB, func(ctx context.Context) error {
+		// First, check if this is the main admin user (protect main admin)
+		var email string
+		err := conn(ctx, m.DB).QueryRowContext(ctx, "SELECT email FROM users WHERE id = ?"+dialect.Of(m.DB).ForUpdate(), id).Scan(&email)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("user not found")
+			}
+			return fmt.Errorf("failed to get user email: %w", err)
 		}
-		return fmt.Errorf("failed to get user email: %v", err)
-	}
 
-	// Prevent deletion of main admin
-	if email == "admin@example.com" {
-		return fmt.Errorf("cannot delete the main administrator account")
-	}
+		// Prevent deletion of main admin
+		if email == "admin@example.com" {
+			return fmt.Errorf("cannot delete the main administrator account")
+		}
 
-	// First, delete all blogs by this user
-	_, err = m.DB.Exec("DELETE FROM blogs WHERE user_id = ?", id)
-	if err != nil {
-		return fmt.Errorf("failed to delete user blogs: %v", err)
-	}
+		// First, delete all blogs by this user
+		_, err = conn(ctx, m.DB).ExecContext(ctx, "DELETE FROM blogs WHERE user_id = ?", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete user blogs: %w", err)
+		}
 
-	// Then delete the user
-	result, err := m.DB.Exec("DELETE FROM users WHERE id = ?", id)
-	if err != nil {
-		return fmt.Errorf("failed to delete user: %v", err)
-	}
+		// Then delete the user
+		result, err := conn(ctx, m.DB).ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
 
-	// Check if any rows were affected
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %v", err)
-	}
+		// Check if any rows were affected
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
-	}
+		if rowsAffected == 0 {
+			return fmt.Errorf("user not found")
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // Count returns the total number of users
-func (m *UserModel) Count() (int, error) {
+func (m *UserModel) Count(ctx context.Context) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM users`
 
-	err := m.DB.QueryRow(query).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count users: %v", err)
+		return 0, fmt.Errorf("failed to count users: %w", err)
 	}
 
 	return count, nil
 }
 
 // CountByRole returns the number of users with a specific role
-func (m *UserModel) CountByRole(role string) (int, error) {
+func (m *UserModel) CountByRole(ctx context.Context, role string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	var count int
 	query := `SELECT COUNT(*) FROM users WHERE role = ?`
 
-	err := m.DB.QueryRow(query, role).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, role).Scan(&count)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count users by role: %v", err)
+		return 0, fmt.Errorf("failed to count users by role: %w", err)
 	}
 
 	return count, nil
 }
 
-// Update updates a user's information
-func (m *UserModel) Update(id int, name, email, role string, password *string) error {
+// Update updates a user's information. A changed email address must be verified again.
+func (m *UserModel) Update(ctx context.Context, id int, name, email, role string, password *string) error {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	// Check if email is unique (excluding current user)
 	var count int
 	query := `SELECT COUNT(*) FROM users WHERE email = ? AND id != ?`
-	err := m.DB.QueryRow(query, email, id).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, email, id).Scan(&count)
 	if err != nil {
-		return fmt.Errorf("failed to check email uniqueness: %v", err)
+		return fmt.Errorf("failed to check email uniqueness: %w", err)
 	}
 
 	if count > 0 {
@@ -290,35 +429,39 @@ func (m *UserModel) Update(id int, name, email, role string, password *string) e
 		// Hash the new password
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
 		if err != nil {
-			return fmt.Errorf("failed to hash password: %v", err)
+			return fmt.Errorf("failed to hash password: %w", err)
 		}
 
-		query = `UPDATE users SET name = ?, email = ?, role = ?, password = ?, updated_at = NOW() 
+		query = `UPDATE users SET name = ?, email_verified_at = CASE WHEN email = ? THEN email_verified_at ELSE NULL END, email = ?, role = ?, password = ?, updated_at = CURRENT_TIMESTAMP 
 				 WHERE id = ?`
-		_, err = m.DB.Exec(query, name, email, role, string(hashedPassword), id)
+		_, err = conn(ctx, m.DB).ExecContext(ctx, query, name, email, email, role, string(hashedPassword), id)
 	} else {
 		// Update without changing password
-		query = `UPDATE users SET name = ?, email = ?, role = ?, updated_at = NOW() 
+		query = `UPDATE users SET name = ?, email_verified_at = CASE WHEN email = ? THEN email_verified_at ELSE NULL END, email = ?, role = ?, updated_at = CURRENT_TIMESTAMP 
 				 WHERE id = ?`
-		_, err = m.DB.Exec(query, name, email, role, id)
+		_, err = conn(ctx, m.DB).ExecContext(ctx, query, name, email, email, role, id)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to update user: %v", err)
+		return fmt.Errorf("failed to update user: %w", err)
 	}
 
 	return nil
 }
 
-// UpdateProfile updates the current user's profile with optional password verification
-func (m *UserModel) UpdateProfile(userID int, name, email, currentPassword string, newPassword *string) error {
+// UpdateProfile updates the current user's profile with optional password verification.
+// A changed email address must be verified again.
+func (m *UserModel) UpdateProfile(ctx context.Context, userID int, name, email, currentPassword string, newPassword *string) error {
+	ctx, cancel := withQueryTimeout(ctx, m.QueryTimeout)
+	defer cancel()
+
 	// If changing password, verify current password
 	if newPassword != nil && *newPassword != "" {
 		if currentPassword == "" {
 			return fmt.Errorf("current password is required when changing password")
 		}
 
-		user, err := m.GetByID(userID)
+		user, err := m.GetByID(ctx, userID)
 		if err != nil {
 			return fmt.Errorf("user not found")
 		}
@@ -332,9 +475,9 @@ func (m *UserModel) UpdateProfile(userID int, name, email, currentPassword strin
 	// Check if email is unique (excluding current user)
 	var count int
 	query := `SELECT COUNT(*) FROM users WHERE email = ? AND id != ?`
-	err := m.DB.QueryRow(query, email, userID).Scan(&count)
+	err := conn(ctx, m.DB).QueryRowContext(ctx, query, email, userID).Scan(&count)
 	if err != nil {
-		return fmt.Errorf("failed to check email uniqueness: %v", err)
+		return fmt.Errorf("failed to check email uniqueness: %w", err)
 	}
 
 	if count > 0 {
@@ -346,21 +489,21 @@ func (m *UserModel) UpdateProfile(userID int, name, email, currentPassword strin
 		// Hash the new password
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*newPassword), bcrypt.DefaultCost)
 		if err != nil {
-			return fmt.Errorf("failed to hash password: %v", err)
+			return fmt.Errorf("failed to hash password: %w", err)
 		}
 
-		query = `UPDATE users SET name = ?, email = ?, password = ?, updated_at = NOW() 
+		query = `UPDATE users SET name = ?, email_verified_at = CASE WHEN email = ? THEN email_verified_at ELSE NULL END, email = ?, password = ?, updated_at = CURRENT_TIMESTAMP 
 				 WHERE id = ?`
-		_, err = m.DB.Exec(query, name, email, string(hashedPassword), userID)
+		_, err = conn(ctx, m.DB).ExecContext(ctx, query, name, email, email, string(hashedPassword), userID)
 	} else {
 		// Update without changing password
-		query = `UPDATE users SET name = ?, email = ?, updated_at = NOW() 
+		query = `UPDATE users SET name = ?, email_verified_at = CASE WHEN email = ? THEN email_verified_at ELSE NULL END, email = ?, updated_at = CURRENT_TIMESTAMP 
 				 WHERE id = ?`
-		_, err = m.DB.Exec(query, name, email, userID)
+		_, err = conn(ctx, m.DB).ExecContext(ctx, query, name, email, email, userID)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to update profile: %v", err)
+		return fmt.Errorf("failed to update profile: %w", err)
 	}
 
 	return nil
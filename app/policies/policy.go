@@ -0,0 +1,119 @@
+// Package policies decides what a user may do. Controllers and templates ask
+// these helpers instead of comparing role names, so changing what a role can
+// do is a matter of editing its permissions on the roles screen.
+package policies
+
+import "go-web-app/app/models"
+
+// Can reports whether user's role grants permission. A nil user can do nothing.
+func Can(user *models.User, permission string) bool {
+	return user != nil && user.Can(permission)
+}
+
+// CanCreateBlog reports whether user may write new posts
+func CanCreateBlog(user *models.User) bool {
+	return Can(user, models.PermBlogsCreate)
+}
+
+// CanPublishBlog reports whether user may publish or schedule posts rather
+// than only save drafts
+func CanPublishBlog(user *models.User) bool {
+	return Can(user, models.PermBlogsPublish)
+}
+
+// CanReviewBlogs reports whether user may see the review queue
+func CanReviewBlogs(user *models.User) bool {
+	return Can(user, models.PermBlogsReview)
+}
+
+// CanReviewBlog reports whether user may approve, edit or send back a post
+// waiting for review. Nobody reviews their own posts.
+func CanReviewBlog(user *models.User, blog *models.Blog) bool {
+	return CanReviewBlogs(user) && blog.Status == models.BlogPendingReview && user.ID != blog.UserID
+}
+
+// CanSetBlogStatus reports whether user may move a post from one status to
+// another when saving it. Saving a draft, submitting for review and keeping a
+// post that isn't live in its status are always allowed; publishing, and
+// changing a post while it stays published or scheduled, need blogs.publish.
+// Posts are only sent back to their author through a review, never by saving.
+func CanSetBlogStatus(user *models.User, from, to string) bool {
+	if to == models.BlogDraft || to == models.BlogPendingReview || (to == from && !isLive(from)) {
+		return true
+	}
+	if to == models.BlogChangesRequested {
+		return false
+	}
+	return CanPublishBlog(user)
+}
+
+// EditedBlogStatus is the status a post gets when user saves it asking for
+// status to. Someone who can't publish can't change a published or scheduled
+// post while it stays live, so their edit sends it back for review instead.
+func EditedBlogStatus(user *models.User, from, to string) string {
+	if to == from && isLive(from) && !CanPublishBlog(user) {
+		return models.BlogPendingReview
+	}
+	return to
+}
+
+// isLive reports whether status is public now or will be without a review
+func isLive(status string) bool {
+	return status == models.BlogPublished || status == models.BlogScheduled
+}
+
+// CanViewBlog reports whether user may see blog before it is published
+func CanViewBlog(user *models.User, blog *models.Blog) bool {
+	return CanEditBlog(user, blog)
+}
+
+// CanEditBlog reports whether user may edit blog: its author, anyone with
+// blogs.edit_any, and reviewers while it waits for review
+func CanEditBlog(user *models.User, blog *models.Blog) bool {
+	if user == nil {
+		return false
+	}
+	return user.ID == blog.UserID || user.Can(models.PermBlogsEditAny) || CanReviewBlog(user, blog)
+}
+
+// CanDeleteBlog reports whether user may delete a post owned by ownerID
+func CanDeleteBlog(user *models.User, ownerID int) bool {
+	if user == nil {
+		return false
+	}
+	return user.ID == ownerID || user.Can(models.PermBlogsDeleteAny)
+}
+
+// CanModerateComments reports whether user may approve, hide or delete
+// comments on a post owned by ownerID. Authors always moderate their own posts.
+func CanModerateComments(user *models.User, ownerID int) bool {
+	if user == nil {
+		return false
+	}
+	return user.ID == ownerID || user.Can(models.PermCommentsModerate)
+}
+
+// IsAdministrator reports whether user holds a permission over other people's
+// accounts. Administrators may mint admin-scoped API tokens and, when
+// REQUIRE_ADMIN_2FA is set, must use two-factor authentication.
+func IsAdministrator(user *models.User) bool {
+	return Can(user, models.PermUsersManage) || Can(user, models.PermRolesManage)
+}
+
+// IsAdminPermission reports whether permission reaches other people's
+// accounts, every post or the audit log. A request made with an API token
+// only gets such a permission when the token has the admin scope.
+func IsAdminPermission(permission string) bool {
+	switch permission {
+	case models.PermUsersManage, models.PermRolesManage, models.PermAuditView, models.PermBlogsEditAny, models.PermBlogsDeleteAny:
+		return true
+	}
+	return false
+}
+
+// CanImpersonate reports whether actor may sign in as target for support.
+// Administrators cannot be impersonated, so impersonation never grants more
+// than actor already has.
+func CanImpersonate(actor, target *models.User) bool {
+	return Can(actor, models.PermUsersManage) && target != nil && actor.ID != target.ID && !IsAdministrator(target)
+}
@@ -0,0 +1,123 @@
+package services
+
+import "strings"
+
+// Diff line kinds
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffCells bounds the size of the comparison table; when the changed
+// region is larger the whole region is shown as removed and re-added
+const maxDiffCells = 4000000
+
+// DiffLine is one line of a line-level diff
+type DiffLine struct {
+	Kind    string // DiffEqual, DiffInsert or DiffDelete
+	Text    string
+	OldLine int // Line number in the old text, 0 for inserted lines
+	NewLine int // Line number in the new text, 0 for deleted lines
+}
+
+// DiffLines compares two texts line by line and returns the lines of the new
+// text with the removed lines of the old text interleaved, in order.
+// The result is a longest-common-subsequence diff, like diff(1).
+func DiffLines(oldText, newText string) []DiffLine {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	// Unchanged lines at either end need no comparison
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, DiffLine{Kind: DiffEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix, prefix)...)
+
+	for i := suffix; i > 0; i-- {
+		oldIndex, newIndex := len(a)-i, len(b)-i
+		lines = append(lines, DiffLine{Kind: DiffEqual, Text: a[oldIndex], OldLine: oldIndex + 1, NewLine: newIndex + 1})
+	}
+
+	return lines
+}
+
+// diffMiddle diffs the changed region of two texts; oldOffset and newOffset
+// are the number of lines before the region, for line numbering
+func diffMiddle(a, b []string, oldOffset, newOffset int) []DiffLine {
+	var lines []DiffLine
+	deleteLine := func(i int) {
+		lines = append(lines, DiffLine{Kind: DiffDelete, Text: a[i], OldLine: oldOffset + i + 1})
+	}
+	insertLine := func(j int) {
+		lines = append(lines, DiffLine{Kind: DiffInsert, Text: b[j], NewLine: newOffset + j + 1})
+	}
+
+	if len(a) == 0 || len(b) == 0 || (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for i := range a {
+			deleteLine(i)
+		}
+		for j := range b {
+			insertLine(j)
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+				lcs[i*width+j] = lcs[(i+1)*width+j]
+			default:
+				lcs[i*width+j] = lcs[i*width+j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Kind: DiffEqual, Text: a[i], OldLine: oldOffset + i + 1, NewLine: newOffset + j + 1})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			deleteLine(i)
+			i++
+		default:
+			insertLine(j)
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		deleteLine(i)
+	}
+	for ; j < len(b); j++ {
+		insertLine(j)
+	}
+
+	return lines
+}
+
+// splitLines splits text into lines, accepting both \n and \r\n endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
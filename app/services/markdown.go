@@ -0,0 +1,124 @@
+// Package services contains application services shared by controllers
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"html/template"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// Content formats supported for blog posts
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+)
+
+// maxRenderCacheEntries bounds the rendered HTML cache
+const maxRenderCacheEntries = 1000
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	// sanitizer is the allowlist applied to all rendered HTML
+	sanitizer = newSanitizer()
+
+	renderCache   = make(map[string]template.HTML)
+	renderCacheMu sync.RWMutex
+)
+
+// newSanitizer builds the HTML allowlist for user-authored content
+func newSanitizer() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[a-zA-Z0-9+\-]+$`)).OnElements("code")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// IsValidFormat reports whether format is a supported content format
+func IsValidFormat(format string) bool {
+	return format == FormatPlain || format == FormatMarkdown
+}
+
+// RenderContent converts stored post content into sanitized HTML.
+// Results are cached by a hash of format and content, so each revision is rendered once.
+func RenderContent(format, content string) template.HTML {
+	key := cacheKey(format, content)
+
+	renderCacheMu.RLock()
+	cached, ok := renderCache[key]
+	renderCacheMu.RUnlock()
+	if ok {
+		return cached
+	}
+
+	rendered := RenderPreview(format, content)
+
+	renderCacheMu.Lock()
+	if len(renderCache) >= maxRenderCacheEntries {
+		// Simple bounded cache: start over rather than tracking recency
+		renderCache = make(map[string]template.HTML)
+	}
+	renderCache[key] = rendered
+	renderCacheMu.Unlock()
+
+	return rendered
+}
+
+// RenderPreview converts unsaved content into sanitized HTML like RenderContent,
+// but bypasses the cache: a draft changes with every keystroke, and caching each
+// version would keep evicting the stored posts the cache is for.
+func RenderPreview(format, content string) template.HTML {
+	return template.HTML(sanitizer.Sanitize(renderRaw(format, content)))
+}
+
+// renderRaw produces unsanitized HTML for the given format
+func renderRaw(format, content string) string {
+	if format != FormatMarkdown {
+		return renderPlain(content)
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(content), &buf); err != nil {
+		log.Printf("Markdown render error: %v", err)
+		return renderPlain(content)
+	}
+	return buf.String()
+}
+
+// renderPlain keeps the legacy behaviour: every non-empty line becomes a paragraph
+func renderPlain(content string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			b.WriteString("<br />\n")
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(html.EscapeString(line))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// cacheKey identifies a revision of content in the render cache
+func cacheKey(format, content string) string {
+	sum := sha256.Sum256([]byte(format + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultSchedulerInterval is how often the scheduler checks for due posts
+// when no interval is configured
+const DefaultSchedulerInterval = time.Minute
+
+// Publisher publishes the scheduled posts whose publish time has passed and
+// reports how many it published. models.BlogModel implements it.
+type Publisher interface {
+	PublishDue(ctx context.Context) (int, error)
+}
+
+// Scheduler runs a Publisher in a background goroutine: once on Start and
+// then every Interval until Stop is called
+type Scheduler struct {
+	Publisher Publisher
+	Interval  time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewScheduler creates a Scheduler; a non-positive interval uses DefaultSchedulerInterval
+func NewScheduler(publisher Publisher, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultSchedulerInterval
+	}
+	return &Scheduler{Publisher: publisher, Interval: interval}
+}
+
+// Start launches the background goroutine. Calling Start on a running scheduler does nothing.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.run(s.stop, s.done)
+}
+
+// Stop signals the goroutine to exit and waits for any publish in progress to
+// finish. It is safe to call more than once.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// run publishes due posts immediately and then on every tick until stop is closed
+func (s *Scheduler) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.publish()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish runs the publisher once, logging the outcome. It isn't cancelled by
+// Stop, which waits for it instead.
+func (s *Scheduler) publish() {
+	published, err := s.Publisher.PublishDue(context.Background())
+	if err != nil {
+		log.Printf("Scheduler: %v", err)
+		return
+	}
+	if published > 0 {
+		log.Printf("Scheduler: published %d scheduled post(s) at %s", published, time.Now().Format(time.RFC3339))
+	}
+}
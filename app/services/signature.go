@@ -0,0 +1,25 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Sign returns a hex-encoded HMAC-SHA256 of parts under key. Parts are joined
+// with a separator that cannot appear in them unescaped, so ("a|b", "c") and
+// ("a", "b|c") sign differently.
+func Sign(key string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	for _, part := range parts {
+		mac.Write([]byte(strings.ReplaceAll(part, "|", "||")))
+		mac.Write([]byte("|."))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidSignature reports whether signature was produced by Sign for the same key and parts
+func ValidSignature(key, signature string, parts ...string) bool {
+	return hmac.Equal([]byte(Sign(key, parts...)), []byte(signature))
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// textOnly strips every tag, keeping just the text
+var textOnly = bluemonday.StrictPolicy()
+
+// PlainText renders post content and strips all markup, collapsing whitespace
+func PlainText(format, content string) string {
+	text := html.UnescapeString(textOnly.Sanitize(renderRaw(format, content)))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Highlight returns an excerpt of text of roughly length bytes centred on the
+// first word starting with one of terms, with every such word wrapped in <mark>.
+// The text is HTML-escaped, so the result is safe to render.
+func Highlight(text string, terms []string, length int) template.HTML {
+	var matches [][]int
+	if len(terms) > 0 {
+		quoted := make([]string, len(terms))
+		for i, term := range terms {
+			quoted[i] = regexp.QuoteMeta(term)
+		}
+		// Match words that start with a term, the same way the search index does
+		pattern := regexp.MustCompile(`(?i)(?:^|[^\pL\pN])((?:` + strings.Join(quoted, "|") + `)[\pL\pN]*)`)
+		for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			matches = append(matches, m[2:4])
+		}
+	}
+
+	// Choose a window around the first match, snapped to word boundaries
+	start, end := 0, len(text)
+	if len(text) > length {
+		if len(matches) > 0 && matches[0][0] > length/3 {
+			start = matches[0][0] - length/3
+			if i := strings.IndexByte(text[start:], ' '); i >= 0 && start+i < matches[0][0] {
+				start += i + 1
+			}
+		}
+		end = start + length
+		if end >= len(text) {
+			end = len(text)
+		} else if i := strings.LastIndexByte(text[start:end], ' '); i > 0 {
+			end = start + i
+		}
+
+		// Without a space to snap to, keep multi-byte characters whole
+		for start < end && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		for end > start && end < len(text) && !utf8.RuneStart(text[end]) {
+			end--
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("&hellip; ")
+	}
+
+	pos := start
+	for _, m := range matches {
+		if m[0] < pos || m[1] > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		pos = m[1]
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+
+	if end < len(text) {
+		b.WriteString(" &hellip;")
+	}
+
+	return template.HTML(b.String())
+}
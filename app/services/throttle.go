@@ -0,0 +1,54 @@
+package services
+
+import "time"
+
+// Throttle turns a run of recent failed attempts into a wait before the next
+// attempt is allowed: a few free tries, then an exponentially growing delay,
+// then a temporary lockout
+type Throttle struct {
+	// FreeAttempts is how many failures are allowed before any delay
+	FreeAttempts int
+	// BaseDelay is the delay after the first failure beyond FreeAttempts; it doubles with each further failure
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay
+	MaxDelay time.Duration
+	// LockoutAfter is how many failures lock the key out for LockoutFor
+	LockoutAfter int
+	// LockoutFor is how long a lockout lasts; failures older than this are forgotten
+	LockoutFor time.Duration
+}
+
+// Login throttles applied to each email address and to each client IP. An IP
+// gets more room because several people may share one address.
+var (
+	LoginEmailThrottle = Throttle{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, LockoutAfter: 10, LockoutFor: 15 * time.Minute}
+	LoginIPThrottle    = Throttle{FreeAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Minute, LockoutAfter: 50, LockoutFor: 15 * time.Minute}
+)
+
+// Wait returns how much longer the caller must wait given the number of recent
+// failures and the time since the last one, and whether that wait is a lockout
+func (t Throttle) Wait(failures int, sinceLast time.Duration) (time.Duration, bool) {
+	if failures >= t.LockoutAfter {
+		if remaining := t.LockoutFor - sinceLast; remaining > 0 {
+			return remaining, true
+		}
+		return 0, false
+	}
+
+	if failures <= t.FreeAttempts {
+		return 0, false
+	}
+
+	delay := t.BaseDelay
+	for i := t.FreeAttempts + 1; i < failures && delay < t.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > t.MaxDelay {
+		delay = t.MaxDelay
+	}
+
+	if remaining := delay - sinceLast; remaining > 0 {
+		return remaining, false
+	}
+	return 0, false
+}
@@ -0,0 +1,50 @@
+package services
+
+import "strings"
+
+// userAgentBrowsers maps User-Agent markers to browser names. Order matters:
+// Edge and Opera also claim to be Chrome, and Chrome also claims to be Safari.
+var userAgentBrowsers = []struct{ marker, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+}
+
+// userAgentPlatforms maps User-Agent markers to operating systems, most specific first
+var userAgentPlatforms = []struct{ marker, name string }{
+	{"iPhone", "iPhone"},
+	{"iPad", "iPad"},
+	{"Android", "Android"},
+	{"CrOS", "ChromeOS"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// DescribeUserAgent turns a User-Agent header into a short device label such
+// as "Chrome on Windows" for session lists
+func DescribeUserAgent(userAgent string) string {
+	if strings.TrimSpace(userAgent) == "" {
+		return "Unknown device"
+	}
+
+	browser := "Unknown browser"
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.marker) {
+			browser = b.name
+			break
+		}
+	}
+
+	for _, p := range userAgentPlatforms {
+		if strings.Contains(userAgent, p.marker) {
+			return browser + " on " + p.name
+		}
+	}
+	return browser
+}
@@ -4,6 +4,7 @@ package tests
 import (
 	"go-web-app/app/controllers"
 	"go-web-app/app/models"
+	"go-web-app/bootstrap"
 	"go-web-app/config"
 	"net/http"
 	"net/http/httptest"
@@ -13,10 +14,10 @@ import (
 // TestUserAccessControl tests that only admins can access user management
 func TestUserAccessControl(t *testing.T) {
 	// Load test configuration
-	config.LoadConfig()
+	appConfig := config.LoadConfig()
 
 	// Connect to test database
-	db, err := config.ConnectDatabase(config.LoadConfig())
+	db, err := config.ConnectDatabase(appConfig)
 	if err != nil {
 		t.Skipf("Database not available: %v", err)
 		return
@@ -24,7 +25,7 @@ func TestUserAccessControl(t *testing.T) {
 	defer db.Close()
 
 	// Initialize controller
-	dashboardController := controllers.NewDashboardController()
+	dashboardController := controllers.NewDashboardController(bootstrap.NewApp(appConfig, db))
 
 	// Test case 1: Non-admin user trying to access users page
 	req, err := http.NewRequest("GET", "/dashboard/users", nil)
@@ -0,0 +1,52 @@
+package views
+
+// Layouts are the page skeletons, by the name pages refer to them with
+var Layouts = map[string]Layout{
+	// base wraps public and auth pages; they define "content"
+	"base": {File: "layouts/base.html", Name: "base", Partials: []string{"impersonation-banner"}},
+	// dashboard wraps the dashboard; its pages define "dashboard_content"
+	"dashboard": {File: "dashboard/layout.html", Name: "dashboard_layout", Partials: []string{"impersonation-banner", "verification-notice"}},
+}
+
+// Pages are the templates controllers render. Add a page here when you add
+// its template; the app won't start while a declared page fails to parse.
+var Pages = map[string]Page{
+	// Public pages
+	"home":         {Layout: "base", Partials: []string{"header", "footer", "pagination"}},
+	"search":       {Layout: "base", Partials: []string{"header", "footer", "pagination"}},
+	"blog/archive": {Layout: "base", Partials: []string{"header", "footer", "pagination"}},
+	"blog/show":    {Layout: "base", Partials: []string{"header", "comments"}},
+	"errors/csrf":  {Layout: "base"},
+
+	// Authentication
+	"auth/login":           {Layout: "base"},
+	"auth/register":        {Layout: "base"},
+	"auth/two-factor":      {Layout: "base"},
+	"auth/forgot-password": {Layout: "base"},
+	"auth/reset-password":  {Layout: "base"},
+
+	// Dashboard
+	"dashboard/index":            {Layout: "dashboard"},
+	"dashboard/profile":          {Layout: "dashboard"},
+	"dashboard/two-factor":       {Layout: "dashboard"},
+	"dashboard/tokens":           {Layout: "dashboard"},
+	"dashboard/reviews":          {Layout: "dashboard", Partials: []string{"pagination"}},
+	"dashboard/comments":         {Layout: "dashboard", Partials: []string{"pagination"}},
+	"dashboard/blogs/index":      {Layout: "dashboard", Partials: []string{"pagination"}},
+	"dashboard/blogs/admin":      {Layout: "dashboard", Partials: []string{"pagination"}},
+	"dashboard/blogs/create":     {Layout: "dashboard", Partials: []string{"editor-preview", "taxonomy-picker", "publish-schedule"}},
+	"dashboard/blogs/edit":       {Layout: "dashboard", Partials: []string{"editor-preview", "taxonomy-picker", "publish-schedule"}},
+	"dashboard/blogs/revisions":  {Layout: "dashboard"},
+	"dashboard/blogs/diff":       {Layout: "dashboard"},
+	"dashboard/categories/index": {Layout: "dashboard"},
+	"dashboard/categories/edit":  {Layout: "dashboard"},
+	"dashboard/tags/index":       {Layout: "dashboard"},
+	"dashboard/tags/edit":        {Layout: "dashboard"},
+
+	// Administration
+	"dashboard/users":          {Layout: "dashboard", Partials: []string{"pagination"}},
+	"dashboard/users/edit":     {Layout: "dashboard"},
+	"dashboard/roles":          {Layout: "dashboard"},
+	"dashboard/audit":          {Layout: "dashboard", Partials: []string{"pagination"}},
+	"dashboard/login-attempts": {Layout: "dashboard", Partials: []string{"pagination"}},
+}
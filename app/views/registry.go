@@ -0,0 +1,351 @@
+// Package views parses the HTML templates under templates/ once at startup.
+// Every page declares the layout it is rendered in and the partials it uses
+// (see Pages), so a syntax error or a missing partial stops the app at boot
+// instead of failing the first request for that page.
+package views
+
+import (
+	"fmt"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"time"
+)
+
+// Layout is a page skeleton that pages are rendered in
+type Layout struct {
+	// File is the layout's path under the templates directory
+	File string
+	// Name is the {{define}} in File that is executed to render a page
+	Name string
+	// Partials are the components the layout itself uses
+	Partials []string
+}
+
+// Page is a template a controller renders. Its name is its path under the
+// templates directory without ".html".
+type Page struct {
+	// Layout names the entry in Layouts the page is rendered in; empty
+	// renders the page file on its own
+	Layout string
+	// Partials are the components (components/<name>.html) the page uses
+	Partials []string
+}
+
+// Registry holds the parsed templates of every page. With reload set, a page
+// is parsed again when one of its files has changed since it was last parsed.
+type Registry struct {
+	dir     string
+	layouts map[string]Layout
+	pages   map[string]Page
+	reload  bool
+
+	mu     sync.RWMutex
+	parsed map[string]*parsedPage
+}
+
+// parsedPage is a page's template set. tmpl is never executed; renders use
+// copies cloned from it, which are kept and reused.
+type parsedPage struct {
+	tmpl    *template.Template
+	entry   string    // the template executed to render the page
+	files   []string  // every file in the set, relative to the templates directory
+	modTime time.Time // newest modification time of files when they were parsed
+
+	mu   sync.Mutex
+	idle []*pageCopy
+}
+
+// pageCopy is an executable clone of a page's template set. html/template
+// escapes a set the first time it runs, so copies are reused instead of being
+// cloned for every render. One render holds a copy at a time; its helpers
+// forward to the funcs that render passed.
+type pageCopy struct {
+	tmpl  *template.Template
+	funcs template.FuncMap
+}
+
+// acquire returns an idle copy of the page, cloning a new one when every copy
+// is in use by another render
+func (p *parsedPage) acquire() (*pageCopy, error) {
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return c, nil
+	}
+	p.mu.Unlock()
+
+	t, err := p.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	c := &pageCopy{tmpl: t}
+	t.Funcs(c.forwarders())
+	return c, nil
+}
+
+// release returns a copy to the page once its render has finished
+func (p *parsedPage) release(c *pageCopy) {
+	c.funcs = nil
+	p.mu.Lock()
+	p.idle = append(p.idle, c)
+	p.mu.Unlock()
+}
+
+// forwarders returns a helper for each of Funcs, with the same signature, that
+// calls the current render's replacement if it passed one
+func (c *pageCopy) forwarders() template.FuncMap {
+	forwarded := template.FuncMap{}
+	for name, fn := range Funcs() {
+		name, fallback := name, reflect.ValueOf(fn)
+		forwarded[name] = reflect.MakeFunc(fallback.Type(), func(args []reflect.Value) []reflect.Value {
+			if replacement, ok := c.funcs[name]; ok {
+				return reflect.ValueOf(replacement).Call(args)
+			}
+			return fallback.Call(args)
+		}).Interface()
+	}
+	return forwarded
+}
+
+// New parses every page from dir. It fails on the first page that has a
+// syntax error, names an unknown layout, or calls a {{template}} that none
+// of its files define.
+func New(dir string, layouts map[string]Layout, pages map[string]Page, reload bool) (*Registry, error) {
+	reg := &Registry{
+		dir:     dir,
+		layouts: layouts,
+		pages:   pages,
+		reload:  reload,
+		parsed:  make(map[string]*parsedPage, len(pages)),
+	}
+
+	for name := range pages {
+		page, err := reg.parse(name)
+		if err != nil {
+			return nil, err
+		}
+		reg.parsed[name] = page
+	}
+
+	return reg, nil
+}
+
+// Render executes the named page with data into w. funcs replaces the
+// request-specific helpers declared by Funcs for this render only.
+func (reg *Registry) Render(w io.Writer, name string, data interface{}, funcs template.FuncMap) error {
+	page, err := reg.page(name)
+	if err != nil {
+		return err
+	}
+
+	// Execute a copy no other render is using, so each request's helpers stay
+	// with that request
+	c, err := page.acquire()
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s: %w", name, err)
+	}
+	defer page.release(c)
+
+	c.funcs = funcs
+	return c.tmpl.ExecuteTemplate(w, page.entry, data)
+}
+
+// page returns the parsed page, parsing it again first if reloading is on and
+// its files have changed
+func (reg *Registry) page(name string) (*parsedPage, error) {
+	reg.mu.RLock()
+	page, ok := reg.parsed[name]
+	reg.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown page %q", name)
+	}
+
+	if !reg.reload {
+		return page, nil
+	}
+
+	modTime, err := reg.newest(page.files)
+	if err != nil {
+		return nil, err
+	}
+	if !modTime.After(page.modTime) {
+		return page, nil
+	}
+
+	page, err = reg.parse(name)
+	if err != nil {
+		return nil, err
+	}
+
+	reg.mu.Lock()
+	reg.parsed[name] = page
+	reg.mu.Unlock()
+	return page, nil
+}
+
+// parse reads the layout, page and partial files of a page into one set
+func (reg *Registry) parse(name string) (*parsedPage, error) {
+	declared, ok := reg.pages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown page %q", name)
+	}
+
+	pageFile := name + ".html"
+	page := &parsedPage{entry: pageFile}
+	partials := declared.Partials
+
+	if declared.Layout != "" {
+		layout, ok := reg.layouts[declared.Layout]
+		if !ok {
+			return nil, fmt.Errorf("page %s: unknown layout %q", name, declared.Layout)
+		}
+		page.entry = layout.Name
+		page.files = append(page.files, layout.File)
+		partials = append(append([]string{}, layout.Partials...), partials...)
+	}
+
+	page.files = append(page.files, pageFile)
+	seen := map[string]bool{}
+	for _, partial := range partials {
+		if !seen[partial] {
+			seen[partial] = true
+			page.files = append(page.files, "components/"+partial+".html")
+		}
+	}
+
+	// Read the modification times first, so a file saved while parsing is
+	// picked up by the next render
+	modTime, err := reg.newest(page.files)
+	if err != nil {
+		return nil, err
+	}
+	page.modTime = modTime
+
+	page.tmpl = template.New("").Funcs(Funcs())
+	for _, file := range page.files {
+		content, err := os.ReadFile(filepath.Join(reg.dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("page %s: %w", name, err)
+		}
+		if _, err := page.tmpl.New(file).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("page %s: %w", name, err)
+		}
+	}
+
+	if page.tmpl.Lookup(page.entry) == nil {
+		return nil, fmt.Errorf("page %s: no template named %q", name, page.entry)
+	}
+	if err := checkCalls(page.tmpl); err != nil {
+		return nil, fmt.Errorf("page %s: %w", name, err)
+	}
+
+	// Clone the first copy now, so renders start from a ready copy
+	c, err := page.acquire()
+	if err != nil {
+		return nil, fmt.Errorf("page %s: %w", name, err)
+	}
+	page.release(c)
+
+	return page, nil
+}
+
+// newest returns the latest modification time of files
+func (reg *Registry) newest(files []string) (time.Time, error) {
+	var newest time.Time
+	for _, file := range files {
+		info, err := os.Stat(filepath.Join(reg.dir, file))
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest, nil
+}
+
+// checkCalls reports a {{template}} call to a template that isn't in the set,
+// which html/template would otherwise only report when the call is executed
+func checkCalls(set *template.Template) error {
+	for _, t := range set.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		for _, called := range templateCalls(t.Tree.Root) {
+			if set.Lookup(called) == nil {
+				return fmt.Errorf("%s calls undefined template %q; declare the partial that defines it", t.Tree.ParseName, called)
+			}
+		}
+	}
+	return nil
+}
+
+// templateCalls lists the names passed to {{template}} within node
+func templateCalls(node parse.Node) []string {
+	var names []string
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			names = append(names, templateCalls(child)...)
+		}
+	case *parse.TemplateNode:
+		names = append(names, n.Name)
+	case *parse.IfNode:
+		names = append(names, templateCalls(n.List)...)
+		names = append(names, templateCalls(n.ElseList)...)
+	case *parse.RangeNode:
+		names = append(names, templateCalls(n.List)...)
+		names = append(names, templateCalls(n.ElseList)...)
+	case *parse.WithNode:
+		names = append(names, templateCalls(n.List)...)
+		names = append(names, templateCalls(n.ElseList)...)
+	}
+	return names
+}
+
+// Funcs returns the helpers every template can use. csrfToken, csrfField and
+// impersonator depend on the request, so these are placeholders that let
+// pages parse; callers pass the real ones to Render.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"split": strings.Split,
+		"slice": func(s string, start, end int) string {
+			if start >= len(s) {
+				return ""
+			}
+			if end > len(s) {
+				end = len(s)
+			}
+			return s[start:end]
+		},
+		"add": func(a, b int) int {
+			return a + b
+		},
+		"asset": func(path string) string {
+			return "/public/" + path
+		},
+		"renderContent": services.RenderContent,
+		"csrfToken": func() string {
+			return ""
+		},
+		"csrfField": func() template.HTML {
+			return ""
+		},
+		"impersonator": func() *models.User {
+			return nil
+		},
+	}
+}
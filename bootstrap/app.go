@@ -0,0 +1,139 @@
+// bootstrap/app.go - The application container (similar to Laravel's bootstrap/app.php)
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/app/views"
+	"go-web-app/config"
+	"log"
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+// App is the application container. main.go builds one at startup and passes
+// it to the routes and controllers, which take their dependencies from it
+// instead of package-level state, so two apps (or one built around fakes in
+// a test) can run side by side.
+type App struct {
+	Config       *config.Config
+	DB           *sql.DB
+	SessionStore sessions.Store
+	Mailer       services.Mailer
+	Middleware   *middleware.Middleware
+	Views        *views.Registry
+
+	// Models
+	Users          models.UserRepository
+	Blogs          models.BlogRepository
+	Searcher       models.BlogSearcher
+	APITokens      *models.APITokenModel
+	Audit          *models.AuditModel
+	BlogReviews    *models.BlogReviewModel
+	Categories     *models.CategoryModel
+	Comments       *models.CommentModel
+	Impersonations *models.ImpersonationModel
+	LoginAttempts  *models.LoginAttemptModel
+	PasswordResets *models.PasswordResetModel
+	Revisions      *models.RevisionModel
+	Roles          *models.RoleModel
+	Sessions       *models.SessionModel
+	Tags           *models.TagModel
+	TwoFactor      *models.TwoFactorModel
+}
+
+// NewApp creates the application container for cfg around an open database connection
+func NewApp(cfg *config.Config, db *sql.DB) *App {
+	timeout := queryTimeout(cfg)
+	blogs := models.NewBlogModel(db)
+	blogs.QueryTimeout = timeout
+	users := models.NewUserModel(db)
+	users.QueryTimeout = timeout
+
+	app := &App{
+		Config: cfg,
+		DB:     db,
+		Mailer: NewMailer(cfg),
+
+		Users:          users,
+		Blogs:          blogs,
+		Searcher:       models.NewBlogSearcher(blogs),
+		APITokens:      models.NewAPITokenModel(db),
+		Audit:          models.NewAuditModel(db),
+		BlogReviews:    models.NewBlogReviewModel(db),
+		Categories:     models.NewCategoryModel(db),
+		Comments:       models.NewCommentModel(db),
+		Impersonations: models.NewImpersonationModel(db),
+		LoginAttempts:  models.NewLoginAttemptModel(db),
+		PasswordResets: models.NewPasswordResetModel(db),
+		Revisions:      models.NewRevisionModel(db),
+		Roles:          models.NewRoleModel(db),
+		Sessions:       models.NewSessionModel(db),
+		Tags:           models.NewTagModel(db),
+		TwoFactor:      models.NewTwoFactorModel(db),
+	}
+
+	app.SessionStore = middleware.NewSessionStore(cfg, app.Sessions)
+	app.Middleware = middleware.New(cfg, app.SessionStore, app.Users, app.APITokens)
+	return app
+}
+
+// Transaction runs fn as one unit of work on the app's database; model calls
+// made with the ctx fn is given share the transaction (see models.Transaction)
+func (app *App) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return models.Transaction(ctx, app.DB, fn)
+}
+
+// LoadViews parses every page under dir into app.Views (see views.Pages). In
+// development pages are parsed again when their files change.
+func (app *App) LoadViews(dir string) error {
+	registry, err := views.New(dir, views.Layouts, views.Pages, app.Config.AppEnv == "development")
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	app.Views = registry
+	return nil
+}
+
+// queryTimeout parses DB_QUERY_TIMEOUT, falling back to no timeout of its own
+// (queries still end with their request) when it is unset or invalid
+func queryTimeout(cfg *config.Config) time.Duration {
+	if cfg == nil || cfg.DBQueryTimeout == "" {
+		return 0
+	}
+
+	timeout, err := time.ParseDuration(cfg.DBQueryTimeout)
+	if err != nil || timeout < 0 {
+		log.Printf("Invalid DB_QUERY_TIMEOUT %q, queries will run until their request ends", cfg.DBQueryTimeout)
+		return 0
+	}
+	return timeout
+}
+
+// NewMailer returns the mailer selected by MAIL_DRIVER
+func NewMailer(cfg *config.Config) services.Mailer {
+	if cfg == nil {
+		return &services.LogMailer{}
+	}
+
+	switch cfg.MailDriver {
+	case "smtp":
+		return &services.SMTPMailer{
+			Host:     cfg.MailHost,
+			Port:     cfg.MailPort,
+			Username: cfg.MailUsername,
+			Password: cfg.MailPassword,
+			From:     cfg.MailFrom,
+		}
+	case "log", "":
+	default:
+		log.Printf("Unknown MAIL_DRIVER %q, logging email instead", cfg.MailDriver)
+	}
+
+	return &services.LogMailer{Dir: cfg.MailLogDir, From: cfg.MailFrom}
+}
@@ -3,15 +3,17 @@ package config
 import (
 	"database/sql"
 	"fmt"
+	"go-web-app/database/dialect"
 	"log"
 	"os"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
 )
 
 // Config holds all application configuration
 type Config struct {
+	// DBDriver selects the database: "mysql", "postgres" or "sqlite" (where DBName is the database file)
+	DBDriver      string
 	DBHost        string
 	DBPort        string
 	DBName        string
@@ -21,14 +23,30 @@ type Config struct {
 	AppEnv        string
 	AppKey        string
 	SessionSecret string
+	// SessionDriver selects where sessions live: "database" (revocable, listed per device) or "cookie"
+	SessionDriver string
+	// AppURL is the public base URL (e.g. "https://blog.example.com") for absolute links in feeds and emails. It is required to send email; without it feeds use the request's Host header, which clients control
+	AppURL string
+	// TrustProxyHeaders makes the client IP come from X-Forwarded-For; only enable it behind a reverse proxy that sets the header
+	TrustProxyHeaders bool
+	// CORSAllowedOrigins is a comma-separated list of origins (e.g. "https://app.example.com") allowed to call the site cross-origin
+	CORSAllowedOrigins string
+	// SchedulerInterval is how often scheduled posts are checked, as a Go duration (e.g. "1m")
+	SchedulerInterval string
+	// DBQueryTimeout bounds each blog and user query, as a Go duration (e.g. "5s"); "0" leaves them to the request's context
+	DBQueryTimeout string
+	// MailDriver selects how email is sent: "smtp", or "log" to write messages to MailLogDir (or the log) instead
+	MailDriver   string
+	MailHost     string
+	MailPort     string
+	MailUsername string
+	MailPassword string
+	MailFrom     string
+	MailLogDir   string
+	// RequireAdmin2FA forces users with the admin role to enable two-factor authentication before using the dashboard
+	RequireAdmin2FA bool
 }
 
-// Database holds the database connection
-var Database *sql.DB
-
-// AppConfig holds the application configuration
-var AppConfig *Config
-
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
 	// Load .env file if it exists
@@ -37,6 +55,7 @@ func LoadConfig() *Config {
 	}
 
 	config := &Config{
+		DBDriver:      getEnv("DB_DRIVER", dialect.MySQL),
 		DBHost:        getEnv("DB_HOST", "localhost"),
 		DBPort:        getEnv("DB_PORT", "3306"),
 		DBName:        getEnv("DB_NAME", "go_web_app"),
@@ -45,26 +64,52 @@ func LoadConfig() *Config {
 		AppPort:       getEnv("APP_PORT", "3000"),
 		AppEnv:        getEnv("APP_ENV", "development"),
 		AppKey:        getEnv("APP_KEY", "default-key"),
+		AppURL:        getEnv("APP_URL", ""),
 		SessionSecret: getEnv("SESSION_SECRET", "default-session-secret"),
+		SessionDriver: getEnv("SESSION_DRIVER", "database"),
+
+		TrustProxyHeaders:  getEnv("TRUST_PROXY_HEADERS", "false") == "true",
+		CORSAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", ""),
+		SchedulerInterval:  getEnv("SCHEDULER_INTERVAL", "1m"),
+		DBQueryTimeout:     getEnv("DB_QUERY_TIMEOUT", "5s"),
+
+		MailDriver:   getEnv("MAIL_DRIVER", "log"),
+		MailHost:     getEnv("MAIL_HOST", "localhost"),
+		MailPort:     getEnv("MAIL_PORT", "587"),
+		MailUsername: getEnv("MAIL_USERNAME", ""),
+		MailPassword: getEnv("MAIL_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "Go Blog <no-reply@localhost>"),
+		MailLogDir:   getEnv("MAIL_LOG_DIR", ""),
+
+		RequireAdmin2FA: getEnv("REQUIRE_ADMIN_2FA", "false") == "true",
 	}
 
-	AppConfig = config
 	return config
 }
 
-// ConnectDatabase establishes a connection to the MySQL database
+// ConnectDatabase establishes a connection to the database selected by DBDriver
 func ConnectDatabase(config *Config) (*sql.DB, error) {
+	driver := config.DBDriver
+	if driver == "" {
+		driver = dialect.MySQL
+	}
+
+	d, err := dialect.ByName(driver)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create connection string
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.DBUser,
-		config.DBPassword,
-		config.DBHost,
-		config.DBPort,
-		config.DBName,
-	)
+	dsn := d.DSN(dialect.Connection{
+		Host:     config.DBHost,
+		Port:     config.DBPort,
+		Name:     config.DBName,
+		User:     config.DBUser,
+		Password: config.DBPassword,
+	})
 
 	// Open database connection
-	db, err := sql.Open("mysql", dsn)
+	db, err := sql.Open(d.DriverName(), dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %v", err)
 	}
@@ -78,8 +123,7 @@ func ConnectDatabase(config *Config) (*sql.DB, error) {
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(25)
 
-	Database = db
-	log.Printf("Connected to MySQL database: %s", config.DBName)
+	log.Printf("Connected to %s database: %s", d.Name(), config.DBName)
 	return db, nil
 }
 
@@ -90,3 +134,13 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// placeholderAppKeys are APP_KEY values that are not secret: the built-in
+// default and the example in .env.example
+var placeholderAppKeys = map[string]bool{"": true, "default-key": true, "your-secret-key-here": true}
+
+// AppKeySet reports whether APP_KEY holds a real secret. Links signed with a
+// placeholder key could be forged by anyone.
+func (c *Config) AppKeySet() bool {
+	return !placeholderAppKeys[c.AppKey]
+}
@@ -0,0 +1,115 @@
+// Package dialect hides the differences between the SQL databases the
+// application can run on. Models write portable SQL with "?" placeholders
+// and ask the dialect for the few statements that differ between backends.
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Supported DB_DRIVER values
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
+// Execer runs statements; *sql.DB and *sql.Tx both satisfy it
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Connection holds the settings needed to connect to a database. SQLite
+// only uses Name, as the path of the database file.
+type Connection struct {
+	Host     string
+	Port     string
+	Name     string
+	User     string
+	Password string
+}
+
+// Dialect is one database backend
+type Dialect interface {
+	// Name returns the DB_DRIVER value selecting this dialect
+	Name() string
+	// DriverName returns the database/sql driver to open
+	DriverName() string
+	// DSN builds the connection string for the driver
+	DSN(conn Connection) string
+	// InsertID runs an INSERT into a table with an "id" primary key and
+	// returns the ID of the new row
+	InsertID(ctx context.Context, db Execer, insert string, args ...interface{}) (int64, error)
+	// InsertIgnore rewrites "INSERT INTO ..." so that rows violating a
+	// unique key are skipped instead of failing the statement
+	InsertIgnore(insert string) string
+	// Upsert appends the clause that updates columns to the inserted values
+	// when the row already exists, as identified by the unique key column
+	Upsert(insert, key string, columns ...string) string
+	// AddSeconds returns SQL for the timestamp expression plus a number of
+	// seconds, which may be negative, bound as the next "?" argument
+	AddSeconds(timestamp string) string
+	// SecondsSince returns SQL for the whole seconds from the timestamp
+	// expression to CURRENT_TIMESTAMP
+	SecondsSince(timestamp string) string
+	// ForUpdate returns the suffix that locks selected rows until the
+	// transaction ends, or "" if the database locks on write anyway
+	ForUpdate() string
+	// Retryable reports whether err is a deadlock or lock timeout that
+	// aborted the transaction, so running it again from the start may succeed
+	Retryable(err error) bool
+}
+
+var dialects = map[string]Dialect{
+	MySQL:    mysqlDialect{},
+	Postgres: postgresDialect{},
+	SQLite:   sqliteDialect{},
+}
+
+// ByName returns the dialect for a DB_DRIVER value
+func ByName(name string) (Dialect, error) {
+	d, ok := dialects[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database driver %q (use %s, %s or %s)", name, MySQL, Postgres, SQLite)
+	}
+	return d, nil
+}
+
+// Of returns the dialect of an open database, as chosen by the driver it was
+// opened with. Unknown drivers are treated as MySQL.
+func Of(db *sql.DB) Dialect {
+	if db == nil {
+		return mysqlDialect{}
+	}
+
+	switch db.Driver() {
+	case postgresDriver:
+		return postgresDialect{}
+	case sqliteDriver:
+		return sqliteDialect{}
+	default:
+		return mysqlDialect{}
+	}
+}
+
+// insertIgnoreAs replaces the leading "INSERT INTO" of insert with prefix
+func insertIgnoreAs(insert, prefix string) string {
+	trimmed := strings.TrimSpace(insert)
+	if len(trimmed) < len("INSERT INTO") || !strings.EqualFold(trimmed[:len("INSERT INTO")], "INSERT INTO") {
+		return insert
+	}
+	return prefix + trimmed[len("INSERT INTO"):]
+}
+
+// onConflictUpdate is the standard SQL upsert clause shared by PostgreSQL and SQLite
+func onConflictUpdate(insert, key string, columns []string) string {
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = column + " = excluded." + column
+	}
+	return insert + " ON CONFLICT (" + key + ") DO UPDATE SET " + strings.Join(sets, ", ")
+}
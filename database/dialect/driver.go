@@ -0,0 +1,109 @@
+package dialect
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// wrappedDriver opens connections of another driver and adapts them so
+// models can use the same SQL on every backend: "?" placeholders are
+// rewritten for PostgreSQL, and times are stored in UTC for SQLite, which
+// compares them as text
+type wrappedDriver struct {
+	open   func(dsn string) (driver.Conn, error)
+	rebind bool
+	utc    bool
+}
+
+func (d *wrappedDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{Conn: conn, driver: d}, nil
+}
+
+// wrappedConn passes everything through to the underlying connection,
+// adapting queries and arguments on the way
+type wrappedConn struct {
+	driver.Conn
+	driver *wrappedDriver
+}
+
+func (c *wrappedConn) query(query string) string {
+	if c.driver.rebind {
+		return Rebind(query)
+	}
+	return query
+}
+
+// CheckNamedValue converts time arguments to UTC when needed and leaves the
+// rest to the default conversion
+func (c *wrappedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if t, ok := nv.Value.(time.Time); ok && c.driver.utc {
+		nv.Value = t.UTC()
+		return nil
+	}
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *wrappedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(c.query(query))
+}
+
+func (c *wrappedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, c.query(query))
+	}
+	return c.Prepare(query)
+}
+
+func (c *wrappedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, c.query(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *wrappedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, c.query(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *wrappedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	if opts.ReadOnly || opts.Isolation != 0 {
+		return nil, fmt.Errorf("transaction options are not supported")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *wrappedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *wrappedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *wrappedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
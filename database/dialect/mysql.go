@@ -0,0 +1,57 @@
+package dialect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlDialect is MySQL/MariaDB, the default backend
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string       { return MySQL }
+func (mysqlDialect) DriverName() string { return "mysql" }
+
+func (mysqlDialect) DSN(conn Connection) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		conn.User, conn.Password, conn.Host, conn.Port, conn.Name)
+}
+
+func (mysqlDialect) InsertID(ctx context.Context, db Execer, insert string, args ...interface{}) (int64, error) {
+	result, err := db.ExecContext(ctx, insert, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (mysqlDialect) InsertIgnore(insert string) string {
+	return insertIgnoreAs(insert, "INSERT IGNORE INTO")
+}
+
+func (mysqlDialect) Upsert(insert, key string, columns ...string) string {
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = column + " = VALUES(" + column + ")"
+	}
+	return insert + " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+func (mysqlDialect) AddSeconds(timestamp string) string {
+	return "DATE_ADD(" + timestamp + ", INTERVAL ? SECOND)"
+}
+
+func (mysqlDialect) SecondsSince(timestamp string) string {
+	return "TIMESTAMPDIFF(SECOND, " + timestamp + ", CURRENT_TIMESTAMP)"
+}
+
+func (mysqlDialect) ForUpdate() string { return " FOR UPDATE" }
+
+// Retryable matches ER_LOCK_DEADLOCK (1213) and ER_LOCK_WAIT_TIMEOUT (1205)
+func (mysqlDialect) Retryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && (mysqlErr.Number == 1213 || mysqlErr.Number == 1205)
+}
@@ -0,0 +1,106 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// postgresDriverName is the database/sql driver that rewrites "?"
+// placeholders to PostgreSQL's "$1, $2, ..." before handing queries to lib/pq
+const postgresDriverName = "postgres-rebind"
+
+var postgresDriver = &wrappedDriver{
+	open:   func(dsn string) (driver.Conn, error) { return pq.Open(dsn) },
+	rebind: true,
+}
+
+func init() {
+	sql.Register(postgresDriverName, postgresDriver)
+}
+
+// postgresDialect is PostgreSQL
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string       { return Postgres }
+func (postgresDialect) DriverName() string { return postgresDriverName }
+
+func (postgresDialect) DSN(conn Connection) string {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(conn.User, conn.Password),
+		Host:   conn.Host + ":" + conn.Port,
+		Path:   "/" + conn.Name,
+	}
+	if conn.Host == "localhost" || conn.Host == "127.0.0.1" {
+		dsn.RawQuery = "sslmode=disable"
+	}
+	return dsn.String()
+}
+
+// InsertID asks for the new ID with RETURNING, as lib/pq doesn't support LastInsertId
+func (postgresDialect) InsertID(ctx context.Context, db Execer, insert string, args ...interface{}) (int64, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, insert+" RETURNING id", args...).Scan(&id)
+	return id, err
+}
+
+func (postgresDialect) InsertIgnore(insert string) string {
+	return insert + " ON CONFLICT DO NOTHING"
+}
+
+func (postgresDialect) Upsert(insert, key string, columns ...string) string {
+	return onConflictUpdate(insert, key, columns)
+}
+
+func (postgresDialect) AddSeconds(timestamp string) string {
+	return "(" + timestamp + " + CAST(? AS INTEGER) * INTERVAL '1 second')"
+}
+
+func (postgresDialect) SecondsSince(timestamp string) string {
+	return "CAST(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - " + timestamp + ")) AS BIGINT)"
+}
+
+func (postgresDialect) ForUpdate() string { return " FOR UPDATE" }
+
+// Retryable matches serialization_failure (40001) and deadlock_detected (40P01)
+func (postgresDialect) Retryable(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && (pqErr.Code == "40001" || pqErr.Code == "40P01")
+}
+
+// Rebind rewrites "?" placeholders to "$1", "$2", ... leaving quoted strings
+// and identifiers untouched
+func Rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
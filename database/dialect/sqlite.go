@@ -0,0 +1,83 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteDriverName is the database/sql driver that stores times in UTC
+// before handing queries to modernc.org/sqlite
+const sqliteDriverName = "sqlite-utc"
+
+var sqliteDriver = &wrappedDriver{
+	open: (&sqlite.Driver{}).Open,
+	utc:  true,
+}
+
+func init() {
+	sql.Register(sqliteDriverName, sqliteDriver)
+}
+
+// sqliteDialect is SQLite, for single-file deployments and tests
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string       { return SQLite }
+func (sqliteDialect) DriverName() string { return sqliteDriverName }
+
+// DSN opens the file named by conn.Name with foreign keys enforced, a busy
+// timeout and write-locking transactions so concurrent writers wait instead
+// of failing, and times written in a format that sorts and compares as text
+func (sqliteDialect) DSN(conn Connection) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+	return "file:" + conn.Name + "?" + params.Encode()
+}
+
+func (sqliteDialect) InsertID(ctx context.Context, db Execer, insert string, args ...interface{}) (int64, error) {
+	result, err := db.ExecContext(ctx, insert, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (sqliteDialect) InsertIgnore(insert string) string {
+	return insertIgnoreAs(insert, "INSERT OR IGNORE INTO")
+}
+
+func (sqliteDialect) Upsert(insert, key string, columns ...string) string {
+	return onConflictUpdate(insert, key, columns)
+}
+
+// AddSeconds uses datetime(), which returns UTC text in the same format as
+// CURRENT_TIMESTAMP
+func (sqliteDialect) AddSeconds(timestamp string) string {
+	return "datetime(" + timestamp + ", ? || ' seconds')"
+}
+
+func (sqliteDialect) SecondsSince(timestamp string) string {
+	return "(CAST(strftime('%s', 'now') AS INTEGER) - CAST(strftime('%s', " + timestamp + ") AS INTEGER))"
+}
+
+// ForUpdate is empty because SQLite locks the whole database on write
+func (sqliteDialect) ForUpdate() string { return "" }
+
+// Retryable matches SQLITE_BUSY and SQLITE_LOCKED, which are returned once
+// the busy timeout runs out while another connection holds the write lock
+func (sqliteDialect) Retryable(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code() & 0xff // primary code, without the extended bits
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
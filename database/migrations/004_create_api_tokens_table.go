@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateAPITokensTable creates the api_tokens table for personal access tokens
+func CreateAPITokensTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS api_tokens (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		user_id INT NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		token_hash CHAR(64) NOT NULL UNIQUE,
+		scopes VARCHAR(255) NOT NULL DEFAULT 'read',
+		last_used_at TIMESTAMP NULL DEFAULT NULL,
+		revoked_at TIMESTAMP NULL DEFAULT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create api_tokens table: %v", err)
+	}
+
+	fmt.Println("✅ API tokens table created successfully")
+	return nil
+}
+
+// DropAPITokensTable drops the api_tokens table
+func DropAPITokensTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS api_tokens;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop api_tokens table: %v", err)
+	}
+
+	fmt.Println("❌ API tokens table dropped successfully")
+	return nil
+}
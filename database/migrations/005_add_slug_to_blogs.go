@@ -0,0 +1,149 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// AddSlugToBlogs adds a unique slug column to blogs, backfills it from titles
+// and creates the blog_slug_redirects table used for previous slugs
+func AddSlugToBlogs(db *sql.DB) error {
+	// Check if column already exists
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.columns 
+				  WHERE table_schema = DATABASE() AND table_name = 'blogs' AND column_name = 'slug'`
+	err := db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing columns: %v", err)
+	}
+
+	if count > 0 {
+		fmt.Println("⏭️  Slug column already exists, skipping")
+	} else {
+		_, err = db.Exec(`ALTER TABLE blogs ADD COLUMN slug VARCHAR(255) NULL AFTER title, ADD UNIQUE INDEX blogs_slug_unique (slug)`)
+		if err != nil {
+			return fmt.Errorf("failed to add slug column to blogs table: %v", err)
+		}
+	}
+
+	query := `
+	CREATE TABLE IF NOT EXISTS blog_slug_redirects (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		old_slug VARCHAR(255) NOT NULL UNIQUE,
+		blog_id INT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (blog_id) REFERENCES blogs(id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create blog_slug_redirects table: %v", err)
+	}
+
+	if err := backfillBlogSlugs(db); err != nil {
+		return err
+	}
+
+	fmt.Println("✅ Slug column added to blogs table")
+	return nil
+}
+
+// backfillBlogSlugs generates slugs for existing blogs that do not have one
+func backfillBlogSlugs(db *sql.DB) error {
+	rows, err := db.Query(`SELECT id, title FROM blogs WHERE slug IS NULL OR slug = '' ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to load blogs for slug backfill: %v", err)
+	}
+
+	type pending struct {
+		id    int
+		title string
+	}
+	var blogs []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.title); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan blog: %v", err)
+		}
+		blogs = append(blogs, p)
+	}
+	rows.Close()
+
+	for _, p := range blogs {
+		base := slugifyTitle(p.title)
+		slug := base
+		for i := 2; ; i++ {
+			var count int
+			err := db.QueryRow(`SELECT COUNT(*) FROM blogs WHERE slug = ? AND id != ?`, slug, p.id).Scan(&count)
+			if err != nil {
+				return fmt.Errorf("failed to check slug: %v", err)
+			}
+			if count == 0 {
+				break
+			}
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		if _, err := db.Exec(`UPDATE blogs SET slug = ? WHERE id = ?`, slug, p.id); err != nil {
+			return fmt.Errorf("failed to backfill slug for blog %d: %v", p.id, err)
+		}
+	}
+
+	return nil
+}
+
+// slugifyTitle converts a title into a slug with the rules slugs had when this
+// migration shipped, so later changes to models.Slugify don't change what it does
+func slugifyTitle(title string) string {
+	var b strings.Builder
+	lastHyphen := true // suppress leading hyphens
+
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(unicode.ToLower(r))
+			lastHyphen = false
+		default:
+			if !lastHyphen {
+				b.WriteByte('-')
+				lastHyphen = true
+			}
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > 200 {
+		slug = strings.Trim(slug[:200], "-")
+	}
+
+	if slug == "" {
+		return "post"
+	}
+	if strings.Trim(slug, "0123456789") == "" {
+		return "post-" + slug
+	}
+	return slug
+}
+
+// RemoveSlugFromBlogs drops the slug redirects table and the slug column
+func RemoveSlugFromBlogs(db *sql.DB) error {
+	if _, err := db.Exec(`DROP TABLE IF EXISTS blog_slug_redirects`); err != nil {
+		return fmt.Errorf("failed to drop blog_slug_redirects table: %v", err)
+	}
+
+	query := `ALTER TABLE blogs DROP COLUMN IF EXISTS slug`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to remove slug column from blogs table: %v", err)
+	}
+
+	fmt.Println("❌ Slug column removed from blogs table")
+	return nil
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddFormatToBlogs adds a content format column to blogs.
+// Existing posts are marked 'plain' so they keep rendering line by line.
+func AddFormatToBlogs(db *sql.DB) error {
+	// Check if column already exists
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.columns 
+				  WHERE table_schema = DATABASE() AND table_name = 'blogs' AND column_name = 'format'`
+	err := db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing columns: %v", err)
+	}
+
+	if count > 0 {
+		fmt.Println("⏭️  Format column already exists, skipping")
+		return nil
+	}
+
+	query := `ALTER TABLE blogs ADD COLUMN format ENUM('plain', 'markdown') NOT NULL DEFAULT 'plain' AFTER content`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to add format column to blogs table: %v", err)
+	}
+
+	fmt.Println("✅ Format column added to blogs table")
+	return nil
+}
+
+// RemoveFormatFromBlogs removes the format column from blogs
+func RemoveFormatFromBlogs(db *sql.DB) error {
+	query := `ALTER TABLE blogs DROP COLUMN IF EXISTS format`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to remove format column from blogs table: %v", err)
+	}
+
+	fmt.Println("❌ Format column removed from blogs table")
+	return nil
+}
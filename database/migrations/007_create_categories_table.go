@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateCategoriesTable creates the hierarchical categories table and links blogs to it
+func CreateCategoriesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS categories (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		slug VARCHAR(255) NOT NULL UNIQUE,
+		description TEXT,
+		parent_id INT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+		FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE SET NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create categories table: %v", err)
+	}
+
+	// Check if column already exists
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.columns 
+				  WHERE table_schema = DATABASE() AND table_name = 'blogs' AND column_name = 'category_id'`
+	err = db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing columns: %v", err)
+	}
+
+	if count == 0 {
+		alter := `
+		ALTER TABLE blogs
+		ADD COLUMN category_id INT NULL,
+		ADD CONSTRAINT blogs_category_id_foreign FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL`
+
+		if _, err := db.Exec(alter); err != nil {
+			return fmt.Errorf("failed to add category_id column to blogs table: %v", err)
+		}
+	}
+
+	fmt.Println("✅ Categories table created successfully")
+	return nil
+}
+
+// DropCategoriesTable unlinks blogs from categories and drops the categories table
+func DropCategoriesTable(db *sql.DB) error {
+	alter := `ALTER TABLE blogs DROP FOREIGN KEY blogs_category_id_foreign, DROP COLUMN category_id`
+	if _, err := db.Exec(alter); err != nil {
+		return fmt.Errorf("failed to remove category_id column from blogs table: %v", err)
+	}
+
+	query := `DROP TABLE IF EXISTS categories;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop categories table: %v", err)
+	}
+
+	fmt.Println("❌ Categories table dropped successfully")
+	return nil
+}
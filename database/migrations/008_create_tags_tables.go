@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateTagsTables creates the tags table and the blog_tags pivot table
+func CreateTagsTables(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS tags (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			slug VARCHAR(120) NOT NULL UNIQUE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`,
+		`CREATE TABLE IF NOT EXISTS blog_tags (
+			blog_id INT NOT NULL,
+			tag_id INT NOT NULL,
+			PRIMARY KEY (blog_id, tag_id),
+			FOREIGN KEY (blog_id) REFERENCES blogs(id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create tags tables: %v", err)
+		}
+	}
+
+	fmt.Println("✅ Tags tables created successfully")
+	return nil
+}
+
+// DropTagsTables drops the blog_tags and tags tables
+func DropTagsTables(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS blog_tags, tags;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop tags tables: %v", err)
+	}
+
+	fmt.Println("❌ Tags tables dropped successfully")
+	return nil
+}
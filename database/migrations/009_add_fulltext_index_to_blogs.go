@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddFulltextIndexToBlogs adds the FULLTEXT index used by post search
+func AddFulltextIndexToBlogs(db *sql.DB) error {
+	// Check if index already exists
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.statistics 
+				  WHERE table_schema = DATABASE() AND table_name = 'blogs' AND index_name = 'blogs_search_fulltext'`
+	err := db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing indexes: %v", err)
+	}
+
+	if count > 0 {
+		fmt.Println("⏭️  Search index already exists, skipping")
+		return nil
+	}
+
+	query := `ALTER TABLE blogs ADD FULLTEXT INDEX blogs_search_fulltext (title, excerpt, content)`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to add search index to blogs table: %v", err)
+	}
+
+	fmt.Println("✅ Search index added to blogs table")
+	return nil
+}
+
+// RemoveFulltextIndexFromBlogs drops the post search index
+func RemoveFulltextIndexFromBlogs(db *sql.DB) error {
+	query := `ALTER TABLE blogs DROP INDEX blogs_search_fulltext`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to remove search index from blogs table: %v", err)
+	}
+
+	fmt.Println("❌ Search index removed from blogs table")
+	return nil
+}
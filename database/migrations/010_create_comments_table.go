@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateCommentsTable creates the threaded comments table
+func CreateCommentsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS comments (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		blog_id INT NOT NULL,
+		user_id INT NOT NULL,
+		parent_id INT NULL,
+		body TEXT NOT NULL,
+		status ENUM('pending', 'approved', 'hidden') NOT NULL DEFAULT 'pending',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+		INDEX comments_blog_status_index (blog_id, status),
+		INDEX comments_status_index (status, created_at),
+		FOREIGN KEY (blog_id) REFERENCES blogs(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create comments table: %v", err)
+	}
+
+	fmt.Println("✅ Comments table created successfully")
+	return nil
+}
+
+// DropCommentsTable drops the comments table
+func DropCommentsTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS comments;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop comments table: %v", err)
+	}
+
+	fmt.Println("❌ Comments table dropped successfully")
+	return nil
+}
@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddSchedulingToBlogs adds the 'scheduled' status with a publish_at time, and a
+// published_at column recording when each post actually went live.
+// Existing published posts take their creation time as published_at.
+func AddSchedulingToBlogs(db *sql.DB) error {
+	// Check if columns already exist
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.columns 
+				  WHERE table_schema = DATABASE() AND table_name = 'blogs' AND column_name IN ('publish_at', 'published_at')`
+	err := db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing columns: %v", err)
+	}
+
+	if count > 0 {
+		fmt.Println("⏭️  Scheduling columns already exist, skipping")
+		return nil
+	}
+
+	query := `ALTER TABLE blogs 
+	MODIFY COLUMN status ENUM('draft', 'scheduled', 'published') DEFAULT 'draft',
+	ADD COLUMN publish_at TIMESTAMP NULL DEFAULT NULL AFTER status,
+	ADD COLUMN published_at TIMESTAMP NULL DEFAULT NULL AFTER publish_at,
+	ADD INDEX blogs_status_publish_at_index (status, publish_at)`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to add scheduling columns to blogs table: %v", err)
+	}
+
+	_, err = db.Exec(`UPDATE blogs SET published_at = created_at WHERE status = 'published'`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill published_at: %v", err)
+	}
+
+	fmt.Println("✅ Scheduling columns added to blogs table")
+	return nil
+}
+
+// RemoveSchedulingFromBlogs removes the scheduling columns from blogs.
+// Posts still waiting to be published fall back to drafts.
+func RemoveSchedulingFromBlogs(db *sql.DB) error {
+	_, err := db.Exec(`UPDATE blogs SET status = 'draft' WHERE status = 'scheduled'`)
+	if err != nil {
+		return fmt.Errorf("failed to unschedule blogs: %v", err)
+	}
+
+	query := `ALTER TABLE blogs 
+	DROP INDEX blogs_status_publish_at_index,
+	DROP COLUMN IF EXISTS publish_at,
+	DROP COLUMN IF EXISTS published_at,
+	MODIFY COLUMN status ENUM('draft', 'published') DEFAULT 'draft'`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to remove scheduling columns from blogs table: %v", err)
+	}
+
+	fmt.Println("❌ Scheduling columns removed from blogs table")
+	return nil
+}
@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateBlogRevisionsTable creates the blog_revisions table holding every saved
+// version of a post. Existing posts get their current version as the first revision.
+func CreateBlogRevisionsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS blog_revisions (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		blog_id INT NOT NULL,
+		user_id INT NULL,
+		title VARCHAR(255) NOT NULL,
+		content TEXT NOT NULL,
+		format ENUM('plain', 'markdown') NOT NULL DEFAULT 'markdown',
+		excerpt TEXT,
+		status VARCHAR(20) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX blog_revisions_blog_index (blog_id, id),
+		FOREIGN KEY (blog_id) REFERENCES blogs(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create blog_revisions table: %v", err)
+	}
+
+	// Start each existing post's history with its current version
+	backfill := `INSERT INTO blog_revisions (blog_id, user_id, title, content, format, excerpt, status, created_at)
+				 SELECT b.id, b.user_id, b.title, b.content, b.format, b.excerpt, b.status, b.updated_at
+				 FROM blogs b
+				 WHERE NOT EXISTS (SELECT 1 FROM blog_revisions r WHERE r.blog_id = b.id)`
+
+	_, err = db.Exec(backfill)
+	if err != nil {
+		return fmt.Errorf("failed to backfill blog revisions: %v", err)
+	}
+
+	fmt.Println("✅ Blog revisions table created successfully")
+	return nil
+}
+
+// DropBlogRevisionsTable drops the blog_revisions table
+func DropBlogRevisionsTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS blog_revisions;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop blog_revisions table: %v", err)
+	}
+
+	fmt.Println("❌ Blog revisions table dropped successfully")
+	return nil
+}
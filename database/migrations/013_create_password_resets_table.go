@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreatePasswordResetsTable creates the password_resets table holding hashed,
+// expiring, single-use password reset tokens
+func CreatePasswordResetsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS password_resets (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		user_id INT NOT NULL,
+		token_hash CHAR(64) NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		used_at TIMESTAMP NULL DEFAULT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE KEY password_resets_token_hash_unique (token_hash),
+		INDEX password_resets_user_index (user_id),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create password_resets table: %v", err)
+	}
+
+	fmt.Println("✅ Password resets table created successfully")
+	return nil
+}
+
+// DropPasswordResetsTable drops the password_resets table
+func DropPasswordResetsTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS password_resets;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop password_resets table: %v", err)
+	}
+
+	fmt.Println("❌ Password resets table dropped successfully")
+	return nil
+}
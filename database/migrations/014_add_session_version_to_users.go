@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddSessionVersionToUsers adds a session_version counter to users. Sessions
+// remember the version they were created with, so bumping it signs the user
+// out everywhere.
+func AddSessionVersionToUsers(db *sql.DB) error {
+	// Check if column already exists
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.columns 
+				  WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'session_version'`
+	err := db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing columns: %v", err)
+	}
+
+	if count > 0 {
+		fmt.Println("⏭️  Session version column already exists, skipping")
+		return nil
+	}
+
+	query := `ALTER TABLE users 
+	ADD COLUMN session_version INT NOT NULL DEFAULT 0 AFTER role`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to add session_version to users table: %v", err)
+	}
+
+	fmt.Println("✅ Session version column added to users table")
+	return nil
+}
+
+// RemoveSessionVersionFromUsers removes the session_version column from users
+func RemoveSessionVersionFromUsers(db *sql.DB) error {
+	query := `ALTER TABLE users 
+	DROP COLUMN IF EXISTS session_version`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to remove session_version from users table: %v", err)
+	}
+
+	fmt.Println("❌ Session version column removed from users table")
+	return nil
+}
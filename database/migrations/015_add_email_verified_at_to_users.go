@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddEmailVerifiedAtToUsers adds the email_verified_at column to users.
+// Accounts that already exist are treated as verified so nobody is locked out.
+func AddEmailVerifiedAtToUsers(db *sql.DB) error {
+	// Check if column already exists
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.columns 
+				  WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name = 'email_verified_at'`
+	err := db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing columns: %v", err)
+	}
+
+	if count > 0 {
+		fmt.Println("⏭️  Email verified column already exists, skipping")
+		return nil
+	}
+
+	query := `ALTER TABLE users 
+	ADD COLUMN email_verified_at TIMESTAMP NULL DEFAULT NULL AFTER email`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to add email_verified_at to users table: %v", err)
+	}
+
+	_, err = db.Exec(`UPDATE users SET email_verified_at = created_at`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill email_verified_at: %v", err)
+	}
+
+	fmt.Println("✅ Email verified column added to users table")
+	return nil
+}
+
+// RemoveEmailVerifiedAtFromUsers removes the email_verified_at column from users
+func RemoveEmailVerifiedAtFromUsers(db *sql.DB) error {
+	query := `ALTER TABLE users 
+	DROP COLUMN IF EXISTS email_verified_at`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to remove email_verified_at from users table: %v", err)
+	}
+
+	fmt.Println("❌ Email verified column removed from users table")
+	return nil
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddTwoFactorToUsers adds TOTP two-factor columns to users: the shared secret,
+// when enrollment was confirmed, and the last accepted time step (so a code
+// cannot be replayed)
+func AddTwoFactorToUsers(db *sql.DB) error {
+	// Check if columns already exist
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.columns 
+				  WHERE table_schema = DATABASE() AND table_name = 'users' AND column_name IN ('two_factor_secret', 'two_factor_confirmed_at', 'two_factor_last_step')`
+	err := db.QueryRow(checkQuery).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing columns: %v", err)
+	}
+
+	if count > 0 {
+		fmt.Println("⏭️  Two-factor columns already exist, skipping")
+		return nil
+	}
+
+	query := `ALTER TABLE users 
+	ADD COLUMN two_factor_secret VARCHAR(64) NULL DEFAULT NULL AFTER password,
+	ADD COLUMN two_factor_confirmed_at TIMESTAMP NULL DEFAULT NULL AFTER two_factor_secret,
+	ADD COLUMN two_factor_last_step BIGINT NULL DEFAULT NULL AFTER two_factor_confirmed_at`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to add two-factor columns to users table: %v", err)
+	}
+
+	fmt.Println("✅ Two-factor columns added to users table")
+	return nil
+}
+
+// RemoveTwoFactorFromUsers removes the two-factor columns from users
+func RemoveTwoFactorFromUsers(db *sql.DB) error {
+	query := `ALTER TABLE users 
+	DROP COLUMN IF EXISTS two_factor_secret,
+	DROP COLUMN IF EXISTS two_factor_confirmed_at,
+	DROP COLUMN IF EXISTS two_factor_last_step`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to remove two-factor columns from users table: %v", err)
+	}
+
+	fmt.Println("❌ Two-factor columns removed from users table")
+	return nil
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateRecoveryCodesTable creates the recovery_codes table holding hashed,
+// single-use two-factor recovery codes
+func CreateRecoveryCodesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS recovery_codes (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		user_id INT NOT NULL,
+		code_hash CHAR(64) NOT NULL,
+		used_at TIMESTAMP NULL DEFAULT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX recovery_codes_user_index (user_id, code_hash),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create recovery_codes table: %v", err)
+	}
+
+	fmt.Println("✅ Recovery codes table created successfully")
+	return nil
+}
+
+// DropRecoveryCodesTable drops the recovery_codes table
+func DropRecoveryCodesTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS recovery_codes;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop recovery_codes table: %v", err)
+	}
+
+	fmt.Println("❌ Recovery codes table dropped successfully")
+	return nil
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateLoginAttemptsTable creates the login_attempts table used to throttle
+// password guessing and to show admins recent sign-in activity
+func CreateLoginAttemptsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS login_attempts (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		email VARCHAR(255) NOT NULL,
+		ip_address VARCHAR(45) NOT NULL,
+		user_agent VARCHAR(255) NOT NULL DEFAULT '',
+		successful BOOLEAN NOT NULL DEFAULT FALSE,
+		cleared_at TIMESTAMP NULL DEFAULT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX login_attempts_email_index (email, created_at),
+		INDEX login_attempts_ip_index (ip_address, created_at),
+		INDEX login_attempts_created_index (created_at)
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create login_attempts table: %v", err)
+	}
+
+	fmt.Println("✅ Login attempts table created successfully")
+	return nil
+}
+
+// DropLoginAttemptsTable drops the login_attempts table
+func DropLoginAttemptsTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS login_attempts;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop login_attempts table: %v", err)
+	}
+
+	fmt.Println("❌ Login attempts table dropped successfully")
+	return nil
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateSessionsTable creates the sessions table backing the server-side
+// session store. Only a hash of each session ID is stored.
+func CreateSessionsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS sessions (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		token_hash CHAR(64) NOT NULL,
+		user_id INT NULL,
+		data MEDIUMBLOB NOT NULL,
+		ip_address VARCHAR(45) NOT NULL DEFAULT '',
+		user_agent VARCHAR(255) NOT NULL DEFAULT '',
+		last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		expires_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE KEY sessions_token_hash_unique (token_hash),
+		INDEX sessions_user_index (user_id),
+		INDEX sessions_expires_index (expires_at),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create sessions table: %v", err)
+	}
+
+	fmt.Println("✅ Sessions table created successfully")
+	return nil
+}
+
+// DropSessionsTable drops the sessions table
+func DropSessionsTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS sessions;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop sessions table: %v", err)
+	}
+
+	fmt.Println("❌ Sessions table dropped successfully")
+	return nil
+}
@@ -0,0 +1,138 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// defaultPermissions are the capabilities the application checks for, in display order
+var defaultPermissions = []struct {
+	Name        string
+	Description string
+}{
+	{"blogs.create", "Write posts and edit their own"},
+	{"blogs.publish", "Publish or schedule posts instead of only saving drafts"},
+	{"blogs.edit_any", "Browse and edit every author's posts"},
+	{"blogs.delete_any", "Delete any author's posts"},
+	{"comments.moderate", "Moderate comments on every post"},
+	{"taxonomy.manage", "Create, rename and delete categories and tags"},
+	{"users.manage", "Manage user accounts, their sessions and sign-in activity"},
+	{"roles.manage", "Edit roles and the permissions they grant"},
+}
+
+// defaultRoles replaces the old role ENUM. The grants keep what each role could
+// do before permissions existed; admins can change them from the dashboard.
+var defaultRoles = []struct {
+	Name        string
+	Label       string
+	Permissions []string
+}{
+	{"admin", "Administrator", []string{
+		"blogs.create", "blogs.publish", "blogs.edit_any", "blogs.delete_any",
+		"comments.moderate", "taxonomy.manage", "users.manage", "roles.manage",
+	}},
+	{"author", "Author", []string{"blogs.create", "blogs.publish"}},
+	{"user", "User", []string{"blogs.create", "blogs.publish"}},
+}
+
+// CreateRolesTables creates the roles, permissions and role_permissions tables,
+// seeds the built-in roles and turns users.role into a reference to roles.name
+func CreateRolesTables(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS roles (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(50) NOT NULL,
+			label VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			UNIQUE KEY roles_name_unique (name)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`,
+		`CREATE TABLE IF NOT EXISTS permissions (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			description VARCHAR(255) NOT NULL DEFAULT '',
+			UNIQUE KEY permissions_name_unique (name)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`,
+		`CREATE TABLE IF NOT EXISTS role_permissions (
+			role_id INT NOT NULL,
+			permission_id INT NOT NULL,
+			PRIMARY KEY (role_id, permission_id),
+			FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+			FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create roles tables: %v", err)
+		}
+	}
+
+	for _, permission := range defaultPermissions {
+		_, err := db.Exec(`INSERT IGNORE INTO permissions (name, description) VALUES (?, ?)`, permission.Name, permission.Description)
+		if err != nil {
+			return fmt.Errorf("failed to seed permission %s: %v", permission.Name, err)
+		}
+	}
+
+	for _, role := range defaultRoles {
+		_, err := db.Exec(`INSERT IGNORE INTO roles (name, label) VALUES (?, ?)`, role.Name, role.Label)
+		if err != nil {
+			return fmt.Errorf("failed to seed role %s: %v", role.Name, err)
+		}
+
+		for _, permission := range role.Permissions {
+			_, err := db.Exec(`INSERT IGNORE INTO role_permissions (role_id, permission_id)
+				SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = ? AND p.name = ?`, role.Name, permission)
+			if err != nil {
+				return fmt.Errorf("failed to grant %s to %s: %v", permission, role.Name, err)
+			}
+		}
+	}
+
+	// Check if users.role already references roles
+	var count int
+	checkQuery := `SELECT COUNT(*) FROM information_schema.table_constraints
+				  WHERE table_schema = DATABASE() AND table_name = 'users' AND constraint_name = 'users_role_foreign'`
+	if err := db.QueryRow(checkQuery).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check existing constraint: %v", err)
+	}
+
+	if count == 0 {
+		userQueries := []string{
+			`UPDATE users SET role = 'user' WHERE role IS NULL OR role = ''`,
+			`ALTER TABLE users MODIFY role VARCHAR(50) NOT NULL DEFAULT 'user'`,
+			`ALTER TABLE users ADD CONSTRAINT users_role_foreign
+				FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE`,
+		}
+
+		for _, query := range userQueries {
+			if _, err := db.Exec(query); err != nil {
+				return fmt.Errorf("failed to link users to roles: %v", err)
+			}
+		}
+	}
+
+	fmt.Println("✅ Roles and permissions tables created successfully")
+	return nil
+}
+
+// DropRolesTables restores the role ENUM on users and drops the roles tables.
+// Users whose role is not one of the built-in three fall back to 'user'.
+func DropRolesTables(db *sql.DB) error {
+	queries := []string{
+		`ALTER TABLE users DROP FOREIGN KEY IF EXISTS users_role_foreign`,
+		`UPDATE users SET role = 'user' WHERE role NOT IN ('admin', 'author', 'user')`,
+		`ALTER TABLE users MODIFY role ENUM('admin', 'author', 'user') DEFAULT 'user'`,
+		`DROP TABLE IF EXISTS role_permissions, permissions, roles;`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to drop roles tables: %v", err)
+		}
+	}
+
+	fmt.Println("❌ Roles and permissions tables dropped successfully")
+	return nil
+}
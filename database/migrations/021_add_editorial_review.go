@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddEditorialReview adds the 'pending_review' and 'changes_requested' statuses,
+// a blog_reviews table recording every review step, the blogs.review permission
+// and an editor role. Authors and users lose blogs.publish, so their posts go
+// through review before they are published.
+func AddEditorialReview(db *sql.DB) error {
+	queries := []string{
+		`ALTER TABLE blogs
+			MODIFY COLUMN status ENUM('draft', 'pending_review', 'changes_requested', 'scheduled', 'published') DEFAULT 'draft'`,
+		`CREATE TABLE IF NOT EXISTS blog_reviews (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			blog_id INT NOT NULL,
+			user_id INT NULL,
+			action VARCHAR(20) NOT NULL,
+			notes TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			INDEX blog_reviews_blog_index (blog_id, id),
+			FOREIGN KEY (blog_id) REFERENCES blogs(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`,
+		`INSERT IGNORE INTO permissions (name, description)
+			VALUES ('blogs.review', 'See the review queue and approve or send back submitted posts')`,
+		`INSERT IGNORE INTO roles (name, label) VALUES ('editor', 'Editor')`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add editorial review: %v", err)
+		}
+	}
+
+	grants := map[string][]string{
+		"admin":  {"blogs.review"},
+		"editor": {"blogs.create", "blogs.publish", "blogs.review"},
+	}
+	for role, permissions := range grants {
+		for _, permission := range permissions {
+			_, err := db.Exec(`INSERT IGNORE INTO role_permissions (role_id, permission_id)
+				SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = ? AND p.name = ?`, role, permission)
+			if err != nil {
+				return fmt.Errorf("failed to grant %s to %s: %v", permission, role, err)
+			}
+		}
+	}
+
+	_, err := db.Exec(`DELETE rp FROM role_permissions rp
+		JOIN roles r ON r.id = rp.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		WHERE r.name IN ('author', 'user') AND p.name = 'blogs.publish'`)
+	if err != nil {
+		return fmt.Errorf("failed to revoke blogs.publish from authors: %v", err)
+	}
+
+	fmt.Println("✅ Editorial review statuses, blog_reviews table and editor role added")
+	return nil
+}
+
+// RemoveEditorialReview undoes AddEditorialReview. Posts in review fall back to
+// drafts, editors become authors and authors may publish directly again.
+func RemoveEditorialReview(db *sql.DB) error {
+	queries := []string{
+		`UPDATE blogs SET status = 'draft' WHERE status IN ('pending_review', 'changes_requested')`,
+		`ALTER TABLE blogs MODIFY COLUMN status ENUM('draft', 'scheduled', 'published') DEFAULT 'draft'`,
+		`DROP TABLE IF EXISTS blog_reviews;`,
+		`UPDATE users SET role = 'author' WHERE role = 'editor'`,
+		`DELETE FROM roles WHERE name = 'editor'`,
+		`DELETE FROM permissions WHERE name = 'blogs.review'`,
+		`INSERT IGNORE INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r, permissions p
+			WHERE r.name IN ('author', 'user') AND p.name = 'blogs.publish'`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to remove editorial review: %v", err)
+		}
+	}
+
+	fmt.Println("❌ Editorial review statuses, blog_reviews table and editor role removed")
+	return nil
+}
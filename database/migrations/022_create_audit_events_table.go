@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// CreateAuditEventsTable creates the audit_events table recording who did what
+// to which record. The actor's name is copied so entries stay readable after
+// the account is deleted.
+func CreateAuditEventsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS audit_events (
+		id BIGINT AUTO_INCREMENT PRIMARY KEY,
+		actor_id INT NULL,
+		actor_name VARCHAR(255) NOT NULL DEFAULT '',
+		action VARCHAR(100) NOT NULL,
+		target_type VARCHAR(50) NOT NULL DEFAULT '',
+		target_id INT NULL,
+		before_data TEXT NULL,
+		after_data TEXT NULL,
+		ip_address VARCHAR(45) NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		INDEX audit_events_created_index (created_at),
+		INDEX audit_events_action_index (action, created_at),
+		INDEX audit_events_actor_index (actor_id, created_at),
+		INDEX audit_events_target_index (target_type, target_id),
+		FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to create audit_events table: %v", err)
+	}
+
+	fmt.Println("✅ Audit events table created successfully")
+	return nil
+}
+
+// DropAuditEventsTable drops the audit_events table
+func DropAuditEventsTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS audit_events;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop audit_events table: %v", err)
+	}
+
+	fmt.Println("❌ Audit events table dropped successfully")
+	return nil
+}
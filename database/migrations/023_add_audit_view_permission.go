@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// AddAuditViewPermission adds the audit.view permission for reading and
+// exporting the audit log, granted to admins
+func AddAuditViewPermission(db *sql.DB) error {
+	queries := []string{
+		`INSERT IGNORE INTO permissions (name, description)
+			VALUES ('audit.view', 'Read and export the audit log of administrative and content actions')`,
+		`INSERT IGNORE INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'admin' AND p.name = 'audit.view'`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add audit.view permission: %v", err)
+		}
+	}
+
+	fmt.Println("✅ audit.view permission added")
+	return nil
+}
+
+// RemoveAuditViewPermission removes the audit.view permission and its grants
+func RemoveAuditViewPermission(db *sql.DB) error {
+	// role_permissions rows go with it (ON DELETE CASCADE)
+	_, err := db.Exec(`DELETE FROM permissions WHERE name = 'audit.view'`)
+	if err != nil {
+		return fmt.Errorf("failed to remove audit.view permission: %v", err)
+	}
+
+	fmt.Println("❌ audit.view permission removed")
+	return nil
+}
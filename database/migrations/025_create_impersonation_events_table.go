@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"go-web-app/database/dialect"
+	"strings"
+)
+
+// CreateImpersonationEventsTable creates the impersonation_events table
+// recording when admins start and stop signing in as other users. It runs on
+// every database.
+func CreateImpersonationEventsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS impersonation_events (
+		id {{bigid}},
+		admin_id INT NULL,
+		admin_name VARCHAR(255) NOT NULL DEFAULT '',
+		user_id INT NULL,
+		user_name VARCHAR(255) NOT NULL DEFAULT '',
+		action VARCHAR(20) NOT NULL,
+		ip_address VARCHAR(45) NOT NULL DEFAULT '',
+		created_at {{timestamp}} DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (admin_id) REFERENCES users(id) ON DELETE SET NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+	)`
+
+	d := dialect.Of(db)
+	if d.Name() == dialect.MySQL {
+		query = strings.NewReplacer(
+			"{{bigid}}", "BIGINT AUTO_INCREMENT PRIMARY KEY",
+			"{{timestamp}}", "TIMESTAMP",
+		).Replace(query) + ` ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`
+	} else if types, ok := schemaTypes[d.Name()]; ok {
+		query = types.Replace(query)
+	} else {
+		return fmt.Errorf("no impersonation_events table for the %s driver", d.Name())
+	}
+
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create impersonation_events table: %v", err)
+	}
+
+	fmt.Println("✅ Impersonation events table created successfully")
+	return nil
+}
+
+// DropImpersonationEventsTable drops the impersonation_events table
+func DropImpersonationEventsTable(db *sql.DB) error {
+	query := `DROP TABLE IF EXISTS impersonation_events`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("failed to drop impersonation_events table: %v", err)
+	}
+
+	fmt.Println("❌ Impersonation events table dropped successfully")
+	return nil
+}
@@ -4,6 +4,7 @@ package migrations
 import (
 	"database/sql"
 	"fmt"
+	"go-web-app/database/dialect"
 	"log"
 )
 
@@ -13,18 +14,22 @@ type Migration struct {
 	Name     string
 	UpFunc   func(*sql.DB) error
 	DownFunc func(*sql.DB) error
+	// Dialect limits the migration to one database driver; empty runs it on every database
+	Dialect string
 }
 
 // MigrationManager manages database migrations
 type MigrationManager struct {
 	DB         *sql.DB
+	Dialect    dialect.Dialect
 	migrations []Migration
 }
 
 // NewMigrationManager creates a new migration manager
 func NewMigrationManager(db *sql.DB) *MigrationManager {
 	manager := &MigrationManager{
-		DB: db,
+		DB:      db,
+		Dialect: dialect.Of(db),
 	}
 	manager.registerMigrations()
 	return manager
@@ -38,20 +43,193 @@ func (m *MigrationManager) registerMigrations() {
 			Name:     "create_users_table",
 			UpFunc:   CreateUsersTable,
 			DownFunc: DropUsersTable,
+			Dialect:  dialect.MySQL,
 		},
 		{
 			ID:       "002",
 			Name:     "create_blogs_table",
 			UpFunc:   CreateBlogsTable,
 			DownFunc: DropBlogsTable,
+			Dialect:  dialect.MySQL,
 		},
 		{
 			ID:       "003",
 			Name:     "add_excerpt_status_to_blogs",
 			UpFunc:   AddExcerptAndStatusToBlogs,
 			DownFunc: RemoveExcerptAndStatusFromBlogs,
+			Dialect:  dialect.MySQL,
 		},
+		{
+			ID:       "004",
+			Name:     "create_api_tokens_table",
+			UpFunc:   CreateAPITokensTable,
+			DownFunc: DropAPITokensTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "005",
+			Name:     "add_slug_to_blogs",
+			UpFunc:   AddSlugToBlogs,
+			DownFunc: RemoveSlugFromBlogs,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "006",
+			Name:     "add_format_to_blogs",
+			UpFunc:   AddFormatToBlogs,
+			DownFunc: RemoveFormatFromBlogs,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "007",
+			Name:     "create_categories_table",
+			UpFunc:   CreateCategoriesTable,
+			DownFunc: DropCategoriesTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "008",
+			Name:     "create_tags_tables",
+			UpFunc:   CreateTagsTables,
+			DownFunc: DropTagsTables,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "009",
+			Name:     "add_fulltext_index_to_blogs",
+			UpFunc:   AddFulltextIndexToBlogs,
+			DownFunc: RemoveFulltextIndexFromBlogs,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "010",
+			Name:     "create_comments_table",
+			UpFunc:   CreateCommentsTable,
+			DownFunc: DropCommentsTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "011",
+			Name:     "add_scheduling_to_blogs",
+			UpFunc:   AddSchedulingToBlogs,
+			DownFunc: RemoveSchedulingFromBlogs,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "012",
+			Name:     "create_blog_revisions_table",
+			UpFunc:   CreateBlogRevisionsTable,
+			DownFunc: DropBlogRevisionsTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "013",
+			Name:     "create_password_resets_table",
+			UpFunc:   CreatePasswordResetsTable,
+			DownFunc: DropPasswordResetsTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "014",
+			Name:     "add_session_version_to_users",
+			UpFunc:   AddSessionVersionToUsers,
+			DownFunc: RemoveSessionVersionFromUsers,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "015",
+			Name:     "add_email_verified_at_to_users",
+			UpFunc:   AddEmailVerifiedAtToUsers,
+			DownFunc: RemoveEmailVerifiedAtFromUsers,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "016",
+			Name:     "add_two_factor_to_users",
+			UpFunc:   AddTwoFactorToUsers,
+			DownFunc: RemoveTwoFactorFromUsers,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "017",
+			Name:     "create_recovery_codes_table",
+			UpFunc:   CreateRecoveryCodesTable,
+			DownFunc: DropRecoveryCodesTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "018",
+			Name:     "create_login_attempts_table",
+			UpFunc:   CreateLoginAttemptsTable,
+			DownFunc: DropLoginAttemptsTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "019",
+			Name:     "create_sessions_table",
+			UpFunc:   CreateSessionsTable,
+			DownFunc: DropSessionsTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "020",
+			Name:     "create_roles_tables",
+			UpFunc:   CreateRolesTables,
+			DownFunc: DropRolesTables,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "021",
+			Name:     "add_editorial_review",
+			UpFunc:   AddEditorialReview,
+			DownFunc: RemoveEditorialReview,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "022",
+			Name:     "create_audit_events_table",
+			UpFunc:   CreateAuditEventsTable,
+			DownFunc: DropAuditEventsTable,
+			Dialect:  dialect.MySQL,
+		},
+		{
+			ID:       "023",
+			Name:     "add_audit_view_permission",
+			UpFunc:   AddAuditViewPermission,
+			DownFunc: RemoveAuditViewPermission,
+			Dialect:  dialect.MySQL,
+		},
+		// PostgreSQL and SQLite start from the schema MySQL reached with 023
+		{
+			ID:       "024",
+			Name:     "create_schema",
+			UpFunc:   CreateSchema,
+			DownFunc: DropSchema,
+			Dialect:  dialect.Postgres,
+		},
+		{
+			ID:       "024",
+			Name:     "create_schema",
+			UpFunc:   CreateSchema,
+			DownFunc: DropSchema,
+			Dialect:  dialect.SQLite,
+		},
+		{
+			ID:       "025",
+			Name:     "create_impersonation_events_table",
+			UpFunc:   CreateImpersonationEventsTable,
+			DownFunc: DropImpersonationEventsTable,
+		},
+	}
+
+	// Keep only the migrations for the database in use
+	migrations := m.migrations[:0]
+	for _, migration := range m.migrations {
+		if migration.Dialect == "" || migration.Dialect == m.Dialect.Name() {
+			migrations = append(migrations, migration)
+		}
 	}
+	m.migrations = migrations
 }
 
 // createMigrationsTable creates the migrations tracking table
@@ -61,7 +239,10 @@ func (m *MigrationManager) createMigrationsTable() error {
 		id VARCHAR(255) PRIMARY KEY,
 		name VARCHAR(255) NOT NULL,
 		executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+	)`
+	if m.Dialect.Name() == dialect.MySQL {
+		query += ` ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;`
+	}
 
 	_, err := m.DB.Exec(query)
 	if err != nil {
@@ -1,6 +1,7 @@
 package seeders
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"go-web-app/app/models"
@@ -12,8 +13,8 @@ import (
 // BlogSeeder handles blog data seeding
 type BlogSeeder struct {
 	DB        *sql.DB
-	BlogModel *models.BlogModel
-	UserModel *models.UserModel
+	BlogModel models.BlogRepository
+	UserModel models.UserRepository
 }
 
 // NewBlogSeeder creates a new blog seeder
@@ -30,7 +31,7 @@ func (s *BlogSeeder) Seed() error {
 	log.Println("🌱 Seeding blogs...")
 
 	// Get all users to assign blogs to
-	users, err := s.UserModel.GetAll()
+	users, err := s.UserModel.GetAll(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get users: %v", err)
 	}
@@ -95,7 +96,7 @@ func (s *BlogSeeder) Seed() error {
 		if len(content) > 200 {
 			excerpt = content[:200] + "..."
 		}
-		_, err = s.BlogModel.Create(title, content, excerpt, "published", user.ID)
+		_, err = s.BlogModel.Create(context.Background(), title, content, excerpt, "published", user.ID)
 		if err != nil {
 			return fmt.Errorf("failed to create blog: %v", err)
 		}
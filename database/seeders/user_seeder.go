@@ -1,6 +1,7 @@
 package seeders
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"go-web-app/app/models"
@@ -12,7 +13,7 @@ import (
 // UserSeeder handles user data seeding
 type UserSeeder struct {
 	DB        *sql.DB
-	UserModel *models.UserModel
+	UserModel models.UserRepository
 }
 
 // NewUserSeeder creates a new user seeder
@@ -37,11 +38,12 @@ func (s *UserSeeder) Seed() error {
 		{"John Author", "john@example.com", "password123", "author"},
 		{"Jane User", "jane@example.com", "password123", "user"},
 		{"Mike Writer", "mike@example.com", "password123", "author"},
+		{"Erin Editor", "erin@example.com", "password123", "editor"},
 	}
 
 	for _, userData := range users {
 		// Check if user already exists
-		exists, err := s.UserModel.ExistsByEmail(userData.Email)
+		exists, err := s.UserModel.ExistsByEmail(context.Background(), userData.Email)
 		if err != nil {
 			return fmt.Errorf("failed to check if user exists: %v", err)
 		}
@@ -59,7 +61,7 @@ func (s *UserSeeder) Seed() error {
 
 		// Create user
 		query := `INSERT INTO users (name, email, password, role, created_at, updated_at) 
-				  VALUES (?, ?, ?, ?, NOW(), NOW())`
+				  VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
 
 		_, err = s.DB.Exec(query, userData.Name, userData.Email, string(hashedPassword), userData.Role)
 		if err != nil {
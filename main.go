@@ -3,12 +3,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"go-web-app/app/middleware"
+	"go-web-app/app/services"
+	"go-web-app/app/views"
+	"go-web-app/bootstrap"
 	"go-web-app/config"
 	"go-web-app/routes"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 // main function bootstraps the application
@@ -16,27 +24,56 @@ func main() {
 	// 1. Load application configuration from .env file
 	appConfig := config.LoadConfig()
 	fmt.Printf("🚀 Starting Go Web App in %s mode\n", appConfig.AppEnv)
+	if appConfig.AppURL == "" && appConfig.MailDriver == "smtp" {
+		log.Fatal("❌ APP_URL must be set to send email: links in emails are never built from request headers")
+	}
+	if !appConfig.AppKeySet() {
+		log.Println("⚠️  APP_KEY is not set: email verification links will not be sent or accepted")
+	}
+	if appConfig.AppURL == "" && appConfig.AppEnv != "development" {
+		log.Println("⚠️  APP_URL is not set: feed links will use the Host header clients send, and no email links are sent")
+	}
 
-	// 2. Connect to MySQL database
+	// 2. Connect to the database selected by DB_DRIVER
 	db, err := config.ConnectDatabase(appConfig)
 	if err != nil {
 		log.Fatal("Failed to connect to database: ", err)
 	}
 	defer db.Close()
 
-	// 3. Initialize sessions for user authentication
-	middleware.InitSessions()
+	// 3. Build the application container (models, sessions, mailer)
+	app := bootstrap.NewApp(appConfig, db)
 	fmt.Println("✅ Sessions initialized")
 
-	// 4. Setup application routes (similar to Laravel's web.php)
-	router := routes.SetupRoutes()
+	// 4. Parse the templates; a broken template stops the app here
+	if err := app.LoadViews("templates"); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("✅ Templates loaded (%d pages)\n", len(views.Pages))
 
-	// 5. Apply global middleware
+	// 5. Start the background publisher for scheduled posts
+	interval, err := time.ParseDuration(appConfig.SchedulerInterval)
+	if err != nil {
+		log.Printf("Invalid SCHEDULER_INTERVAL %q, using %s", appConfig.SchedulerInterval, services.DefaultSchedulerInterval)
+	}
+	scheduler := services.NewScheduler(app.Blogs, interval)
+	scheduler.Start()
+	fmt.Printf("✅ Scheduler started (every %s)\n", scheduler.Interval)
+
+	// 6. Setup application routes (similar to Laravel's web.php)
+	router := routes.SetupRoutes(app)
+
+	// 7. Apply global middleware
 	handler := middleware.LoggingMiddleware(
-		middleware.CORSMiddleware(router),
+		app.Middleware.CORSMiddleware(router),
 	)
 
-	// 6. Start the HTTP server
+	// 8. Start the HTTP server
+	server := &http.Server{
+		Addr:    ":" + appConfig.AppPort,
+		Handler: handler,
+	}
+
 	fmt.Printf("🌐 Server started at http://localhost:%s\n", appConfig.AppPort)
 	fmt.Println("📝 Available routes:")
 	fmt.Println("   - GET  /                 (Homepage - Blog listing)")
@@ -48,8 +85,31 @@ func main() {
 	fmt.Println("   - GET  /dashboard/users  (User listing)")
 	fmt.Println("   - GET  /dashboard/profile (User profile)")
 
-	err = http.ListenAndServe(":"+appConfig.AppPort, handler)
-	if err != nil {
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	// 9. Shut down cleanly on Ctrl+C or SIGTERM
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErr:
+		scheduler.Stop()
 		log.Fatal("❌ Server error: ", err)
+	case <-quit:
 	}
+
+	fmt.Println("🛑 Shutting down...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("❌ Server shutdown error: %v", err)
+	}
+
+	// Stop the scheduler before the deferred db.Close runs
+	scheduler.Stop()
+	fmt.Println("👋 Server stopped")
 }
@@ -316,23 +316,26 @@ function handleSearch(event) {
         return;
     }
     
-    // In a real application, this would make an AJAX call to search endpoint
-    console.log('Searching for:', query);
-    
-    // Mock search results for demonstration
-    showSearchResults([
-        { title: 'Sample Blog Post', url: '/blog/1' },
-        { title: 'Another Post', url: '/blog/2' }
-    ]);
+    fetch('/search?q=' + encodeURIComponent(query), {
+        headers: { 'Accept': 'application/json' }
+    })
+        .then(response => response.ok ? response.json() : { data: [] })
+        .then(body => {
+            // Ignore responses for queries the user has already typed past
+            if (event.target.value.trim() === query) {
+                showSearchResults(body.data.slice(0, 5), query);
+            }
+        })
+        .catch(() => clearSearchResults());
 }
 
 // Show search results
-function showSearchResults(results) {
+function showSearchResults(results, query) {
     let resultsContainer = document.getElementById('search-results');
     if (!resultsContainer) {
         resultsContainer = document.createElement('div');
         resultsContainer.id = 'search-results';
-        resultsContainer.className = 'absolute top-full left-0 right-0 bg-white border border-gray-200 rounded-md shadow-lg z-50 mt-1';
+        resultsContainer.className = 'absolute top-full left-0 w-96 bg-white border border-gray-200 rounded-md shadow-lg z-50 mt-1';
         document.getElementById('search-input').parentNode.appendChild(resultsContainer);
     }
     
@@ -341,13 +344,23 @@ function showSearchResults(results) {
         return;
     }
     
+    // Titles are escaped here; snippets arrive already escaped and highlighted
     const resultsHTML = results.map(result => `
-        <a href="${result.url}" class="block p-3 hover:bg-gray-50 border-b border-gray-100 last:border-b-0">
-            <div class="font-medium text-gray-900">${result.title}</div>
+        <a href="${escapeHTML(result.url)}" class="block p-3 hover:bg-gray-50 border-b border-gray-100 last:border-b-0">
+            <div class="font-medium text-gray-900">${escapeHTML(result.title)}</div>
+            <div class="search-snippet text-xs text-gray-500 mt-1">${result.snippet}</div>
         </a>
     `).join('');
     
-    resultsContainer.innerHTML = resultsHTML;
+    resultsContainer.innerHTML = resultsHTML +
+        `<a href="/search?q=${encodeURIComponent(query)}" class="block p-3 text-sm text-blue-600 hover:bg-gray-50">See all results</a>`;
+}
+
+// Utility: Escape text for insertion into HTML
+function escapeHTML(text) {
+    const div = document.createElement('div');
+    div.textContent = text;
+    return div.innerHTML.replace(/"/g, '&quot;');
 }
 
 // Clear search results
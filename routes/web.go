@@ -4,61 +4,180 @@ package routes
 import (
 	"go-web-app/app/controllers"
 	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/bootstrap"
+	"net/http"
 
 	"github.com/gorilla/mux"
 )
 
 // SetupRoutes configures all application routes
-func SetupRoutes() *mux.Router {
+func SetupRoutes(app *bootstrap.App) *mux.Router {
 	// Initialize router
 	r := mux.NewRouter()
 
-	// Initialize controllers
-	authController := controllers.NewAuthController()
-	homeController := controllers.NewHomeController()
-	dashboardController := controllers.NewDashboardController()
-	blogController := controllers.NewBlogController()
+	// Middleware and controllers take their dependencies from the app container
+	mw := app.Middleware
+	authController := controllers.NewAuthController(app)
+	homeController := controllers.NewHomeController(app)
+	dashboardController := controllers.NewDashboardController(app)
+	blogController := controllers.NewBlogController(app)
+	apiController := controllers.NewAPIController(app)
+	tokenController := controllers.NewTokenController(app)
+	taxonomyController := controllers.NewTaxonomyController(app)
+	searchController := controllers.NewSearchController(app)
+	commentController := controllers.NewCommentController(app)
+	revisionController := controllers.NewRevisionController(app)
+	feedController := controllers.NewFeedController(app)
+	passwordResetController := controllers.NewPasswordResetController(app)
+	verificationController := controllers.NewVerificationController(app)
+	twoFactorController := controllers.NewTwoFactorController(app)
+	loginAttemptController := controllers.NewLoginAttemptController(app)
+	sessionController := controllers.NewSessionController(app)
+	roleController := controllers.NewRoleController(app)
+	reviewController := controllers.NewReviewController(app)
+	impersonationController := controllers.NewImpersonationController(app)
+	auditController := controllers.NewAuditController(app)
+
+	// Reject state-changing requests without a valid CSRF token
+	r.Use(mw.CSRFMiddleware(controllers.NewController(app).CSRFFailure))
 
 	// Static files serving
 	r.PathPrefix("/public/").Handler(controllers.StaticFileHandler())
 
 	// Public routes (accessible to everyone)
 	r.HandleFunc("/", homeController.Index).Methods("GET")
-	r.HandleFunc("/blog/{id}", homeController.ShowBlog).Methods("GET")
+	r.HandleFunc("/blog/{slug}", homeController.ShowBlog).Methods("GET")
+	r.HandleFunc("/category/{slug}", homeController.ShowCategory).Methods("GET")
+	r.HandleFunc("/tag/{slug}", homeController.ShowTag).Methods("GET")
+	r.HandleFunc("/search", searchController.Index).Methods("GET")
+
+	// Feed routes (site-wide, per-author and per-tag)
+	r.HandleFunc("/feed.xml", feedController.RSS).Methods("GET", "HEAD")
+	r.HandleFunc("/atom.xml", feedController.Atom).Methods("GET", "HEAD")
+	r.HandleFunc("/feed.json", feedController.JSON).Methods("GET", "HEAD")
+	r.HandleFunc("/author/{id:[0-9]+}/feed.xml", feedController.RSS).Methods("GET", "HEAD")
+	r.HandleFunc("/author/{id:[0-9]+}/atom.xml", feedController.Atom).Methods("GET", "HEAD")
+	r.HandleFunc("/author/{id:[0-9]+}/feed.json", feedController.JSON).Methods("GET", "HEAD")
+	r.HandleFunc("/tag/{slug}/feed.xml", feedController.RSS).Methods("GET", "HEAD")
+	r.HandleFunc("/tag/{slug}/atom.xml", feedController.Atom).Methods("GET", "HEAD")
+	r.HandleFunc("/tag/{slug}/feed.json", feedController.JSON).Methods("GET", "HEAD")
 
 	// Guest routes (only for non-authenticated users)
-	r.HandleFunc("/login", middleware.GuestMiddleware(authController.ShowLogin)).Methods("GET")
-	r.HandleFunc("/login", middleware.GuestMiddleware(authController.Login)).Methods("POST")
-	r.HandleFunc("/register", middleware.GuestMiddleware(authController.ShowRegister)).Methods("GET")
-	r.HandleFunc("/register", middleware.GuestMiddleware(authController.Register)).Methods("POST")
+	r.HandleFunc("/login", mw.GuestMiddleware(authController.ShowLogin)).Methods("GET")
+	r.HandleFunc("/login", mw.GuestMiddleware(authController.Login)).Methods("POST")
+	r.HandleFunc("/register", mw.GuestMiddleware(authController.ShowRegister)).Methods("GET")
+	r.HandleFunc("/register", mw.GuestMiddleware(authController.Register)).Methods("POST")
+	r.HandleFunc("/forgot-password", mw.GuestMiddleware(passwordResetController.ShowForgot)).Methods("GET")
+	r.HandleFunc("/forgot-password", mw.GuestMiddleware(passwordResetController.SendLink)).Methods("POST")
+	r.HandleFunc("/reset-password/{token}", mw.GuestMiddleware(passwordResetController.ShowReset)).Methods("GET")
+	r.HandleFunc("/reset-password", mw.GuestMiddleware(passwordResetController.Reset)).Methods("POST")
+	r.HandleFunc("/login/two-factor", mw.GuestMiddleware(authController.ShowTwoFactor)).Methods("GET")
+	r.HandleFunc("/login/two-factor", mw.GuestMiddleware(authController.VerifyTwoFactor)).Methods("POST")
 
 	// Authentication route
 	r.HandleFunc("/logout", authController.Logout).Methods("POST")
+	r.HandleFunc("/impersonation/stop", impersonationController.Stop).Methods("POST")
+
+	// Email verification link (signed, so it works without being logged in)
+	r.HandleFunc("/verify-email/{id:[0-9]+}", verificationController.Verify).Methods("GET")
+
+	// Comment routes (moderation is limited to the post's author and admins)
+	r.HandleFunc("/comments", mw.AuthMiddleware(commentController.Store)).Methods("POST")
+	r.HandleFunc("/comments/{id}/approve", mw.AuthMiddleware(commentController.Approve)).Methods("POST")
+	r.HandleFunc("/comments/{id}/hide", mw.AuthMiddleware(commentController.Hide)).Methods("POST")
+	r.HandleFunc("/comments/{id}/delete", mw.AuthMiddleware(commentController.Delete)).Methods("POST")
 
 	// Protected routes (require authentication)
 	// Dashboard routes
 	dashboard := r.PathPrefix("/dashboard").Subrouter()
-	dashboard.HandleFunc("", middleware.AuthMiddleware(dashboardController.Index)).Methods("GET")
-	dashboard.HandleFunc("/", middleware.AuthMiddleware(dashboardController.Index)).Methods("GET")
-	dashboard.HandleFunc("/profile", middleware.AuthMiddleware(dashboardController.Profile)).Methods("GET")
-	dashboard.HandleFunc("/profile", middleware.AuthMiddleware(dashboardController.UpdateProfile)).Methods("POST")
-	dashboard.HandleFunc("/profile/change-password", middleware.AuthMiddleware(dashboardController.ChangePassword)).Methods("POST")
-	dashboard.HandleFunc("/users", middleware.AuthMiddleware(dashboardController.Users)).Methods("GET")
-	dashboard.HandleFunc("/users/{id}/edit", middleware.AuthMiddleware(dashboardController.EditUser)).Methods("GET")
-	dashboard.HandleFunc("/users/{id}", middleware.AuthMiddleware(dashboardController.UpdateUser)).Methods("POST")
-	dashboard.HandleFunc("/users/{id}/delete", middleware.AuthMiddleware(dashboardController.DeleteUser)).Methods("POST")
+	dashboard.HandleFunc("", mw.AuthMiddleware(dashboardController.Index)).Methods("GET")
+	dashboard.HandleFunc("/", mw.AuthMiddleware(dashboardController.Index)).Methods("GET")
+	dashboard.HandleFunc("/profile", mw.AuthMiddleware(dashboardController.Profile)).Methods("GET")
+	dashboard.HandleFunc("/profile", mw.AuthMiddleware(mw.NotWhileImpersonating(dashboardController.UpdateProfile))).Methods("POST")
+	dashboard.HandleFunc("/profile/change-password", mw.AuthMiddleware(mw.NotWhileImpersonating(dashboardController.ChangePassword))).Methods("POST")
+	dashboard.HandleFunc("/email/resend", mw.AuthMiddleware(verificationController.Resend)).Methods("POST")
+	dashboard.HandleFunc("/profile/sessions/logout-all", mw.AuthMiddleware(mw.NotWhileImpersonating(sessionController.DestroyAll))).Methods("POST")
+	dashboard.HandleFunc("/profile/sessions/{id:[0-9]+}/logout", mw.AuthMiddleware(mw.NotWhileImpersonating(sessionController.Destroy))).Methods("POST")
+	dashboard.HandleFunc("/profile/tokens", mw.AuthMiddleware(tokenController.Index)).Methods("GET")
+	dashboard.HandleFunc("/profile/tokens", mw.AuthMiddleware(mw.NotWhileImpersonating(tokenController.Store))).Methods("POST")
+	dashboard.HandleFunc("/profile/tokens/{id}/revoke", mw.AuthMiddleware(mw.NotWhileImpersonating(tokenController.Revoke))).Methods("POST")
+	dashboard.HandleFunc("/profile/two-factor", mw.AuthMiddleware(twoFactorController.Show)).Methods("GET")
+	dashboard.HandleFunc("/profile/two-factor", mw.AuthMiddleware(mw.NotWhileImpersonating(twoFactorController.Enable))).Methods("POST")
+	dashboard.HandleFunc("/profile/two-factor/confirm", mw.AuthMiddleware(mw.NotWhileImpersonating(twoFactorController.Confirm))).Methods("POST")
+	dashboard.HandleFunc("/profile/two-factor/recovery-codes", mw.AuthMiddleware(mw.NotWhileImpersonating(twoFactorController.RegenerateCodes))).Methods("POST")
+	dashboard.HandleFunc("/profile/two-factor/disable", mw.AuthMiddleware(mw.NotWhileImpersonating(twoFactorController.Disable))).Methods("POST")
+	dashboard.HandleFunc("/users", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, dashboardController.Users))).Methods("GET")
+	dashboard.HandleFunc("/users/{id}/edit", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, dashboardController.EditUser))).Methods("GET")
+	dashboard.HandleFunc("/users/{id}", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, dashboardController.UpdateUser))).Methods("POST")
+	dashboard.HandleFunc("/users/{id}/delete", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, dashboardController.DeleteUser))).Methods("POST")
+	dashboard.HandleFunc("/users/{id}/verify", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, verificationController.MarkVerified))).Methods("POST")
+	dashboard.HandleFunc("/users/{id}/unlock", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, loginAttemptController.Unlock))).Methods("POST")
+	dashboard.HandleFunc("/users/{id:[0-9]+}/impersonate", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, impersonationController.Start))).Methods("POST")
+	dashboard.HandleFunc("/users/{id}/sessions/logout", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, sessionController.AdminDestroy))).Methods("POST")
+	dashboard.HandleFunc("/login-attempts", mw.AuthMiddleware(middleware.RequirePermission(models.PermUsersManage, loginAttemptController.Index))).Methods("GET")
 
 	// Blog management routes
-	dashboard.HandleFunc("/blogs", middleware.AuthMiddleware(blogController.Index)).Methods("GET")
-	dashboard.HandleFunc("/blogs/create", middleware.AuthMiddleware(blogController.Create)).Methods("GET")
-	dashboard.HandleFunc("/blogs", middleware.AuthMiddleware(blogController.Store)).Methods("POST")
-	dashboard.HandleFunc("/blogs/{id}/edit", middleware.AuthMiddleware(blogController.Edit)).Methods("GET")
-	dashboard.HandleFunc("/blogs/{id}", middleware.AuthMiddleware(blogController.Update)).Methods("POST")
-	dashboard.HandleFunc("/blogs/{id}/delete", middleware.AuthMiddleware(blogController.Delete)).Methods("POST")
-
-	// Admin-only blog management routes
-	dashboard.HandleFunc("/admin/blogs", middleware.AuthMiddleware(blogController.AdminIndex)).Methods("GET")
-	dashboard.HandleFunc("/admin/blogs/{id}/delete", middleware.AuthMiddleware(blogController.AdminDelete)).Methods("POST")
+	dashboard.HandleFunc("/blogs", mw.AuthMiddleware(blogController.Index)).Methods("GET")
+	dashboard.HandleFunc("/blogs/create", mw.AuthMiddleware(middleware.RequirePermission(models.PermBlogsCreate, blogController.Create))).Methods("GET")
+	dashboard.HandleFunc("/blogs/preview", mw.AuthMiddleware(blogController.Preview)).Methods("POST")
+	dashboard.HandleFunc("/blogs", mw.AuthMiddleware(middleware.RequirePermission(models.PermBlogsCreate, blogController.Store))).Methods("POST")
+	dashboard.HandleFunc("/blogs/{id}/edit", mw.AuthMiddleware(blogController.Edit)).Methods("GET")
+	dashboard.HandleFunc("/blogs/{id}", mw.AuthMiddleware(blogController.Update)).Methods("POST")
+	dashboard.HandleFunc("/blogs/{id}/delete", mw.AuthMiddleware(blogController.Delete)).Methods("POST")
+	dashboard.HandleFunc("/blogs/{id}/revisions", mw.AuthMiddleware(revisionController.Index)).Methods("GET")
+	dashboard.HandleFunc("/blogs/{id}/revisions/diff", mw.AuthMiddleware(revisionController.Diff)).Methods("GET")
+	dashboard.HandleFunc("/blogs/{id}/revisions/{revision}/restore", mw.AuthMiddleware(revisionController.Restore)).Methods("POST")
+
+	// Editorial review routes
+	dashboard.HandleFunc("/reviews", mw.AuthMiddleware(middleware.RequirePermission(models.PermBlogsReview, reviewController.Index))).Methods("GET")
+	dashboard.HandleFunc("/reviews/{id:[0-9]+}/approve", mw.AuthMiddleware(middleware.RequirePermission(models.PermBlogsReview, reviewController.Approve))).Methods("POST")
+	dashboard.HandleFunc("/reviews/{id:[0-9]+}/reject", mw.AuthMiddleware(middleware.RequirePermission(models.PermBlogsReview, reviewController.Reject))).Methods("POST")
+
+	// Role and permission management routes
+	dashboard.HandleFunc("/roles", mw.AuthMiddleware(middleware.RequirePermission(models.PermRolesManage, roleController.Index))).Methods("GET")
+	dashboard.HandleFunc("/roles", mw.AuthMiddleware(middleware.RequirePermission(models.PermRolesManage, roleController.Update))).Methods("POST")
+	dashboard.HandleFunc("/roles/create", mw.AuthMiddleware(middleware.RequirePermission(models.PermRolesManage, roleController.Store))).Methods("POST")
+	dashboard.HandleFunc("/roles/{id:[0-9]+}/delete", mw.AuthMiddleware(middleware.RequirePermission(models.PermRolesManage, roleController.Destroy))).Methods("POST")
+
+	// Audit log routes
+	dashboard.HandleFunc("/audit", mw.AuthMiddleware(middleware.RequirePermission(models.PermAuditView, auditController.Index))).Methods("GET")
+	dashboard.HandleFunc("/audit/export", mw.AuthMiddleware(middleware.RequirePermission(models.PermAuditView, auditController.Export))).Methods("GET")
+
+	// Blog-wide management routes
+	dashboard.HandleFunc("/admin/blogs", mw.AuthMiddleware(middleware.RequirePermission(models.PermBlogsEditAny, blogController.AdminIndex))).Methods("GET")
+	dashboard.HandleFunc("/admin/blogs/{id}/delete", mw.AuthMiddleware(middleware.RequirePermission(models.PermBlogsDeleteAny, blogController.AdminDelete))).Methods("POST")
+
+	// Taxonomy management routes
+	dashboard.HandleFunc("/categories", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.Categories))).Methods("GET")
+	dashboard.HandleFunc("/categories", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.StoreCategory))).Methods("POST")
+	dashboard.HandleFunc("/categories/{id}/edit", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.EditCategory))).Methods("GET")
+	dashboard.HandleFunc("/categories/{id}", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.UpdateCategory))).Methods("POST")
+	dashboard.HandleFunc("/categories/{id}/delete", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.DeleteCategory))).Methods("POST")
+	dashboard.HandleFunc("/tags", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.Tags))).Methods("GET")
+	dashboard.HandleFunc("/tags", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.StoreTag))).Methods("POST")
+	dashboard.HandleFunc("/tags/{id}/edit", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.EditTag))).Methods("GET")
+	dashboard.HandleFunc("/tags/{id}", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.UpdateTag))).Methods("POST")
+	dashboard.HandleFunc("/tags/{id}/delete", mw.AuthMiddleware(middleware.RequirePermission(models.PermTaxonomyManage, taxonomyController.DeleteTag))).Methods("POST")
+
+	// Comment moderation routes
+	dashboard.HandleFunc("/comments", mw.AuthMiddleware(middleware.RequirePermission(models.PermCommentsModerate, commentController.Queue))).Methods("GET")
+	dashboard.HandleFunc("/comments/bulk", mw.AuthMiddleware(middleware.RequirePermission(models.PermCommentsModerate, commentController.Bulk))).Methods("POST")
+
+	// JSON API routes (versioned)
+	api := r.PathPrefix("/api/v1").Subrouter()
+	api.NotFoundHandler = http.HandlerFunc(apiController.NotFound)
+	api.HandleFunc("/blogs", mw.APIOptionalAuthMiddleware(apiController.ListBlogs)).Methods("GET")
+	api.HandleFunc("/blogs/{id:[0-9]+}", mw.APIOptionalAuthMiddleware(apiController.ShowBlog)).Methods("GET")
+	api.HandleFunc("/blogs", mw.APIAuthMiddleware(middleware.RequirePermission(models.PermBlogsCreate, apiController.CreateBlog))).Methods("POST")
+	api.HandleFunc("/blogs/{id:[0-9]+}", mw.APIAuthMiddleware(apiController.UpdateBlog)).Methods("PUT", "PATCH")
+	api.HandleFunc("/blogs/{id:[0-9]+}", mw.APIAuthMiddleware(apiController.DeleteBlog)).Methods("DELETE")
+	api.HandleFunc("/me", mw.APIAuthMiddleware(apiController.Me)).Methods("GET")
+	api.HandleFunc("/users", mw.APIAuthMiddleware(middleware.RequirePermission(models.PermUsersManage, apiController.ListUsers))).Methods("GET")
+	api.HandleFunc("/users", mw.APIAuthMiddleware(middleware.RequirePermission(models.PermUsersManage, apiController.CreateUser))).Methods("POST")
+	api.HandleFunc("/users/{id:[0-9]+}", mw.APIAuthMiddleware(middleware.RequirePermission(models.PermUsersManage, apiController.ShowUser))).Methods("GET")
+	api.HandleFunc("/users/{id:[0-9]+}", mw.APIAuthMiddleware(middleware.RequirePermission(models.PermUsersManage, apiController.UpdateUser))).Methods("PUT", "PATCH")
+	api.HandleFunc("/users/{id:[0-9]+}", mw.APIAuthMiddleware(middleware.RequirePermission(models.PermUsersManage, apiController.DeleteUser))).Methods("DELETE")
 
 	return r
 }
@@ -0,0 +1,66 @@
+// tests/audit_test.go - Unit tests for audit event change summaries
+package tests
+
+import (
+	"go-web-app/app/models"
+	"reflect"
+	"testing"
+)
+
+// TestAuditEventChanges tests comparing an event's before and after data
+func TestAuditEventChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected []models.AuditChange
+	}{
+		{
+			name:   "changed field",
+			before: `{"name":"Ann","role":"author"}`,
+			after:  `{"name":"Ann","role":"editor"}`,
+			expected: []models.AuditChange{
+				{Field: "role", Before: "author", After: "editor"},
+			},
+		},
+		{
+			name:   "added and removed fields in name order",
+			before: `{"blogs.create":"granted","blogs.publish":"granted"}`,
+			after:  `{"blogs.create":"granted","blogs.review":"granted"}`,
+			expected: []models.AuditChange{
+				{Field: "blogs.publish", Before: "granted", After: ""},
+				{Field: "blogs.review", Before: "", After: "granted"},
+			},
+		},
+		{
+			name:   "deleted record",
+			before: `{"blogs":3,"name":"Ann"}`,
+			after:  "",
+			expected: []models.AuditChange{
+				{Field: "blogs", Before: "3", After: ""},
+				{Field: "name", Before: "Ann", After: ""},
+			},
+		},
+		{
+			name:     "nothing recorded",
+			before:   "",
+			after:    "",
+			expected: nil,
+		},
+		{
+			name:     "not an object",
+			before:   `["a"]`,
+			after:    `["b"]`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &models.AuditEvent{Before: tt.before, After: tt.after}
+			if got := event.Changes(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// tests/comments_test.go - Unit tests for comment threading
+package tests
+
+import (
+	"go-web-app/app/models"
+	"testing"
+)
+
+// flattenComments lists comment IDs depth-first with their nesting level
+func flattenComments(comments []*models.Comment, depth int, out *[][2]int) {
+	for _, c := range comments {
+		*out = append(*out, [2]int{c.ID, depth})
+		flattenComments(c.Replies, depth+1, out)
+	}
+}
+
+// TestBuildCommentTree tests nesting replies and filtering hidden threads
+func TestBuildCommentTree(t *testing.T) {
+	parent := func(id int) *int { return &id }
+
+	newComments := func() []*models.Comment {
+		return []*models.Comment{
+			{ID: 1, Status: models.CommentApproved},
+			{ID: 2, Status: models.CommentApproved, ParentID: parent(1)},
+			{ID: 3, Status: models.CommentHidden},
+			{ID: 4, Status: models.CommentApproved, ParentID: parent(3)},
+			{ID: 5, Status: models.CommentApproved, ParentID: parent(2)},
+			{ID: 6, Status: models.CommentApproved},
+			{ID: 7, Status: models.CommentApproved, ParentID: parent(99)},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		visible  func(*models.Comment) bool
+		expected [][2]int // comment ID and depth, depth-first
+	}{
+		{
+			name:     "everything visible",
+			visible:  func(*models.Comment) bool { return true },
+			expected: [][2]int{{1, 0}, {2, 1}, {5, 2}, {3, 0}, {4, 1}, {6, 0}},
+		},
+		{
+			name:     "hidden comment drops its replies",
+			visible:  (*models.Comment).IsApproved,
+			expected: [][2]int{{1, 0}, {2, 1}, {5, 2}, {6, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][2]int
+			flattenComments(models.BuildCommentTree(newComments(), tt.visible), 0, &got)
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestIsValidCommentStatus tests the accepted moderation statuses
+func TestIsValidCommentStatus(t *testing.T) {
+	for _, status := range []string{models.CommentPending, models.CommentApproved, models.CommentHidden} {
+		if !models.IsValidCommentStatus(status) {
+			t.Errorf("Expected %q to be valid", status)
+		}
+	}
+
+	for _, status := range []string{"", "published", "APPROVED", "spam"} {
+		if models.IsValidCommentStatus(status) {
+			t.Errorf("Expected %q to be invalid", status)
+		}
+	}
+}
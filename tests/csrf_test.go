@@ -0,0 +1,176 @@
+// tests/csrf_test.go - Unit tests for CSRF and CORS middleware
+package tests
+
+import (
+	"go-web-app/app/middleware"
+	"go-web-app/config"
+	"go-web-app/routes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/sessions"
+)
+
+// cookieMiddleware returns middleware backed by a cookie session store
+func cookieMiddleware() *middleware.Middleware {
+	return middleware.New(&config.Config{}, sessions.NewCookieStore([]byte("test-session-secret")), nil, nil)
+}
+
+// csrfHandler wraps a handler that reports success with the CSRF middleware;
+// rejected requests answer 419
+func csrfHandler(mw *middleware.Middleware) http.Handler {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	failure := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(419)
+	}
+	return mw.CSRFMiddleware(failure)(ok)
+}
+
+// issueCSRFToken returns a token and the session cookie carrying it, as a rendered page would
+func issueCSRFToken(t *testing.T, mw *middleware.Middleware) (string, []*http.Cookie) {
+	rr := httptest.NewRecorder()
+	token := mw.CSRFToken(rr, httptest.NewRequest("GET", "/login", nil))
+	if token == "" {
+		t.Fatal("Expected a CSRF token to be issued")
+	}
+	return token, rr.Result().Cookies()
+}
+
+// TestCSRFMiddleware tests which requests the CSRF middleware lets through
+func TestCSRFMiddleware(t *testing.T) {
+	mw := cookieMiddleware()
+	token, cookies := issueCSRFToken(t, mw)
+
+	postForm := func(value string, withCookies bool) *http.Request {
+		form := url.Values{"email": {"user@example.com"}}
+		if value != "" {
+			form.Set(middleware.CSRFFieldName, value)
+		}
+		req := httptest.NewRequest("POST", "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if withCookies {
+			for _, cookie := range cookies {
+				req.AddCookie(cookie)
+			}
+		}
+		return req
+	}
+
+	headerRequest := httptest.NewRequest("DELETE", "/api/v1/blogs/1", nil)
+	headerRequest.Header.Set(middleware.CSRFHeaderName, token)
+	for _, cookie := range cookies {
+		headerRequest.AddCookie(cookie)
+	}
+
+	bearerRequest := httptest.NewRequest("POST", "/api/v1/blogs", strings.NewReader(`{}`))
+	bearerRequest.Header.Set("Authorization", "Bearer gwa_test")
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		expected int
+	}{
+		{"GET needs no token", httptest.NewRequest("GET", "/dashboard", nil), http.StatusOK},
+		{"Valid form token", postForm(token, true), http.StatusOK},
+		{"Valid header token", headerRequest, http.StatusOK},
+		{"Bearer token request is exempt", bearerRequest, http.StatusOK},
+		{"Missing token", postForm("", true), 419},
+		{"Wrong token", postForm(token+"x", true), 419},
+		{"Token without its session", postForm(token, false), 419},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			csrfHandler(mw).ServeHTTP(rr, tt.req)
+			if rr.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
+
+// TestCSRFTokenIsStable tests that a session keeps its token across requests
+func TestCSRFTokenIsStable(t *testing.T) {
+	mw := cookieMiddleware()
+	token, cookies := issueCSRFToken(t, mw)
+
+	req := httptest.NewRequest("GET", "/dashboard", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	rr := httptest.NewRecorder()
+	if again := mw.CSRFToken(rr, req); again != token {
+		t.Errorf("Expected the session's token %q, got %q", token, again)
+	}
+	if len(rr.Result().Cookies()) != 0 {
+		t.Error("Expected no new session cookie for an existing token")
+	}
+}
+
+// TestCSRFTokenOnlyForForms tests that guests only get a session from pages
+// with a form, so browsing and crawling public pages stores nothing
+func TestCSRFTokenOnlyForForms(t *testing.T) {
+	router := routes.SetupRoutes(newDatabaseApp(t))
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/", false},
+		{"/search?q=go", false},
+		{"/login", true},
+		{"/register", true},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", tt.path, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", tt.path, rr.Code)
+		}
+		if started := len(rr.Result().Cookies()) > 0; started != tt.expected {
+			t.Errorf("%s: expected a session cookie: %v, got %v", tt.path, tt.expected, started)
+		}
+		if hasToken := strings.Contains(rr.Body.String(), `name="`+middleware.CSRFFieldName+`"`); hasToken != tt.expected {
+			t.Errorf("%s: expected a CSRF field: %v, got %v", tt.path, tt.expected, hasToken)
+		}
+	}
+}
+
+// TestCORSMiddleware tests that only configured origins get CORS headers
+func TestCORSMiddleware(t *testing.T) {
+	mw := middleware.New(&config.Config{CORSAllowedOrigins: "https://app.example.com, https://admin.example.com"}, nil, nil, nil)
+
+	handler := mw.CORSMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		origin   string
+		expected string
+	}{
+		{"https://app.example.com", "https://app.example.com"},
+		{"https://admin.example.com", "https://admin.example.com"},
+		{"https://evil.example.com", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/v1/blogs", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tt.expected {
+			t.Errorf("Origin %q: expected Access-Control-Allow-Origin %q, got %q", tt.origin, tt.expected, got)
+		}
+	}
+}
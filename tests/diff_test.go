@@ -0,0 +1,74 @@
+// tests/diff_test.go - Unit tests for line-level revision diffs
+package tests
+
+import (
+	"go-web-app/app/services"
+	"strings"
+	"testing"
+)
+
+// renderDiff formats diff lines like a unified diff body, e.g. " a", "-b", "+c"
+func renderDiff(lines []services.DiffLine) string {
+	var out []string
+	for _, line := range lines {
+		marker := " "
+		switch line.Kind {
+		case services.DiffInsert:
+			marker = "+"
+		case services.DiffDelete:
+			marker = "-"
+		}
+		out = append(out, marker+line.Text)
+	}
+	return strings.Join(out, "\n")
+}
+
+// TestDiffLines tests line-level diffs between two texts
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{"identical", "a\nb", "a\nb", " a\n b"},
+		{"changed line", "a\nb\nc", "a\nx\nc", " a\n-b\n+x\n c"},
+		{"added lines", "a\nc", "a\nb\nc\nd", " a\n+b\n c\n+d"},
+		{"removed lines", "a\nb\nc", "b", "-a\n b\n-c"},
+		{"from empty", "", "a\nb", "+a\n+b"},
+		{"to empty", "a", "", "-a"},
+		{"windows line endings", "a\r\nb", "a\nb", " a\n b"},
+		{"moved line", "a\nb\nc", "b\nc\na", "-a\n b\n c\n+a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderDiff(services.DiffLines(tt.old, tt.new))
+			if got != tt.expected {
+				t.Errorf("Expected diff\n%s\ngot\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestDiffLinesNumbers tests that old and new line numbers follow each side
+func TestDiffLinesNumbers(t *testing.T) {
+	lines := services.DiffLines("a\nb\nc", "a\nx\ny\nc")
+
+	expected := []services.DiffLine{
+		{Kind: services.DiffEqual, Text: "a", OldLine: 1, NewLine: 1},
+		{Kind: services.DiffDelete, Text: "b", OldLine: 2},
+		{Kind: services.DiffInsert, Text: "x", NewLine: 2},
+		{Kind: services.DiffInsert, Text: "y", NewLine: 3},
+		{Kind: services.DiffEqual, Text: "c", OldLine: 3, NewLine: 4},
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d: %+v", len(expected), len(lines), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Line %d: expected %+v, got %+v", i, expected[i], lines[i])
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// tests/feed_test.go - Unit tests for RSS, Atom and JSON Feed encoding
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/routes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sampleFeed returns a feed with one post whose content needs escaping
+func sampleFeed() *services.Feed {
+	published := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	updated := published.Add(2 * time.Hour)
+
+	return &services.Feed{
+		Title:       "Go Blog",
+		Description: "The latest posts",
+		HomeURL:     "https://example.com/",
+		FeedURL:     "https://example.com/feed.xml",
+		Updated:     updated,
+		Items: []services.FeedItem{{
+			ID:          "https://example.com/blog/hello",
+			URL:         "https://example.com/blog/hello",
+			Title:       "Hello & welcome",
+			Summary:     "A first post",
+			ContentHTML: "<p>Hi <strong>there</strong></p>",
+			Author:      "Jane",
+			Published:   published,
+			Updated:     updated,
+		}},
+	}
+}
+
+// TestFeedRSS tests that the RSS 2.0 output is well-formed and carries the item
+func TestFeedRSS(t *testing.T) {
+	body, err := sampleFeed().RSS()
+	if err != nil {
+		t.Fatalf("RSS() error: %v", err)
+	}
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title   string `xml:"title"`
+				Link    string `xml:"link"`
+				PubDate string `xml:"pubDate"`
+				Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("RSS output is not valid XML: %v\n%s", err, body)
+	}
+
+	if doc.Version != "2.0" {
+		t.Errorf("Expected RSS version 2.0, got %q", doc.Version)
+	}
+	if len(doc.Channel.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(doc.Channel.Items))
+	}
+
+	item := doc.Channel.Items[0]
+	if item.Title != "Hello & welcome" {
+		t.Errorf("Expected title to round-trip, got %q", item.Title)
+	}
+	if item.Link != "https://example.com/blog/hello" {
+		t.Errorf("Unexpected link %q", item.Link)
+	}
+	if item.PubDate != "Fri, 01 Mar 2024 10:00:00 +0000" {
+		t.Errorf("Expected RFC 1123 pubDate, got %q", item.PubDate)
+	}
+	if item.Content != "<p>Hi <strong>there</strong></p>" {
+		t.Errorf("Expected content HTML to round-trip, got %q", item.Content)
+	}
+}
+
+// TestFeedAtom tests that the Atom output is well-formed and carries the entry
+func TestFeedAtom(t *testing.T) {
+	body, err := sampleFeed().Atom()
+	if err != nil {
+		t.Fatalf("Atom() error: %v", err)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Author  string `xml:"author>name"`
+			Content struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Atom output is not valid XML: %v\n%s", err, body)
+	}
+
+	if doc.Updated != "2024-03-01T12:00:00Z" {
+		t.Errorf("Expected RFC 3339 feed updated time, got %q", doc.Updated)
+	}
+	if len(doc.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(doc.Entries))
+	}
+
+	entry := doc.Entries[0]
+	if entry.ID != "https://example.com/blog/hello" || entry.Author != "Jane" {
+		t.Errorf("Unexpected entry id/author: %q, %q", entry.ID, entry.Author)
+	}
+	if entry.Content.Type != "html" || entry.Content.Body != "<p>Hi <strong>there</strong></p>" {
+		t.Errorf("Expected escaped HTML content, got %q (%q)", entry.Content.Body, entry.Content.Type)
+	}
+}
+
+// TestFeedJSON tests the JSON Feed 1.1 output
+func TestFeedJSON(t *testing.T) {
+	body, err := sampleFeed().JSON()
+	if err != nil {
+		t.Fatalf("JSON() error: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("JSON output is not valid JSON: %v", err)
+	}
+
+	if version, _ := doc["version"].(string); !strings.HasPrefix(version, "https://jsonfeed.org/version/1") {
+		t.Errorf("Unexpected version %q", version)
+	}
+	if doc["feed_url"] != "https://example.com/feed.xml" {
+		t.Errorf("Unexpected feed_url %v", doc["feed_url"])
+	}
+
+	items, _ := doc["items"].([]interface{})
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(items))
+	}
+
+	item := items[0].(map[string]interface{})
+	if item["content_html"] != "<p>Hi <strong>there</strong></p>" {
+		t.Errorf("Unexpected content_html %v", item["content_html"])
+	}
+	if item["date_published"] != "2024-03-01T10:00:00Z" {
+		t.Errorf("Unexpected date_published %v", item["date_published"])
+	}
+}
+
+// TestFeedEmpty tests that a feed without items still encodes
+func TestFeedEmpty(t *testing.T) {
+	feed := &services.Feed{Title: "Empty", HomeURL: "https://example.com/", FeedURL: "https://example.com/atom.xml"}
+
+	if _, err := feed.RSS(); err != nil {
+		t.Errorf("RSS() error: %v", err)
+	}
+	if body, err := feed.Atom(); err != nil || !strings.Contains(string(body), "<updated>") {
+		t.Errorf("Atom() should always include an updated time: %v", err)
+	}
+	if body, err := feed.JSON(); err != nil || !strings.Contains(string(body), `"items": []`) {
+		t.Errorf("JSON() should encode an empty item list: %v\n%s", err, body)
+	}
+}
+
+// TestFeedItemIDSurvivesRename tests that renaming a post changes its link
+// but not its item ID, so feed readers don't show it as a new post
+func TestFeedItemIDSurvivesRename(t *testing.T) {
+	app := newDatabaseApp(t)
+	app.Config.AppURL = "https://blog.example.com"
+	router := routes.SetupRoutes(app)
+	ctx := context.Background()
+
+	author, err := app.Users.Create(ctx, "Feed Author", "feed@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create author: %v", err)
+	}
+	blog, err := app.Blogs.CreateFrom(ctx, models.BlogInput{Title: "Original title", Content: "Body", Status: models.BlogPublished, UserID: author.ID})
+	if err != nil {
+		t.Fatalf("Failed to create blog: %v", err)
+	}
+
+	// item returns the ID and URL of the feed's only item
+	item := func() (string, string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/feed.json", nil))
+		var feed struct {
+			Items []struct {
+				ID  string `json:"id"`
+				URL string `json:"url"`
+			} `json:"items"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil || len(feed.Items) != 1 {
+			t.Fatalf("Expected one feed item, got %s (%v)", w.Body.String(), err)
+		}
+		return feed.Items[0].ID, feed.Items[0].URL
+	}
+
+	id, url := item()
+	if _, err := app.Blogs.UpdateFrom(ctx, blog.ID, models.BlogInput{Title: "Renamed", Slug: "renamed", Content: "Body", Status: models.BlogPublished, UserID: author.ID}); err != nil {
+		t.Fatalf("Failed to rename blog: %v", err)
+	}
+
+	renamedID, renamedURL := item()
+	if renamedURL == url || renamedURL != "https://blog.example.com/blog/renamed" {
+		t.Errorf("Expected the item to link to the new slug, got %s", renamedURL)
+	}
+	if renamedID != id {
+		t.Errorf("Expected the item ID %s to survive the rename, got %s", id, renamedID)
+	}
+}
+
+// TestFeedSiteURL tests where feed links get their scheme and host from
+func TestFeedSiteURL(t *testing.T) {
+	app := newDatabaseApp(t)
+	router := routes.SetupRoutes(app)
+
+	tests := []struct {
+		name     string
+		appURL   string
+		trust    bool
+		expected string
+	}{
+		{"APP_URL wins over the Host header", "https://blog.example.com/", false, "https://blog.example.com/feed.json"},
+		{"forwarded scheme ignored by default", "", false, "http://attacker.example/feed.json"},
+		{"forwarded scheme trusted behind a proxy", "", true, "https://attacker.example/feed.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app.Config.AppURL, app.Config.TrustProxyHeaders = tt.appURL, tt.trust
+
+			r := httptest.NewRequest("GET", "/feed.json", nil)
+			r.Host = "attacker.example"
+			r.Header.Set("X-Forwarded-Proto", "https")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			var feed struct {
+				FeedURL string `json:"feed_url"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+				t.Fatalf("Failed to decode feed: %v", err)
+			}
+			if feed.FeedURL != tt.expected {
+				t.Errorf("Expected feed URL %s, got %s", tt.expected, feed.FeedURL)
+			}
+		})
+	}
+}
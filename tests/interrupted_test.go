@@ -0,0 +1,66 @@
+// tests/interrupted_test.go - Unit tests for answering interrupted database calls
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-web-app/app/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestInterruptedStatus tests the status chosen for each kind of query error
+func TestInterruptedStatus(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected int
+	}{
+		{"no error", context.Background(), nil, 0},
+		{"no error after the client left", cancelled, nil, 0},
+		{"ordinary error", context.Background(), errors.New("blog not found"), 0},
+		{"client went away", context.Background(), fmt.Errorf("failed to get blogs: %w", context.Canceled), middleware.StatusClientClosedRequest},
+		{"driver error after the client left", cancelled, errors.New("driver: bad connection"), middleware.StatusClientClosedRequest},
+		{"query timed out", context.Background(), fmt.Errorf("failed to get blogs: %w", context.DeadlineExceeded), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil).WithContext(tt.ctx)
+			if status := middleware.InterruptedStatus(r, tt.err); status != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, status)
+			}
+		})
+	}
+}
+
+// TestWriteInterrupted tests the responses written for interrupted requests
+func TestWriteInterrupted(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/v1/blogs", nil)
+	if !middleware.WriteInterrupted(w, r, fmt.Errorf("failed to get blogs: %w", context.DeadlineExceeded)) {
+		t.Fatal("Expected a timed out query to be answered")
+	}
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 503 with Retry-After, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(w.Body.String(), `"request_interrupted"`) {
+		t.Errorf("Expected a JSON error for an API request, got %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/blogs", nil)
+	if middleware.WriteInterrupted(w, r, errors.New("blog not found")) {
+		t.Error("Expected other errors to be left to the caller")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected nothing written for other errors, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,125 @@
+// tests/mailer_test.go - Unit tests for email formatting and the log mailer
+package tests
+
+import (
+	"context"
+	"go-web-app/app/controllers"
+	"go-web-app/app/services"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFormatMessage tests the rendered headers and body of an email
+func TestFormatMessage(t *testing.T) {
+	date := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	raw := string(services.FormatMessage("Go Blog <no-reply@example.com>", services.Message{
+		To:      "jane@example.com",
+		Subject: "Reset your password",
+		Body:    "Hello\nLine two\n",
+	}, date))
+
+	expected := []string{
+		"From: Go Blog <no-reply@example.com>\r\n",
+		"To: jane@example.com\r\n",
+		"Subject: Reset your password\r\n",
+		"Date: Fri, 01 Mar 2024 10:00:00 +0000\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nHello\r\nLine two\r\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(raw, part) {
+			t.Errorf("Expected message to contain %q, got:\n%s", part, raw)
+		}
+	}
+}
+
+// TestFormatMessageHeaderInjection tests that line breaks cannot add headers
+func TestFormatMessageHeaderInjection(t *testing.T) {
+	raw := string(services.FormatMessage("no-reply@example.com", services.Message{
+		To:      "jane@example.com\r\nBcc: victim@example.com",
+		Subject: "Hi\nBcc: other@example.com",
+		Body:    "Body",
+	}, time.Now()))
+
+	headers := raw[:strings.Index(raw, "\r\n\r\n")]
+	for _, line := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(line, "Bcc:") {
+			t.Errorf("Header injected: %q", line)
+		}
+	}
+}
+
+// TestLogMailer tests that the log mailer writes each message to its directory
+func TestLogMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := &services.LogMailer{Dir: filepath.Join(dir, "mail"), From: "no-reply@example.com"}
+
+	err := mailer.Send(services.Message{To: "jane@example.com", Subject: "Hello", Body: "https://example.com/reset-password/abc"})
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "mail", "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected 1 .eml file, got %d (%v)", len(files), err)
+	}
+
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "https://example.com/reset-password/abc") {
+		t.Errorf("Expected the body in the written email, got:\n%s", content)
+	}
+
+	if err := mailer.Send(services.Message{To: "not an address", Subject: "x"}); err == nil {
+		t.Error("Expected an invalid recipient to be rejected")
+	}
+}
+
+// TestResetLinksIgnoreHostHeader tests that password reset emails link to
+// APP_URL whatever Host the request names, and are not sent without it
+func TestResetLinksIgnoreHostHeader(t *testing.T) {
+	app := newDatabaseApp(t)
+	dir := t.TempDir()
+	app.Mailer = &services.LogMailer{Dir: dir, From: "no-reply@example.com"}
+
+	if _, err := app.Users.Create(context.Background(), "Victim", "victim@example.com", "password123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	sendLink := func() []string {
+		r := httptest.NewRequest("POST", "/forgot-password", strings.NewReader(url.Values{"email": {"victim@example.com"}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Host = "attacker.example"
+		controllers.NewPasswordResetController(app).SendLink(httptest.NewRecorder(), r)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+
+	if files := sendLink(); len(files) != 0 {
+		t.Fatalf("Expected no email without APP_URL, got %d", len(files))
+	}
+
+	app.Config.AppURL = "https://blog.example.com/"
+	files := sendLink()
+	if len(files) != 1 {
+		t.Fatalf("Expected one email, got %d", len(files))
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "https://blog.example.com/reset-password/") || strings.Contains(string(raw), "attacker.example") {
+		t.Errorf("Expected the link to use APP_URL, got %s", raw)
+	}
+}
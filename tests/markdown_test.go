@@ -0,0 +1,50 @@
+// tests/markdown_test.go - Unit tests for post content rendering
+package tests
+
+import (
+	"go-web-app/app/services"
+	"strings"
+	"testing"
+)
+
+// TestRenderMarkdown tests Markdown rendering and sanitization
+func TestRenderMarkdown(t *testing.T) {
+	t.Run("Formatting", func(t *testing.T) {
+		html := string(services.RenderContent("markdown", "## Title\n\n- one\n- two\n\n[link](https://example.com)\n\n```go\nfmt.Println()\n```"))
+
+		for _, want := range []string{`<h2 id="title">Title</h2>`, "<li>one</li>", `href="https://example.com"`, `rel="nofollow`, `class="language-go"`} {
+			if !strings.Contains(html, want) {
+				t.Errorf("Expected rendered HTML to contain %q, got: %s", want, html)
+			}
+		}
+	})
+
+	t.Run("Sanitization", func(t *testing.T) {
+		html := string(services.RenderContent("markdown", "Hello <script>alert(1)</script> <img src=x onerror=alert(1)>\n\n[x](javascript:alert(1))"))
+
+		for _, bad := range []string{"<script", "onerror", "javascript:"} {
+			if strings.Contains(html, bad) {
+				t.Errorf("Expected rendered HTML not to contain %q, got: %s", bad, html)
+			}
+		}
+	})
+
+	t.Run("PreviewMatchesStoredRendering", func(t *testing.T) {
+		content := "Hello <script>alert(1)</script>\n\n**bold**"
+		if preview, stored := services.RenderPreview("markdown", content), services.RenderContent("markdown", content); preview != stored {
+			t.Errorf("Expected the preview to match the stored rendering, got %s and %s", preview, stored)
+		}
+	})
+
+	t.Run("LegacyPlainText", func(t *testing.T) {
+		html := string(services.RenderContent("plain", "First line\n\n<b>Second</b> # not a heading"))
+
+		if !strings.Contains(html, "<p>First line</p>") {
+			t.Errorf("Expected plain text lines to become paragraphs, got: %s", html)
+		}
+
+		if strings.Contains(html, "<b>") || strings.Contains(html, "<h1") {
+			t.Errorf("Expected plain text to be escaped, got: %s", html)
+		}
+	})
+}
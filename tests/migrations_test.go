@@ -0,0 +1,37 @@
+// tests/migrations_test.go - Unit tests for the database migrations
+package tests
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestMigrationsDoNotImportApp tests that migrations only depend on the
+// database, so changes to the app's models never require editing a migration
+// that has already shipped
+func TestMigrationsDoNotImportApp(t *testing.T) {
+	files, err := filepath.Glob("../database/migrations/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("Expected to find migrations")
+	}
+
+	for _, file := range files {
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", file, err)
+		}
+		for _, spec := range parsed.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if strings.HasPrefix(path, "go-web-app/app/") {
+				t.Errorf("Expected %s not to import %s", filepath.Base(file), path)
+			}
+		}
+	}
+}
@@ -2,8 +2,10 @@
 package tests
 
 import (
+	"context"
 	"database/sql"
 	"go-web-app/app/models"
+	"go-web-app/app/policies"
 	"go-web-app/config"
 	"log"
 	"testing"
@@ -89,7 +91,7 @@ func TestUserModel(t *testing.T) {
 
 	// Test user creation
 	t.Run("CreateUser", func(t *testing.T) {
-		user, err := userModel.Create("Test User", "test@example.com", "password123")
+		user, err := userModel.Create(context.Background(), "Test User", "test@example.com", "password123")
 		if err != nil {
 			t.Fatalf("Failed to create user: %v", err)
 		}
@@ -110,10 +112,10 @@ func TestUserModel(t *testing.T) {
 	// Test user authentication
 	t.Run("AuthenticateUser", func(t *testing.T) {
 		// Create a user first
-		userModel.Create("Auth User", "auth@example.com", "password123")
+		userModel.Create(context.Background(), "Auth User", "auth@example.com", "password123")
 
 		// Test successful authentication
-		user, err := userModel.Authenticate("auth@example.com", "password123")
+		user, err := userModel.Authenticate(context.Background(), "auth@example.com", "password123")
 		if err != nil {
 			t.Fatalf("Failed to authenticate user: %v", err)
 		}
@@ -123,7 +125,7 @@ func TestUserModel(t *testing.T) {
 		}
 
 		// Test failed authentication
-		_, err = userModel.Authenticate("auth@example.com", "wrongpassword")
+		_, err = userModel.Authenticate(context.Background(), "auth@example.com", "wrongpassword")
 		if err == nil {
 			t.Error("Expected authentication to fail with wrong password")
 		}
@@ -132,10 +134,10 @@ func TestUserModel(t *testing.T) {
 	// Test email existence check
 	t.Run("EmailExists", func(t *testing.T) {
 		// Create a user first
-		userModel.Create("Existing User", "existing@example.com", "password123")
+		userModel.Create(context.Background(), "Existing User", "existing@example.com", "password123")
 
 		// Test existing email
-		exists, err := userModel.EmailExists("existing@example.com")
+		exists, err := userModel.EmailExists(context.Background(), "existing@example.com")
 		if err != nil {
 			t.Fatalf("Failed to check email existence: %v", err)
 		}
@@ -145,7 +147,7 @@ func TestUserModel(t *testing.T) {
 		}
 
 		// Test non-existing email
-		exists, err = userModel.EmailExists("nonexisting@example.com")
+		exists, err = userModel.EmailExists(context.Background(), "nonexisting@example.com")
 		if err != nil {
 			t.Fatalf("Failed to check email existence: %v", err)
 		}
@@ -158,10 +160,10 @@ func TestUserModel(t *testing.T) {
 	// Test get user by ID
 	t.Run("GetUserByID", func(t *testing.T) {
 		// Create a user first
-		createdUser, _ := userModel.Create("Get User", "getuser@example.com", "password123")
+		createdUser, _ := userModel.Create(context.Background(), "Get User", "getuser@example.com", "password123")
 
 		// Get user by ID
-		user, err := userModel.GetByID(createdUser.ID)
+		user, err := userModel.GetByID(context.Background(), createdUser.ID)
 		if err != nil {
 			t.Fatalf("Failed to get user by ID: %v", err)
 		}
@@ -185,14 +187,14 @@ func TestBlogModel(t *testing.T) {
 	blogModel := models.NewBlogModel(testDB)
 
 	// Create a test user first
-	testUser, err := userModel.Create("Blog Author", "author@example.com", "password123")
+	testUser, err := userModel.Create(context.Background(), "Blog Author", "author@example.com", "password123")
 	if err != nil {
 		t.Fatalf("Failed to create test user: %v", err)
 	}
 
 	// Test blog creation
 	t.Run("CreateBlog", func(t *testing.T) {
-		blog, err := blogModel.Create("Test Blog Title", "This is test blog content.", "This is test blog content.", "published", testUser.ID)
+		blog, err := blogModel.Create(context.Background(), "Test Blog Title", "This is test blog content.", "This is test blog content.", "published", testUser.ID)
 		if err != nil {
 			t.Fatalf("Failed to create blog: %v", err)
 		}
@@ -217,10 +219,10 @@ func TestBlogModel(t *testing.T) {
 	// Test get blog by ID
 	t.Run("GetBlogByID", func(t *testing.T) {
 		// Create a blog first
-		createdBlog, _ := blogModel.Create("Get Blog Test", "Get blog content.", "Get blog content.", "published", testUser.ID)
+		createdBlog, _ := blogModel.Create(context.Background(), "Get Blog Test", "Get blog content.", "Get blog content.", "published", testUser.ID)
 
 		// Get blog by ID
-		blog, err := blogModel.GetByID(createdBlog.ID)
+		blog, err := blogModel.GetByID(context.Background(), createdBlog.ID)
 		if err != nil {
 			t.Fatalf("Failed to get blog by ID: %v", err)
 		}
@@ -241,11 +243,11 @@ func TestBlogModel(t *testing.T) {
 	// Test get blogs by user ID
 	t.Run("GetBlogsByUserID", func(t *testing.T) {
 		// Create multiple blogs for the user
-		blogModel.Create("User Blog 1", "Content 1", "Content 1", "published", testUser.ID)
-		blogModel.Create("User Blog 2", "Content 2", "Content 2", "published", testUser.ID)
+		blogModel.Create(context.Background(), "User Blog 1", "Content 1", "Content 1", "published", testUser.ID)
+		blogModel.Create(context.Background(), "User Blog 2", "Content 2", "Content 2", "published", testUser.ID)
 
 		// Get blogs by user ID
-		blogs, err := blogModel.GetByUserID(testUser.ID)
+		blogs, err := blogModel.GetByUserID(context.Background(), testUser.ID)
 		if err != nil {
 			t.Fatalf("Failed to get blogs by user ID: %v", err)
 		}
@@ -265,10 +267,10 @@ func TestBlogModel(t *testing.T) {
 	// Test blog update
 	t.Run("UpdateBlog", func(t *testing.T) {
 		// Create a blog first
-		createdBlog, _ := blogModel.Create("Original Title", "Original content.", "Original content.", "published", testUser.ID)
+		createdBlog, _ := blogModel.Create(context.Background(), "Original Title", "Original content.", "Original content.", "published", testUser.ID)
 
 		// Update the blog
-		updatedBlog, err := blogModel.Update(createdBlog.ID, "Updated Title", "Updated content.", "Updated content.", "published")
+		updatedBlog, err := blogModel.Update(context.Background(), createdBlog.ID, "Updated Title", "Updated content.", "Updated content.", "published")
 		if err != nil {
 			t.Fatalf("Failed to update blog: %v", err)
 		}
@@ -285,46 +287,45 @@ func TestBlogModel(t *testing.T) {
 	// Test blog deletion
 	t.Run("DeleteBlog", func(t *testing.T) {
 		// Create a blog first
-		createdBlog, _ := blogModel.Create("Delete Test", "Delete content.", "Delete content.", "published", testUser.ID)
+		createdBlog, _ := blogModel.Create(context.Background(), "Delete Test", "Delete content.", "Delete content.", "published", testUser.ID)
 
 		// Delete the blog
-		err := blogModel.Delete(createdBlog.ID)
+		err := blogModel.Delete(context.Background(), createdBlog.ID)
 		if err != nil {
 			t.Fatalf("Failed to delete blog: %v", err)
 		}
 
 		// Try to get the deleted blog (should fail)
-		_, err = blogModel.GetByID(createdBlog.ID)
+		_, err = blogModel.GetByID(context.Background(), createdBlog.ID)
 		if err == nil {
 			t.Error("Expected error when getting deleted blog")
 		}
 	})
 
 	// Test user edit permission
-	t.Run("CanUserEdit", func(t *testing.T) {
+	t.Run("CanEditBlog", func(t *testing.T) {
 		// Create another user
-		anotherUser, _ := userModel.Create("Another User", "another@example.com", "password123")
+		anotherUser, _ := userModel.Create(context.Background(), "Another User", "another@example.com", "password123")
 
 		// Create a blog by test user
-		blog, _ := blogModel.Create("Permission Test", "Permission content.", "Permission content.", "published", testUser.ID)
+		blog, _ := blogModel.Create(context.Background(), "Permission Test", "Permission content.", "Permission content.", "published", testUser.ID)
 
-		// Test that owner can edit
-		canEdit, err := blogModel.CanUserEdit(blog.ID, testUser.ID)
+		// The blog belongs to the test user
+		ownerID, err := blogModel.OwnerID(context.Background(), blog.ID)
 		if err != nil {
-			t.Fatalf("Failed to check edit permission: %v", err)
+			t.Fatalf("Failed to look up blog owner: %v", err)
+		}
+		if ownerID != testUser.ID {
+			t.Errorf("Expected owner %d, got %d", testUser.ID, ownerID)
 		}
 
-		if !canEdit {
+		// Test that owner can edit
+		if !policies.CanEditBlog(testUser, blog) {
 			t.Error("Expected owner to be able to edit")
 		}
 
 		// Test that non-owner cannot edit
-		canEdit, err = blogModel.CanUserEdit(blog.ID, anotherUser.ID)
-		if err != nil {
-			t.Fatalf("Failed to check edit permission: %v", err)
-		}
-
-		if canEdit {
+		if policies.CanEditBlog(anotherUser, blog) {
 			t.Error("Expected non-owner to not be able to edit")
 		}
 	})
@@ -339,10 +340,10 @@ func TestBlogCount(t *testing.T) {
 	blogModel := models.NewBlogModel(testDB)
 
 	// Create a test user
-	testUser, _ := userModel.Create("Count User", "count@example.com", "password123")
+	testUser, _ := userModel.Create(context.Background(), "Count User", "count@example.com", "password123")
 
 	// Initially, there should be 0 blogs
-	count, err := blogModel.Count()
+	count, err := blogModel.Count(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to count blogs: %v", err)
 	}
@@ -350,12 +351,12 @@ func TestBlogCount(t *testing.T) {
 	initialCount := count
 
 	// Create some blogs
-	blogModel.Create("Count Blog 1", "Content 1", "Content 1", "published", testUser.ID)
-	blogModel.Create("Count Blog 2", "Content 2", "Content 2", "published", testUser.ID)
-	blogModel.Create("Count Blog 3", "Content 3", "Content 3", "published", testUser.ID)
+	blogModel.Create(context.Background(), "Count Blog 1", "Content 1", "Content 1", "published", testUser.ID)
+	blogModel.Create(context.Background(), "Count Blog 2", "Content 2", "Content 2", "published", testUser.ID)
+	blogModel.Create(context.Background(), "Count Blog 3", "Content 3", "Content 3", "published", testUser.ID)
 
 	// Count should increase by 3
-	count, err = blogModel.Count()
+	count, err = blogModel.Count(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to count blogs: %v", err)
 	}
@@ -0,0 +1,266 @@
+// tests/policy_test.go - Unit tests for role permissions and authorization policies
+package tests
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/app/policies"
+	"testing"
+)
+
+// newPolicyUser builds a user whose role grants the given permissions
+func newPolicyUser(id int, permissions ...string) *models.User {
+	user := &models.User{ID: id, Role: "custom", Permissions: map[string]bool{}}
+	for _, permission := range permissions {
+		user.Permissions[permission] = true
+	}
+	return user
+}
+
+// TestPolicyCan tests plain permission checks
+func TestPolicyCan(t *testing.T) {
+	user := newPolicyUser(1, models.PermTaxonomyManage)
+
+	if !policies.Can(user, models.PermTaxonomyManage) {
+		t.Error("Expected granted permission to be allowed")
+	}
+	if policies.Can(user, models.PermUsersManage) {
+		t.Error("Expected missing permission to be denied")
+	}
+	if policies.Can(nil, models.PermTaxonomyManage) {
+		t.Error("Expected a guest to be denied")
+	}
+	if policies.Can(&models.User{ID: 2, Role: "admin"}, models.PermUsersManage) {
+		t.Error("Expected a role name alone to grant nothing")
+	}
+}
+
+// TestBlogPolicies tests the ownership rules for editing, deleting and moderating posts
+func TestBlogPolicies(t *testing.T) {
+	owner := newPolicyUser(1, models.PermBlogsCreate)
+	stranger := newPolicyUser(2, models.PermBlogsCreate)
+	editor := newPolicyUser(3, models.PermBlogsEditAny)
+	janitor := newPolicyUser(4, models.PermBlogsDeleteAny)
+	moderator := newPolicyUser(5, models.PermCommentsModerate)
+	blog := &models.Blog{ID: 1, UserID: owner.ID, Status: models.BlogDraft}
+
+	tests := []struct {
+		name     string
+		check    func(*models.User, int) bool
+		user     *models.User
+		expected bool
+	}{
+		{"Owner edits", editCheck(policies.CanEditBlog, blog), owner, true},
+		{"Stranger edits", editCheck(policies.CanEditBlog, blog), stranger, false},
+		{"Editor edits", editCheck(policies.CanEditBlog, blog), editor, true},
+		{"Deleter edits", editCheck(policies.CanEditBlog, blog), janitor, false},
+		{"Guest edits", editCheck(policies.CanEditBlog, blog), nil, false},
+		{"Owner deletes", policies.CanDeleteBlog, owner, true},
+		{"Stranger deletes", policies.CanDeleteBlog, stranger, false},
+		{"Editor deletes", policies.CanDeleteBlog, editor, false},
+		{"Deleter deletes", policies.CanDeleteBlog, janitor, true},
+		{"Owner views draft", editCheck(policies.CanViewBlog, blog), owner, true},
+		{"Stranger views draft", editCheck(policies.CanViewBlog, blog), stranger, false},
+		{"Owner moderates", policies.CanModerateComments, owner, true},
+		{"Stranger moderates", policies.CanModerateComments, stranger, false},
+		{"Moderator moderates", policies.CanModerateComments, moderator, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check(tt.user, owner.ID); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// editCheck adapts a policy about a particular post to the ownerID-based table in TestBlogPolicies
+func editCheck(check func(*models.User, *models.Blog) bool, blog *models.Blog) func(*models.User, int) bool {
+	return func(user *models.User, _ int) bool {
+		return check(user, blog)
+	}
+}
+
+// TestReviewPolicies tests who may review and edit posts waiting for review
+func TestReviewPolicies(t *testing.T) {
+	author := newPolicyUser(1, models.PermBlogsCreate)
+	reviewer := newPolicyUser(2, models.PermBlogsCreate, models.PermBlogsReview)
+	writer := newPolicyUser(3, models.PermBlogsCreate)
+
+	pending := &models.Blog{ID: 1, UserID: author.ID, Status: models.BlogPendingReview}
+	draft := &models.Blog{ID: 2, UserID: author.ID, Status: models.BlogDraft}
+	ownPending := &models.Blog{ID: 3, UserID: reviewer.ID, Status: models.BlogPendingReview}
+
+	if !policies.CanReviewBlogs(reviewer) || policies.CanReviewBlogs(writer) {
+		t.Error("Expected only blogs.review to open the review queue")
+	}
+	if !policies.CanReviewBlog(reviewer, pending) || !policies.CanEditBlog(reviewer, pending) {
+		t.Error("Expected a reviewer to review and edit a submitted post")
+	}
+	if policies.CanReviewBlog(reviewer, draft) || policies.CanEditBlog(reviewer, draft) {
+		t.Error("Expected a reviewer not to touch a post that was not submitted")
+	}
+	if policies.CanReviewBlog(reviewer, ownPending) {
+		t.Error("Expected a reviewer not to review their own post")
+	}
+	if policies.CanReviewBlog(writer, pending) || policies.CanEditBlog(writer, pending) {
+		t.Error("Expected a writer without blogs.review not to touch someone else's submission")
+	}
+}
+
+// TestReviewAction tests which status changes count as review steps
+func TestReviewAction(t *testing.T) {
+	tests := []struct {
+		from, to string
+		expected string
+	}{
+		{models.BlogDraft, models.BlogPendingReview, models.ReviewSubmitted},
+		{models.BlogChangesRequested, models.BlogPendingReview, models.ReviewSubmitted},
+		{models.BlogPendingReview, models.BlogPublished, models.ReviewApproved},
+		{models.BlogPendingReview, models.BlogScheduled, models.ReviewApproved},
+		{models.BlogPendingReview, models.BlogChangesRequested, models.ReviewChangesRequested},
+		{models.BlogPendingReview, models.BlogDraft, models.ReviewWithdrawn},
+		{models.BlogPendingReview, models.BlogPendingReview, ""},
+		{models.BlogDraft, models.BlogPublished, ""},
+		{models.BlogChangesRequested, models.BlogDraft, ""},
+	}
+
+	for _, tt := range tests {
+		if got := models.ReviewAction(tt.from, tt.to); got != tt.expected {
+			t.Errorf("ReviewAction(%q, %q) = %q, expected %q", tt.from, tt.to, got, tt.expected)
+		}
+	}
+}
+
+// TestCanSetBlogStatus tests that only publishers may make posts public
+func TestCanSetBlogStatus(t *testing.T) {
+	writer := newPolicyUser(1, models.PermBlogsCreate)
+	publisher := newPolicyUser(2, models.PermBlogsCreate, models.PermBlogsPublish)
+	reviewer := newPolicyUser(3, models.PermBlogsCreate, models.PermBlogsPublish, models.PermBlogsReview)
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		from, to string
+		expected bool
+	}{
+		{"Writer saves draft", writer, models.BlogDraft, models.BlogDraft, true},
+		{"Writer publishes", writer, models.BlogDraft, models.BlogPublished, false},
+		{"Writer schedules", writer, models.BlogDraft, models.BlogScheduled, false},
+		{"Writer keeps published post live", writer, models.BlogPublished, models.BlogPublished, false},
+		{"Writer keeps scheduled post", writer, models.BlogScheduled, models.BlogScheduled, false},
+		{"Writer resubmits published post", writer, models.BlogPublished, models.BlogPendingReview, true},
+		{"Publisher keeps published post live", publisher, models.BlogPublished, models.BlogPublished, true},
+		{"Writer unpublishes", writer, models.BlogPublished, models.BlogDraft, true},
+		{"Publisher publishes", publisher, models.BlogDraft, models.BlogPublished, true},
+		{"Publisher schedules", publisher, models.BlogDraft, models.BlogScheduled, true},
+		{"Writer submits for review", writer, models.BlogDraft, models.BlogPendingReview, true},
+		{"Writer resubmits", writer, models.BlogChangesRequested, models.BlogPendingReview, true},
+		{"Writer keeps changes requested", writer, models.BlogChangesRequested, models.BlogChangesRequested, true},
+		{"Writer publishes submission", writer, models.BlogPendingReview, models.BlogPublished, false},
+		{"Reviewer publishes submission", reviewer, models.BlogPendingReview, models.BlogPublished, true},
+		{"Reviewer requests changes by saving", reviewer, models.BlogPendingReview, models.BlogChangesRequested, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policies.CanSetBlogStatus(tt.user, tt.from, tt.to); got != tt.expected {
+				t.Errorf("CanSetBlogStatus(%q, %q) = %v, expected %v", tt.from, tt.to, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestEditedBlogStatus tests that live posts edited by someone who can't
+// publish go back for review
+func TestEditedBlogStatus(t *testing.T) {
+	writer := newPolicyUser(1, models.PermBlogsCreate)
+	publisher := newPolicyUser(2, models.PermBlogsCreate, models.PermBlogsPublish)
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		from, to string
+		expected string
+	}{
+		{"Writer edits published post", writer, models.BlogPublished, models.BlogPublished, models.BlogPendingReview},
+		{"Writer edits scheduled post", writer, models.BlogScheduled, models.BlogScheduled, models.BlogPendingReview},
+		{"Writer unpublishes", writer, models.BlogPublished, models.BlogDraft, models.BlogDraft},
+		{"Writer edits draft", writer, models.BlogDraft, models.BlogDraft, models.BlogDraft},
+		{"Publisher edits published post", publisher, models.BlogPublished, models.BlogPublished, models.BlogPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policies.EditedBlogStatus(tt.user, tt.from, tt.to); got != tt.expected {
+				t.Errorf("EditedBlogStatus(%q, %q) = %q, expected %q", tt.from, tt.to, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIsAdministrator tests who the admin two-factor policy applies to
+func TestIsAdministrator(t *testing.T) {
+	if !policies.IsAdministrator(newPolicyUser(1, models.PermUsersManage)) {
+		t.Error("Expected users.manage to make an administrator")
+	}
+	if !policies.IsAdministrator(newPolicyUser(2, models.PermRolesManage)) {
+		t.Error("Expected roles.manage to make an administrator")
+	}
+	if policies.IsAdministrator(newPolicyUser(3, models.PermBlogsEditAny, models.PermCommentsModerate)) {
+		t.Error("Expected content permissions not to make an administrator")
+	}
+}
+
+// TestIsAdminPermission tests which permissions API tokens need the admin scope for
+func TestIsAdminPermission(t *testing.T) {
+	for _, permission := range []string{models.PermUsersManage, models.PermRolesManage, models.PermAuditView, models.PermBlogsDeleteAny} {
+		if !policies.IsAdminPermission(permission) {
+			t.Errorf("Expected %s to be an admin permission", permission)
+		}
+	}
+	for _, permission := range []string{models.PermBlogsCreate, models.PermBlogsPublish, models.PermCommentsModerate} {
+		if policies.IsAdminPermission(permission) {
+			t.Errorf("Expected %s not to be an admin permission", permission)
+		}
+	}
+}
+
+// TestCanImpersonate tests who admins may sign in as for support
+func TestCanImpersonate(t *testing.T) {
+	admin := newPolicyUser(1, models.PermUsersManage)
+	author := newPolicyUser(2, models.PermBlogsCreate)
+
+	if !policies.CanImpersonate(admin, author) {
+		t.Error("Expected an admin to impersonate an author")
+	}
+	if policies.CanImpersonate(admin, admin) {
+		t.Error("Expected an admin not to impersonate themselves")
+	}
+	if policies.CanImpersonate(admin, newPolicyUser(3, models.PermRolesManage)) {
+		t.Error("Expected administrators not to be impersonated")
+	}
+	if policies.CanImpersonate(author, newPolicyUser(4)) {
+		t.Error("Expected users without users.manage to be denied")
+	}
+	if policies.CanImpersonate(admin, nil) {
+		t.Error("Expected a missing target to be denied")
+	}
+}
+
+// TestIsValidRoleName tests role name validation
+func TestIsValidRoleName(t *testing.T) {
+	valid := []string{"editor", "guest_author", "reviewer-2"}
+	invalid := []string{"", "a", "Editor", "2nd", "has space", "role;drop"}
+
+	for _, name := range valid {
+		if !models.IsValidRoleName(name) {
+			t.Errorf("Expected %q to be a valid role name", name)
+		}
+	}
+	for _, name := range invalid {
+		if models.IsValidRoleName(name) {
+			t.Errorf("Expected %q to be an invalid role name", name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// tests/scheduler_test.go - Unit tests for scheduled publishing
+package tests
+
+import (
+	"context"
+	"errors"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePublisher counts how often the scheduler asks it to publish
+type fakePublisher struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (p *fakePublisher) PublishDue(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return 1, p.err
+}
+
+func (p *fakePublisher) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// TestSchedulerRunsUntilStopped tests that the scheduler publishes on start and
+// on every tick, and stops publishing once Stop returns
+func TestSchedulerRunsUntilStopped(t *testing.T) {
+	publisher := &fakePublisher{}
+	scheduler := services.NewScheduler(publisher, 5*time.Millisecond)
+
+	scheduler.Start()
+	scheduler.Start() // A second Start must not launch another goroutine
+
+	deadline := time.Now().Add(time.Second)
+	for publisher.Calls() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if publisher.Calls() < 3 {
+		t.Fatalf("Expected at least 3 publish runs, got %d", publisher.Calls())
+	}
+
+	scheduler.Stop()
+	calls := publisher.Calls()
+	time.Sleep(20 * time.Millisecond)
+	if publisher.Calls() != calls {
+		t.Errorf("Expected no publish runs after Stop, got %d more", publisher.Calls()-calls)
+	}
+
+	scheduler.Stop() // Stopping twice is safe
+}
+
+// TestSchedulerKeepsRunningAfterErrors tests that a failed run does not stop the scheduler
+func TestSchedulerKeepsRunningAfterErrors(t *testing.T) {
+	publisher := &fakePublisher{err: errors.New("database unavailable")}
+	scheduler := services.NewScheduler(publisher, 5*time.Millisecond)
+
+	scheduler.Start()
+	defer scheduler.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for publisher.Calls() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if publisher.Calls() < 2 {
+		t.Errorf("Expected the scheduler to retry after an error, got %d runs", publisher.Calls())
+	}
+}
+
+// TestNewSchedulerDefaultInterval tests the fallback for a missing interval
+func TestNewSchedulerDefaultInterval(t *testing.T) {
+	scheduler := services.NewScheduler(&fakePublisher{}, 0)
+	if scheduler.Interval != services.DefaultSchedulerInterval {
+		t.Errorf("Expected interval %s, got %s", services.DefaultSchedulerInterval, scheduler.Interval)
+	}
+}
+
+// TestBlogIsPublished tests public visibility of draft, scheduled and published posts
+func TestBlogIsPublished(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		blog     models.Blog
+		expected bool
+	}{
+		{"draft", models.Blog{Status: models.BlogDraft}, false},
+		{"published", models.Blog{Status: models.BlogPublished}, true},
+		{"scheduled in the future", models.Blog{Status: models.BlogScheduled, PublishAt: &future}, false},
+		{"scheduled and due", models.Blog{Status: models.BlogScheduled, PublishAt: &past}, true},
+		{"scheduled without a time", models.Blog{Status: models.BlogScheduled}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.blog.IsPublished(); got != tt.expected {
+				t.Errorf("Expected IsPublished() = %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// tests/search_test.go - Unit tests for search query building and snippets
+package tests
+
+import (
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestFullTextQuery tests converting user input into a boolean-mode query
+func TestFullTextQuery(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"Go web", "go* web*"},
+		{"+go -web \"exact\" (group)", "go* web* exact* group*"},
+		{"Café café", "café*"},
+		{"  ***  ", ""},
+	}
+
+	for _, tc := range testCases {
+		query := models.FullTextQuery(tc.input)
+		if query != tc.expected {
+			t.Errorf("FullTextQuery(%q): expected '%s', got '%s'", tc.input, tc.expected, query)
+		}
+	}
+}
+
+// TestHighlight tests highlighted, escaped search snippets
+func TestHighlight(t *testing.T) {
+	snippet := string(services.Highlight("Learn Go <fast> with goroutines and algorithms", []string{"go"}, 200))
+
+	if !strings.Contains(snippet, "<mark>Go</mark>") || !strings.Contains(snippet, "<mark>goroutines</mark>") {
+		t.Errorf("Expected word prefixes to be highlighted, got: %s", snippet)
+	}
+	if strings.Contains(snippet, "al<mark>") {
+		t.Errorf("Expected matches only at word starts, got: %s", snippet)
+	}
+	if !strings.Contains(snippet, "&lt;fast&gt;") {
+		t.Errorf("Expected text to be HTML-escaped, got: %s", snippet)
+	}
+
+	// Long text is trimmed to a window around the first match
+	long := strings.Repeat("filler ", 100) + "needle " + strings.Repeat("padding ", 100)
+	snippet = string(services.Highlight(long, []string{"needle"}, 120))
+	if !strings.HasPrefix(snippet, "&hellip;") || !strings.HasSuffix(snippet, "&hellip;") || !strings.Contains(snippet, "<mark>needle</mark>") {
+		t.Errorf("Expected a trimmed window around the match, got: %s", snippet)
+	}
+}
+
+// TestHighlightKeepsRunesWhole tests that excerpts of text without spaces
+// are not cut in the middle of a multi-byte character
+func TestHighlightKeepsRunesWhole(t *testing.T) {
+	text := strings.Repeat("日本語", 40) + "Go" + strings.Repeat("テキスト", 40)
+
+	for _, length := range []int{31, 32, 50, 100} {
+		snippet := string(services.Highlight(text, []string{"go"}, length))
+		if !utf8.ValidString(snippet) {
+			t.Errorf("length %d: expected valid UTF-8, got %q", length, snippet)
+		}
+	}
+}
+
+// TestPlainText tests stripping rendered markup for snippets
+func TestPlainText(t *testing.T) {
+	text := services.PlainText(services.FormatMarkdown, "# Title\n\nSome **bold** & [a link](https://example.com)")
+	if text != "Title Some bold & a link" {
+		t.Errorf("Expected markup to be stripped, got '%s'", text)
+	}
+}
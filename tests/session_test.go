@@ -0,0 +1,137 @@
+// tests/session_test.go - Unit tests for the server-side session store
+package tests
+
+import (
+	"context"
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"go-web-app/app/services"
+	"go-web-app/database/dialect"
+	"go-web-app/database/migrations"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDescribeUserAgent tests the device labels shown in session lists
+func TestDescribeUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		expected  string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", "Chrome on Windows"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge on Windows"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", "Safari on macOS"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", "Safari on iPhone"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox on Linux"},
+		{"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "Chrome on Android"},
+		{"curl/8.4.0", "curl"},
+		{"", "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		if got := services.DescribeUserAgent(tt.userAgent); got != tt.expected {
+			t.Errorf("DescribeUserAgent(%q) = %q, expected %q", tt.userAgent, got, tt.expected)
+		}
+	}
+}
+
+// TestDatabaseStoreNewSession tests that requests without a valid session
+// cookie get a fresh session without touching the database
+func TestDatabaseStoreNewSession(t *testing.T) {
+	store := middleware.NewDatabaseStore(nil, []byte("test-session-secret"))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	session, err := store.New(r, "session")
+	if err != nil {
+		t.Fatalf("Expected no error without a cookie, got %v", err)
+	}
+	if !session.IsNew || session.ID != "" {
+		t.Error("Expected a new session without an ID")
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "forged"})
+	session, err = store.New(r, "session")
+	if err == nil {
+		t.Error("Expected a forged cookie to be rejected")
+	}
+	if !session.IsNew || len(session.Values) != 0 {
+		t.Error("Expected a forged cookie to yield an empty session")
+	}
+}
+
+// TestDatabaseStoreGuestSessions tests that a guest's session stays in its
+// cookie and only a signed-in session is stored in the database
+func TestDatabaseStoreGuestSessions(t *testing.T) {
+	db := openRepositoryBackend(t, dialect.SQLite, "")
+	if err := migrations.RunMigrations(db); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	store := middleware.NewDatabaseStore(models.NewSessionModel(db), []byte("test-session-secret"))
+	user, err := models.NewUserModel(db).Create(context.Background(), "Session User", "session@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	storedSessions := func() int {
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM sessions").Scan(&count); err != nil {
+			t.Fatalf("Failed to count sessions: %v", err)
+		}
+		return count
+	}
+
+	// save stores values in a session loaded from cookie and returns the new cookie
+	save := func(cookie *http.Cookie, values map[interface{}]interface{}) *http.Cookie {
+		r := httptest.NewRequest("GET", "/", nil)
+		if cookie != nil {
+			r.AddCookie(cookie)
+		}
+		session, err := store.New(r, "session")
+		if err != nil {
+			t.Fatalf("Failed to load session: %v", err)
+		}
+		for key, value := range values {
+			session.Values[key] = value
+		}
+
+		w := httptest.NewRecorder()
+		if err := store.Save(r, w, session); err != nil {
+			t.Fatalf("Failed to save session: %v", err)
+		}
+		return w.Result().Cookies()[0]
+	}
+
+	// load returns the values of the session cookie names
+	load := func(cookie *http.Cookie) map[interface{}]interface{} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.AddCookie(cookie)
+		session, err := store.New(r, "session")
+		if err != nil || session.IsNew {
+			t.Fatalf("Expected the session to load, got new=%v, %v", session.IsNew, err)
+		}
+		return session.Values
+	}
+
+	guest := save(nil, map[interface{}]interface{}{"csrf_token": "guest-token"})
+	if n := storedSessions(); n != 0 {
+		t.Errorf("Expected no stored sessions for a guest, got %d", n)
+	}
+	if token := load(guest)["csrf_token"]; token != "guest-token" {
+		t.Errorf("Expected the guest's token from the cookie, got %v", token)
+	}
+
+	signedIn := save(guest, map[interface{}]interface{}{"user_id": user.ID, "session_version": 0})
+	if n := storedSessions(); n != 1 {
+		t.Errorf("Expected the signed-in session to be stored, got %d", n)
+	}
+	if values := load(signedIn); values["user_id"] != user.ID || values["csrf_token"] != "guest-token" {
+		t.Errorf("Expected the stored session values, got %v", values)
+	}
+
+	save(nil, map[interface{}]interface{}{"two_factor_user_id": user.ID})
+	if n := storedSessions(); n != 2 {
+		t.Errorf("Expected a pending two-factor challenge to be stored, got %d", n)
+	}
+}
@@ -0,0 +1,96 @@
+// tests/signature_test.go - Unit tests for signed link signatures
+package tests
+
+import (
+	"context"
+	"go-web-app/app/services"
+	"go-web-app/routes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestSignature tests that signatures only validate for the same key and parts
+func TestSignature(t *testing.T) {
+	signature := services.Sign("app-key", "verify-email", "42", "jane@example.com", "1700000000")
+
+	tests := []struct {
+		name     string
+		key      string
+		parts    []string
+		expected bool
+	}{
+		{"Same key and parts", "app-key", []string{"verify-email", "42", "jane@example.com", "1700000000"}, true},
+		{"Different key", "other-key", []string{"verify-email", "42", "jane@example.com", "1700000000"}, false},
+		{"Different user", "app-key", []string{"verify-email", "43", "jane@example.com", "1700000000"}, false},
+		{"Changed email", "app-key", []string{"verify-email", "42", "john@example.com", "1700000000"}, false},
+		{"Extended expiry", "app-key", []string{"verify-email", "42", "jane@example.com", "1800000000"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := services.ValidSignature(tt.key, signature, tt.parts...); got != tt.expected {
+				t.Errorf("ValidSignature() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	if services.ValidSignature("app-key", "", "verify-email") {
+		t.Error("Expected an empty signature to be rejected")
+	}
+}
+
+// TestSignatureSeparatesParts tests that moving text between parts changes the signature
+func TestSignatureSeparatesParts(t *testing.T) {
+	pairs := [][2][]string{
+		{{"a|b", "c"}, {"a", "b|c"}},
+		{{"ab", "c"}, {"a", "bc"}},
+		{{"a|.", "b"}, {"a", ".b"}},
+		{{"a"}, {"a", ""}},
+	}
+
+	for _, pair := range pairs {
+		if services.Sign("key", pair[0]...) == services.Sign("key", pair[1]...) {
+			t.Errorf("Expected %q and %q to sign differently", pair[0], pair[1])
+		}
+	}
+}
+
+// TestVerificationLinksNeedAppKey tests that verification links signed with
+// the default APP_KEY are refused, so they cannot be forged on deployments
+// that never set one
+func TestVerificationLinksNeedAppKey(t *testing.T) {
+	app := newDatabaseApp(t)
+	router := routes.SetupRoutes(app)
+
+	user, err := app.Users.Create(context.Background(), "Unverified", "unverified@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	verify := func(key string) int {
+		id := strconv.Itoa(user.ID)
+		expires := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+		query := url.Values{"expires": {expires}, "signature": {services.Sign(key, "verify-email", id, user.Email, expires)}}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/verify-email/"+id+"?"+query.Encode(), nil))
+		return w.Code
+	}
+
+	app.Config.AppKey = "default-key"
+	if code := verify("default-key"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected links to be refused with the default APP_KEY, got %d", code)
+	}
+
+	app.Config.AppKey = "a-real-secret"
+	if code := verify("default-key"); code != http.StatusForbidden {
+		t.Errorf("Expected a link signed with the default key to be rejected, got %d", code)
+	}
+	if code := verify("a-real-secret"); code != http.StatusSeeOther {
+		t.Errorf("Expected a link signed with APP_KEY to verify, got %d", code)
+	}
+}
@@ -0,0 +1,51 @@
+// tests/slug_test.go - Unit tests for blog slug generation
+package tests
+
+import (
+	"go-web-app/app/models"
+	"strings"
+	"testing"
+)
+
+// TestSlugify tests converting titles into URL slugs
+func TestSlugify(t *testing.T) {
+	testCases := []struct {
+		title string
+		slug  string
+	}{
+		{"Getting Started with Go", "getting-started-with-go"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"Café & Crème Brûlée", "cafe-creme-brulee"},
+		{"Go 1.21 - What's New?", "go-1-21-what-s-new"},
+		{"2024", "post-2024"},
+		{"!!!", "post"},
+		{"", "post"},
+	}
+
+	for _, tc := range testCases {
+		slug := models.Slugify(tc.title)
+		if slug != tc.slug {
+			t.Errorf("Slugify(%q): expected '%s', got '%s'", tc.title, tc.slug, slug)
+		}
+	}
+
+	// Long titles are truncated without a trailing hyphen
+	long := models.Slugify(strings.Repeat("word ", 100))
+	if len(long) > 200 || strings.HasSuffix(long, "-") {
+		t.Errorf("Expected truncated slug without trailing hyphen, got %d chars: %s", len(long), long)
+	}
+}
+
+// TestBlogURL tests the public URL of a blog post
+func TestBlogURL(t *testing.T) {
+	blog := &models.Blog{ID: 7, Slug: "hello-world"}
+	if blog.URL() != "/blog/hello-world" {
+		t.Errorf("Expected '/blog/hello-world', got '%s'", blog.URL())
+	}
+
+	// Blogs without a slug fall back to the legacy numeric URL
+	blog.Slug = ""
+	if blog.URL() != "/blog/7" {
+		t.Errorf("Expected '/blog/7', got '%s'", blog.URL())
+	}
+}
@@ -0,0 +1,68 @@
+// tests/taxonomy_test.go - Unit tests for category trees and tag parsing
+package tests
+
+import (
+	"go-web-app/app/models"
+	"reflect"
+	"testing"
+)
+
+// TestBuildCategoryTree tests ordering categories depth-first with nesting levels
+func TestBuildCategoryTree(t *testing.T) {
+	parent := func(id int) *int { return &id }
+
+	categories := []*models.Category{
+		{ID: 1, Name: "Programming"},
+		{ID: 2, Name: "Go", ParentID: parent(1)},
+		{ID: 3, Name: "Cooking"},
+		{ID: 4, Name: "Concurrency", ParentID: parent(2)},
+		{ID: 5, Name: "Assembly", ParentID: parent(1)},
+		{ID: 6, Name: "Orphan", ParentID: parent(99)},
+	}
+
+	tree := models.BuildCategoryTree(categories)
+
+	var names []string
+	var depths []int
+	for _, c := range tree {
+		names = append(names, c.Name)
+		depths = append(depths, c.Depth)
+	}
+
+	expectedNames := []string{"Cooking", "Orphan", "Programming", "Assembly", "Go", "Concurrency"}
+	expectedDepths := []int{0, 0, 0, 1, 1, 2}
+
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("Expected order %v, got %v", expectedNames, names)
+	}
+	if !reflect.DeepEqual(depths, expectedDepths) {
+		t.Errorf("Expected depths %v, got %v", expectedDepths, depths)
+	}
+
+	// Descendants include the category itself and every level below it
+	ids := models.CategoryDescendantIDs(categories, 1)
+	if !reflect.DeepEqual(ids, []int{1, 2, 5, 4}) {
+		t.Errorf("Expected descendants [1 2 5 4], got %v", ids)
+	}
+}
+
+// TestParseTagNames tests splitting the comma-separated new tags field
+func TestParseTagNames(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{"go, web ,  databases", []string{"go", "web", "databases"}},
+		{"Go, go, GO", []string{"Go"}},
+		{"  machine   learning ,", []string{"machine learning"}},
+		{" , ,", nil},
+		{"", nil},
+	}
+
+	for _, tc := range testCases {
+		names := models.ParseTagNames(tc.input)
+		if !reflect.DeepEqual(names, tc.expected) {
+			t.Errorf("ParseTagNames(%q): expected %v, got %v", tc.input, tc.expected, names)
+		}
+	}
+}
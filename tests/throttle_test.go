@@ -0,0 +1,68 @@
+// tests/throttle_test.go - Unit tests for login throttling
+package tests
+
+import (
+	"go-web-app/app/middleware"
+	"go-web-app/app/services"
+	"go-web-app/config"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestThrottleWait tests the free attempts, exponential backoff and lockout
+func TestThrottleWait(t *testing.T) {
+	throttle := services.Throttle{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second, LockoutAfter: 10, LockoutFor: 15 * time.Minute}
+
+	tests := []struct {
+		name       string
+		failures   int
+		sinceLast  time.Duration
+		wait       time.Duration
+		shouldLock bool
+	}{
+		{"No failures", 0, 0, 0, false},
+		{"Free attempts", 3, 0, 0, false},
+		{"First delay", 4, 0, time.Second, false},
+		{"Delay doubles", 5, 0, 2 * time.Second, false},
+		{"Delay doubles again", 6, 0, 4 * time.Second, false},
+		{"Delay is capped", 9, 0, 10 * time.Second, false},
+		{"Delay already served", 6, 5 * time.Second, 0, false},
+		{"Delay partly served", 6, 3 * time.Second, time.Second, false},
+		{"Lockout", 10, time.Minute, 14 * time.Minute, true},
+		{"Lockout expired", 12, 15 * time.Minute, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, locked := throttle.Wait(tt.failures, tt.sinceLast)
+			if wait != tt.wait || locked != tt.shouldLock {
+				t.Errorf("Wait(%d, %v) = %v, %v, expected %v, %v", tt.failures, tt.sinceLast, wait, locked, tt.wait, tt.shouldLock)
+			}
+		})
+	}
+}
+
+// TestClientIP tests that X-Forwarded-For is only believed when configured
+func TestClientIP(t *testing.T) {
+	r := httptest.NewRequest("POST", "/login", nil)
+	r.RemoteAddr = "10.0.0.1:54321"
+	r.Header.Set("X-Forwarded-For", "6.6.6.6, 203.0.113.7")
+
+	cfg := &config.Config{}
+	mw := middleware.New(cfg, nil, nil, nil)
+
+	if ip := mw.ClientIP(r); ip != "10.0.0.1" {
+		t.Errorf("Expected the remote address without trusted proxies, got %s", ip)
+	}
+
+	cfg.TrustProxyHeaders = true
+	if ip := mw.ClientIP(r); ip != "203.0.113.7" {
+		t.Errorf("Expected the address added by the proxy, got %s", ip)
+	}
+
+	r.Header.Set("X-Forwarded-For", "not-an-ip")
+	if ip := mw.ClientIP(r); ip != "10.0.0.1" {
+		t.Errorf("Expected a malformed header to be ignored, got %s", ip)
+	}
+}
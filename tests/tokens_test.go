@@ -0,0 +1,94 @@
+// tests/tokens_test.go - Unit tests for personal API token helpers
+package tests
+
+import (
+	"context"
+	"go-web-app/app/middleware"
+	"go-web-app/app/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestTokenScopes tests scope implication rules
+func TestTokenScopes(t *testing.T) {
+	testCases := []struct {
+		granted  []string
+		required string
+		allowed  bool
+	}{
+		{[]string{"read"}, "read", true},
+		{[]string{"read"}, "write", false},
+		{[]string{"write"}, "read", true},
+		{[]string{"write"}, "admin", false},
+		{[]string{"admin"}, "write", true},
+		{[]string{"admin"}, "read", true},
+		{[]string{}, "read", false},
+	}
+
+	for _, tc := range testCases {
+		allowed := models.ScopesAllow(tc.granted, tc.required)
+		if allowed != tc.allowed {
+			t.Errorf("Scopes %v requiring '%s': expected allowed=%v, got %v",
+				tc.granted, tc.required, tc.allowed, allowed)
+		}
+	}
+}
+
+// TestTokenHashing tests that token hashes are stable and do not leak the token
+func TestTokenHashing(t *testing.T) {
+	plain := models.APITokenPrefix + "abc123"
+
+	hash := models.HashAPIToken(plain)
+	if len(hash) != 64 {
+		t.Errorf("Expected 64 character hash, got %d", len(hash))
+	}
+
+	if hash != models.HashAPIToken(plain) {
+		t.Error("Expected hashing to be deterministic")
+	}
+
+	if strings.Contains(hash, "abc123") {
+		t.Error("Expected hash not to contain the plaintext token")
+	}
+}
+
+// TestRequirePermissionTokenScope tests that a token needs the admin scope to
+// use its owner's admin permissions, while sessions are not scope-restricted
+func TestRequirePermissionTokenScope(t *testing.T) {
+	admin := &models.User{ID: 1, Permissions: map[string]bool{models.PermUsersManage: true, models.PermBlogsCreate: true}}
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	testCases := []struct {
+		name       string
+		path       string
+		permission string
+		token      *models.APIToken
+		expected   int
+	}{
+		{"session", "/dashboard/users/2/delete", models.PermUsersManage, nil, http.StatusOK},
+		{"write token on admin route", "/dashboard/users/2/delete", models.PermUsersManage, &models.APIToken{Scopes: []string{"write"}}, http.StatusForbidden},
+		{"write token on API admin route", "/api/v1/users", models.PermUsersManage, &models.APIToken{Scopes: []string{"write"}}, http.StatusForbidden},
+		{"admin token on admin route", "/dashboard/users/2/delete", models.PermUsersManage, &models.APIToken{Scopes: []string{"admin"}}, http.StatusOK},
+		{"write token on author route", "/api/v1/blogs", models.PermBlogsCreate, &models.APIToken{Scopes: []string{"write"}}, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), "user", admin)
+			if tc.token != nil {
+				ctx = context.WithValue(ctx, "api_token", tc.token)
+			}
+			r := httptest.NewRequest("POST", tc.path, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			middleware.RequirePermission(tc.permission, ok)(w, r)
+			if w.Code != tc.expected {
+				t.Errorf("Expected status %d, got %d", tc.expected, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// tests/totp_test.go - Unit tests for TOTP two-factor codes
+package tests
+
+import (
+	"go-web-app/app/services"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 test key from RFC 6238 ("12345678901234567890"), base32 encoded
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestTOTPCode tests codes against the RFC 6238 SHA1 vectors (last six of the eight digits)
+func TestTOTPCode(t *testing.T) {
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := services.TOTPCode(rfc6238Secret, services.TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode() error: %v", err)
+		}
+		if code != tt.expected {
+			t.Errorf("TOTPCode(T=%d) = %s, expected %s", tt.unix, code, tt.expected)
+		}
+	}
+}
+
+// TestValidateTOTP tests clock skew, spacing and the returned time step
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := services.TOTPStep(now)
+
+	tests := []struct {
+		name     string
+		offset   int64
+		expected bool
+	}{
+		{"Current step", 0, true},
+		{"One step behind", -1, true},
+		{"One step ahead", 1, true},
+		{"Two steps behind", -2, false},
+		{"Two steps ahead", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _ := services.TOTPCode(rfc6238Secret, step+tt.offset)
+			got, ok := services.ValidateTOTP(rfc6238Secret, code, now)
+			if ok != tt.expected {
+				t.Fatalf("ValidateTOTP() = %v, expected %v", ok, tt.expected)
+			}
+			if ok && got != step+tt.offset {
+				t.Errorf("ValidateTOTP() step = %d, expected %d", got, step+tt.offset)
+			}
+		})
+	}
+
+	if _, ok := services.ValidateTOTP(rfc6238Secret, "005 924", now); !ok {
+		t.Error("Expected a code with a space to be accepted")
+	}
+	if _, ok := services.ValidateTOTP(rfc6238Secret, "", now); ok {
+		t.Error("Expected an empty code to be rejected")
+	}
+}
+
+// TestGenerateTOTPSecret tests that secrets are unique and usable
+func TestGenerateTOTPSecret(t *testing.T) {
+	first, err := services.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error: %v", err)
+	}
+	second, _ := services.GenerateTOTPSecret()
+
+	if first == second {
+		t.Error("Expected two secrets to differ")
+	}
+	if len(first) != 32 {
+		t.Errorf("Expected a 32-character secret, got %d", len(first))
+	}
+	if _, err := services.TOTPCode(first, 1); err != nil {
+		t.Errorf("Expected a generated secret to produce codes: %v", err)
+	}
+}
+
+// TestTOTPProvisioningURI tests the otpauth:// URI scanned by authenticator apps
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := services.TOTPProvisioningURI("Go Blog", "jane@example.com", rfc6238Secret)
+
+	for _, want := range []string{"otpauth://totp/Go%20Blog:jane@example.com?", "secret=" + rfc6238Secret, "issuer=Go+Blog", "digits=6", "period=30"} {
+		if !strings.Contains(uri, want) {
+			t.Errorf("Expected %q to contain %q", uri, want)
+		}
+	}
+}
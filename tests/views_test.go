@@ -0,0 +1,202 @@
+// tests/views_test.go - Unit tests for the template registry
+package tests
+
+import (
+	"bytes"
+	"go-web-app/app/views"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testLayouts is a one-layout setup for registries built in a temp dir
+var testLayouts = map[string]views.Layout{
+	"main": {File: "layouts/main.html", Name: "main", Partials: []string{"banner"}},
+}
+
+// writeTemplates writes files (path relative to dir => content) into dir
+func writeTemplates(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// baseTemplates is a valid layout, partial and page
+func baseTemplates() map[string]string {
+	return map[string]string{
+		"layouts/main.html":      `{{define "main"}}<main>{{template "banner" .}}{{template "content" .}}</main>{{end}}`,
+		"components/banner.html": `{{define "banner"}}[{{csrfToken}}]{{end}}`,
+		"components/card.html":   `{{define "card"}}<div>{{.}}</div>{{end}}`,
+		"home.html":              `{{define "content"}}{{template "card" .Title}}{{end}}`,
+	}
+}
+
+// TestViewsParseAppTemplates tests that every page the app declares parses
+// with its layout and partials
+func TestViewsParseAppTemplates(t *testing.T) {
+	if _, err := views.New("../templates", views.Layouts, views.Pages, false); err != nil {
+		t.Fatalf("Expected the app's templates to parse, got %v", err)
+	}
+}
+
+// TestViewsRender tests rendering a page in its layout with per-render helpers
+func TestViewsRender(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplates(t, dir, baseTemplates())
+
+	registry, err := views.New(dir, testLayouts, map[string]views.Page{
+		"home": {Layout: "main", Partials: []string{"card"}},
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to parse templates: %v", err)
+	}
+
+	for _, token := range []string{"first", "second"} {
+		var buf bytes.Buffer
+		token := token
+		err := registry.Render(&buf, "home", map[string]string{"Title": "Hello"}, template.FuncMap{
+			"csrfToken": func() string { return token },
+		})
+		if err != nil {
+			t.Fatalf("Failed to render: %v", err)
+		}
+		if expected := "<main>[" + token + "]<div>Hello</div></main>"; buf.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, buf.String())
+		}
+	}
+
+	if err := registry.Render(&bytes.Buffer{}, "missing", nil, nil); err == nil {
+		t.Error("Expected an error for an undeclared page")
+	}
+}
+
+// TestViewsRenderConcurrently tests that renders sharing a page each use their
+// own helpers, with or without replacing them
+func TestViewsRenderConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplates(t, dir, baseTemplates())
+
+	registry, err := views.New(dir, testLayouts, map[string]views.Page{
+		"home": {Layout: "main", Partials: []string{"card"}},
+	}, false)
+	if err != nil {
+		t.Fatalf("Failed to parse templates: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var funcs template.FuncMap
+			token := ""
+			if i%5 != 0 {
+				token = "token-" + strconv.Itoa(i)
+				funcs = template.FuncMap{"csrfToken": func() string { return token }}
+			}
+
+			var buf bytes.Buffer
+			if err := registry.Render(&buf, "home", map[string]string{"Title": "Hello"}, funcs); err != nil {
+				t.Errorf("Failed to render: %v", err)
+				return
+			}
+			if expected := "<main>[" + token + "]<div>Hello</div></main>"; buf.String() != expected {
+				t.Errorf("Expected %q, got %q", expected, buf.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestViewsFailFast tests that broken templates are reported when the
+// registry is built rather than when a page is rendered
+func TestViewsFailFast(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		page     views.Page
+		expected string
+	}{
+		{
+			name:     "syntax error",
+			files:    map[string]string{"home.html": `{{define "content"}}{{if .Title}}{{end}}`},
+			page:     views.Page{Layout: "main", Partials: []string{"card"}},
+			expected: "home.html",
+		},
+		{
+			name:     "unknown layout",
+			page:     views.Page{Layout: "sidebar", Partials: []string{"card"}},
+			expected: `unknown layout "sidebar"`,
+		},
+		{
+			name:     "undeclared partial",
+			page:     views.Page{Layout: "main"},
+			expected: `undefined template "card"`,
+		},
+		{
+			name:     "missing partial file",
+			page:     views.Page{Layout: "main", Partials: []string{"card", "sidebar"}},
+			expected: "sidebar.html",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTemplates(t, dir, baseTemplates())
+			writeTemplates(t, dir, tt.files)
+
+			_, err := views.New(dir, testLayouts, map[string]views.Page{"home": tt.page}, false)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error mentioning %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestViewsReload tests that changed files are only picked up with reload on
+func TestViewsReload(t *testing.T) {
+	for _, reload := range []bool{false, true} {
+		dir := t.TempDir()
+		writeTemplates(t, dir, baseTemplates())
+
+		registry, err := views.New(dir, testLayouts, map[string]views.Page{
+			"home": {Layout: "main", Partials: []string{"card"}},
+		}, reload)
+		if err != nil {
+			t.Fatalf("Failed to parse templates: %v", err)
+		}
+
+		// Edit the partial, dated ahead so the change is seen on coarse filesystem clocks
+		writeTemplates(t, dir, map[string]string{"components/card.html": `{{define "card"}}<p>{{.}}</p>{{end}}`})
+		later := time.Now().Add(time.Minute)
+		if err := os.Chtimes(filepath.Join(dir, "components/card.html"), later, later); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := registry.Render(&buf, "home", map[string]string{"Title": "Hello"}, nil); err != nil {
+			t.Fatalf("Failed to render: %v", err)
+		}
+
+		edited := strings.Contains(buf.String(), "<p>Hello</p>")
+		if edited != reload {
+			t.Errorf("reload=%v: expected the edit to be picked up: %v, got %q", reload, reload, buf.String())
+		}
+	}
+}